@@ -1,21 +1,42 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
 
 	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/cli"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
-	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/server"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/sms"
 )
 
 func main() {
+	commands := cli.App{Out: os.Stdout, Load: load}
+	if err := commands.Run(os.Args[1:]); err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+}
+
+// load wires the container every command shares with the HTTP server.
+func load() (*app.Container, error) {
 	conf, err := config.NewConfig()
 	if err != nil {
-		log.Fatalf("Config error: %s", err)
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+	db, err := database.Connect(conf.DB)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	client, err := redis.Connect(conf.Redis)
+	if err != nil {
+		return nil, fmt.Errorf("redis error: %w", err)
 	}
-	err = database.StartDB()
+	smsRouter, err := sms.Connect(conf.SMS)
 	if err != nil {
-		log.Fatalf("Database error: %s", err)
+		return nil, fmt.Errorf("sms error: %w", err)
 	}
-	server.Run(conf)
+	return app.New(conf, db, client, smsRouter)
 }
@@ -2,8 +2,7 @@ package config
 
 import (
 	"fmt"
-	"os"
-	"strings"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
@@ -11,9 +10,17 @@ import (
 
 type (
 	Config struct {
-		APP  `yaml:"app"`
-		HTTP `yaml:"http"`
-		DB   `yaml:"db"`
+		APP             `yaml:"app"`
+		HTTP            `yaml:"http"`
+		DB              `yaml:"db"`
+		OTP             `yaml:"otp"`
+		OAuth           `yaml:"oauth"`
+		LoginMonitor    `yaml:"login_monitor"`
+		Impersonation   `yaml:"impersonation"`
+		Audit           `yaml:"audit"`
+		AccountDeletion `yaml:"account_deletion"`
+		Locations       `yaml:"locations"`
+		SMS             `yaml:"sms"`
 		Redis
 	}
 	APP struct {
@@ -27,15 +34,70 @@ type (
 	}
 
 	DB struct {
-		Host     string `env-required:"true" env:"POSTGRES_HOST"`
-		Username string `env-required:"true" env:"POSTGRES_USER"`
-		Password string `env-required:"true" env:"POSTGRES_PASSWORD"`
-		DB       string `env-required:"true" env:"POSTGRES_DB"`
+		Host             string        `env-required:"true" env:"POSTGRES_HOST"`
+		Port             int           `yaml:"port" env:"POSTGRES_PORT" env-default:"5432"`
+		Username         string        `env-required:"true" env:"POSTGRES_USER"`
+		Password         string        `env-required:"true" env:"POSTGRES_PASSWORD"`
+		DB               string        `env-required:"true" env:"POSTGRES_DB"`
+		SSLMode          string        `yaml:"ssl_mode" env:"POSTGRES_SSL_MODE" env-default:"disable"`
+		TimeZone         string        `yaml:"timezone" env:"POSTGRES_TIMEZONE" env-default:"UTC"`
+		SearchPath       string        `yaml:"search_path" env:"POSTGRES_SEARCH_PATH" env-default:"public"`
+		MaxOpenConns     int           `yaml:"max_open_conns" env:"POSTGRES_MAX_OPEN_CONNS" env-default:"10"`
+		MaxIdleConns     int           `yaml:"max_idle_conns" env:"POSTGRES_MAX_IDLE_CONNS" env-default:"2"`
+		ConnMaxLifetime  time.Duration `yaml:"conn_max_lifetime" env:"POSTGRES_CONN_MAX_LIFETIME" env-default:"30m"`
+		ConnMaxIdleTime  time.Duration `yaml:"conn_max_idle_time" env:"POSTGRES_CONN_MAX_IDLE_TIME" env-default:"5m"`
+		StatementTimeout time.Duration `yaml:"statement_timeout" env:"POSTGRES_STATEMENT_TIMEOUT" env-default:"30s"`
 	}
 
 	Redis struct {
 		Url string `env-required:"true" env:"REDIS_URL"`
 	}
+
+	SMS struct {
+		// Provider delivers text messages: http posts them to URL, console
+		// only logs them, codes included, and is meant for development
+		Provider string        `yaml:"provider" env:"SMS_PROVIDER"`
+		URL      string        `yaml:"url" env:"SMS_URL"`
+		APIKey   string        `env:"SMS_API_KEY"`
+		Timeout  time.Duration `yaml:"timeout" env:"SMS_TIMEOUT" env-default:"10s"`
+	}
+
+	OTP struct {
+		Length int           `yaml:"length" env:"OTP_LENGTH" env-default:"6"`
+		TTL    time.Duration `yaml:"ttl" env:"OTP_TTL" env-default:"1m"`
+	}
+
+	OAuth struct {
+		CodeTTL         time.Duration `yaml:"code_ttl" env:"OAUTH_CODE_TTL" env-default:"5m"`
+		AccessTokenTTL  time.Duration `yaml:"access_token_ttl" env:"OAUTH_ACCESS_TOKEN_TTL" env-default:"1h"`
+		RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env:"OAUTH_REFRESH_TOKEN_TTL" env-default:"720h"`
+	}
+
+	LoginMonitor struct {
+		FailureBurst  int           `yaml:"failure_burst" env:"LOGIN_FAILURE_BURST" env-default:"5"`
+		FailureWindow time.Duration `yaml:"failure_window" env:"LOGIN_FAILURE_WINDOW" env-default:"15m"`
+	}
+
+	Impersonation struct {
+		TTL time.Duration `yaml:"ttl" env:"IMPERSONATION_TTL" env-default:"30m"`
+	}
+
+	Audit struct {
+		Retention     time.Duration `yaml:"retention" env:"AUDIT_RETENTION" env-default:"8760h"`
+		PurgeInterval time.Duration `yaml:"purge_interval" env:"AUDIT_PURGE_INTERVAL" env-default:"24h"`
+	}
+
+	AccountDeletion struct {
+		GracePeriod   time.Duration `yaml:"grace_period" env:"ACCOUNT_DELETION_GRACE_PERIOD" env-default:"720h"`
+		SweepInterval time.Duration `yaml:"sweep_interval" env:"ACCOUNT_DELETION_SWEEP_INTERVAL" env-default:"1h"`
+	}
+
+	Locations struct {
+		// StateDeletion is what deleting a state that still has cities does
+		// when the request doesn't say: reject or cascade. Reassign needs a
+		// target state, so it can only be chosen per request
+		StateDeletion string `yaml:"state_deletion" env:"STATE_DELETION" env-default:"reject"`
+	}
 )
 
 func NewConfig() (*Config, error) {
@@ -54,12 +116,3 @@ func NewConfig() (*Config, error) {
 	}
 	return conf, nil
 }
-
-func InTestMode() bool {
-	for _, arg := range os.Args {
-		if strings.HasPrefix(arg, "-test.") {
-			return true
-		}
-	}
-	return false
-}
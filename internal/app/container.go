@@ -0,0 +1,132 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/handlers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/middlewares"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/geodata"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/sms"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Container is built once when the application starts. It owns the
+// database and redis connections and the use cases built on them, which
+// the handlers, middlewares and background jobs share.
+type Container struct {
+	Config *config.Config
+	DB     *gorm.DB
+	Redis  *redis.Client
+	SMS    *sms.Router
+
+	Users          usecase.UserUseCase
+	OTP            usecase.OTPUseCase
+	States         usecase.StateUseCase
+	Cities         usecase.CityUseCase
+	Districts      usecase.DistrictUseCase
+	Neighborhoods  usecase.NeighborhoodUseCase
+	Roles          usecase.RoleUseCase
+	APIKeys        usecase.APIKeyUseCase
+	OAuth          usecase.OAuthUseCase
+	TwoFactor      usecase.TwoFactorUseCase
+	Suspensions    usecase.SuspensionUseCase
+	LoginEvents    usecase.LoginEventUseCase
+	Impersonations usecase.ImpersonationUseCase
+	AuditLogs      usecase.AuditLogUseCase
+	Accounts       usecase.AccountUseCase
+	Diagnostics    usecase.DiagnosticsUseCase
+	GeoData        usecase.GeoDataUseCase
+	Locations      usecase.LocationUseCase
+}
+
+// New builds the container. It fails when the configuration can't be used.
+func New(conf *config.Config, db *gorm.DB, client *redis.Client, smsRouter *sms.Router) (*Container, error) {
+	if err := usecase.ValidateDefaultDeletionMode(conf.StateDeletion); err != nil {
+		return nil, fmt.Errorf("STATE_DELETION: %w", err)
+	}
+	userRepo := repository.NewUserRepository(db)
+	auditLogs := usecase.NewAuditLogUseCase(repository.NewAuditLogRepository(db))
+	roleUseCase := usecase.NewCachedRoleUseCase(repository.NewRoleRepository(db), repository.NewRoleCacheRepository(client))
+	oauthUseCase := usecase.NewOAuthUseCase(
+		repository.NewOAuthClientRepository(db),
+		repository.NewOAuthTokenRepository(client),
+		userRepo,
+		conf.OAuth,
+		conf.SecretKey,
+	)
+	stateRepo := repository.NewStateRepository(db)
+	cityRepo := repository.NewCityRepository(db)
+	districtRepo := repository.NewDistrictRepository(db)
+	neighborhoodRepo := repository.NewNeighborhoodRepository(db)
+	geoDataRepo := repository.NewGeoDataRepository(db)
+	accountUseCase := usecase.NewAccountUseCase(repository.NewAccountRepository(db), userRepo, conf.AccountDeletion)
+	return &Container{
+		Config: conf,
+		DB:     db,
+		Redis:  client,
+		SMS:    smsRouter,
+
+		Users:          usecase.NewUserUseCase(userRepo).WithAudit(auditLogs),
+		OTP:            usecase.NewOTPCase(repository.NewOTPCodeRepository(client), conf.OTP),
+		States:         usecase.NewStateUseCase(stateRepo).WithAudit(auditLogs).WithDeletionMode(conf.StateDeletion),
+		Cities:         usecase.NewCityUseCase(cityRepo).WithAudit(auditLogs),
+		Districts:      usecase.NewDistrictUseCase(districtRepo).WithAudit(auditLogs),
+		Neighborhoods:  usecase.NewNeighborhoodUseCase(neighborhoodRepo).WithAudit(auditLogs),
+		Roles:          roleUseCase.WithAudit(auditLogs),
+		APIKeys:        usecase.NewAPIKeyUseCase(repository.NewAPIKeyRepository(db), repository.NewRateLimitRepository(client)),
+		OAuth:          oauthUseCase,
+		TwoFactor:      usecase.NewTwoFactorUseCase(repository.NewTwoFactorRepository(db)),
+		Suspensions:    usecase.NewSuspensionUseCase(repository.NewSuspensionRepository(db), userRepo),
+		LoginEvents:    usecase.NewLoginEventUseCase(repository.NewLoginEventRepository(db), conf.LoginMonitor),
+		Impersonations: usecase.NewImpersonationUseCase(repository.NewImpersonationRepository(db), conf.Impersonation, conf.SecretKey),
+		AuditLogs:      auditLogs,
+		Accounts:       accountUseCase.WithAudit(auditLogs),
+		Diagnostics:    usecase.NewDiagnosticsUseCase(repository.NewDiagnosticsRepository(db), conf.DB),
+		GeoData:        usecase.NewGeoDataUseCase(geoDataRepo, geodata.Iran()).WithAudit(auditLogs),
+		Locations:      usecase.NewLocationUseCase(stateRepo, cityRepo, districtRepo, neighborhoodRepo, geoDataRepo).WithAudit(auditLogs),
+	}, nil
+}
+
+// Handler returns the HTTP handlers wired to the container's use cases.
+func (c *Container) Handler() handlers.Handler {
+	return handlers.Handler{
+		Users:          c.Users,
+		OTP:            c.OTP,
+		States:         c.States,
+		Cities:         c.Cities,
+		Districts:      c.Districts,
+		Neighborhoods:  c.Neighborhoods,
+		Roles:          c.Roles,
+		APIKeys:        c.APIKeys,
+		OAuth:          c.OAuth,
+		TwoFactor:      c.TwoFactor,
+		Suspensions:    c.Suspensions,
+		LoginEvents:    c.LoginEvents,
+		Impersonations: c.Impersonations,
+		Audit:          c.AuditLogs,
+		Accounts:       c.Accounts,
+		Diagnostics:    c.Diagnostics,
+		GeoData:        c.GeoData,
+		Locations:      c.Locations,
+		SMS:            c.SMS,
+		SecretKey:      c.Config.SecretKey,
+	}
+}
+
+// Middleware returns the authentication middlewares wired to the
+// container's use cases.
+func (c *Container) Middleware() middlewares.Middleware {
+	return middlewares.Middleware{
+		Users:          c.Users,
+		Roles:          c.Roles,
+		APIKeys:        c.APIKeys,
+		OAuth:          c.OAuth,
+		Impersonations: c.Impersonations,
+		Audit:          c.AuditLogs,
+		SecretKey:      c.Config.SecretKey,
+	}
+}
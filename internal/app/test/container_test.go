@@ -0,0 +1,47 @@
+package app_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/sms"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	container := app.TestContainer()
+	assert.Same(t, database.TestDb(), container.DB)
+
+	user := entity.NewUser("something", "09120000001", entity.UserRole)
+	assert.NoError(t, container.Users.Repo.Save(&user))
+	handler := container.Handler()
+	ctx := context.WithValue(context.TODO(), "userId", user.ID)
+	assert.NoError(t, handler.Users.Update(ctx, user.ID, map[string]any{"full_name": "Jane Doe"}))
+
+	logs, count, err := container.AuditLogs.List(context.TODO(), repository.AuditLogFilter{TargetType: "user"}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, entity.UpdateUserAction, logs[0].Action)
+}
+
+func TestNew_RejectsDefaultDeletionMode(t *testing.T) {
+	for _, mode := range []string{"reassign", "", "drop"} {
+		conf := &config.Config{Locations: config.Locations{StateDeletion: mode}}
+		container, err := app.New(conf, database.TestDb(), redis.TestClient(), sms.NewRouter(sms.ConsoleSender{}))
+		assert.ErrorIs(t, err, usecase.ErrInvalidDefaultMode, mode)
+		assert.Nil(t, container)
+	}
+	conf := &config.Config{Locations: config.Locations{StateDeletion: entity.CascadeDeletion}}
+	container, err := app.New(conf, database.TestDb(), redis.TestClient(), sms.NewRouter(sms.ConsoleSender{}))
+	assert.NoError(t, err)
+	assert.Equal(t, entity.CascadeDeletion, container.States.DeletionMode)
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/sms"
+)
+
+// TestSecretKey signs the tokens of the test container.
+const TestSecretKey = "secretestkey"
+
+// TestContainer builds a container on the test database and redis client.
+// Text messages are printed instead of sent.
+func TestContainer() *Container {
+	conf := &config.Config{
+		APP:             config.APP{SecretKey: TestSecretKey},
+		OTP:             config.OTP{Length: 6, TTL: time.Minute},
+		OAuth:           config.OAuth{CodeTTL: 5 * time.Minute, AccessTokenTTL: time.Hour, RefreshTokenTTL: 720 * time.Hour},
+		LoginMonitor:    config.LoginMonitor{FailureBurst: 3, FailureWindow: 15 * time.Minute},
+		Impersonation:   config.Impersonation{TTL: 30 * time.Minute},
+		Audit:           config.Audit{Retention: 8760 * time.Hour, PurgeInterval: 24 * time.Hour},
+		AccountDeletion: config.AccountDeletion{GracePeriod: 720 * time.Hour, SweepInterval: time.Hour},
+		Locations:       config.Locations{StateDeletion: "reject"},
+	}
+	smsRouter := sms.NewRouter(sms.ConsoleSender{Name: "international"})
+	smsRouter.Register("IR", sms.ConsoleSender{Name: "local"})
+	container, err := New(conf, database.TestDb(), redis.TestClient(), smsRouter)
+	if err != nil {
+		panic(err)
+	}
+	return container
+}
@@ -0,0 +1,22 @@
+package cli
+
+import "fmt"
+
+// cache flush drops the cached roles. Everything else in redis, such as OTP
+// codes, rate limits and OAuth tokens and revocations, is state rather than
+// cache and is left alone.
+func (a App) cache(args []string) error {
+	if len(args) != 1 || args[0] != "flush" {
+		return fmt.Errorf("%w: cache needs the flush subcommand", ErrUsage)
+	}
+	c, err := a.Load()
+	if err != nil {
+		return err
+	}
+	keys, err := c.Roles.FlushCache(a.context())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.Out, "removed %v keys\n", keys)
+	return nil
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/server"
+)
+
+var ErrUsage = errors.New("invalid usage")
+
+const usage = `usage: <command> [arguments]
+
+commands:
+  serve                                       start the HTTP server (default)
+  migrate up | down [steps] | status          apply, revert or list migrations
+  migrate create <name>                       add empty migration files
+  seed                                        create the built-in roles
+  seed geo                                    create the bundled provinces and counties
+  user create-admin --mobile <number> [--name <full name>]
+  user set-role --mobile <number> --role <role>
+  token issue --mobile <number>               print an access token for a user
+  cache flush                                 drop the cached roles
+`
+
+// App runs the management commands. Commands share the container the HTTP
+// server is built from, loaded only by the commands that need it.
+type App struct {
+	Out  io.Writer
+	Load func() (*app.Container, error)
+}
+
+// Run executes the command named by args. Without arguments it serves.
+func (a App) Run(args []string) error {
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+	var err error
+	switch args[0] {
+	case "serve":
+		err = a.serve()
+	case "migrate":
+		err = a.migrate(args[1:])
+	case "seed":
+		err = a.seed(args[1:])
+	case "user":
+		err = a.user(args[1:])
+	case "token":
+		err = a.token(args[1:])
+	case "cache":
+		err = a.cache(args[1:])
+	case "help", "-h", "--help":
+		fmt.Fprint(a.Out, usage)
+	default:
+		err = fmt.Errorf("%w: unknown command %q", ErrUsage, args[0])
+	}
+	if errors.Is(err, ErrUsage) {
+		fmt.Fprint(a.Out, usage)
+	}
+	return err
+}
+
+func (a App) serve() error {
+	c, err := a.Load()
+	if err != nil {
+		return err
+	}
+	if err = database.StartDB(c.DB); err != nil {
+		return err
+	}
+	server.Run(c)
+	return nil
+}
+
+func (a App) seed(args []string) error {
+	if len(args) > 1 || len(args) == 1 && args[0] != "geo" {
+		return fmt.Errorf("%w: seed takes no argument but geo", ErrUsage)
+	}
+	c, err := a.Load()
+	if err != nil {
+		return err
+	}
+	if err = database.CheckSchema(c.DB); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		if err = database.SeedRoles(c.DB); err != nil {
+			return err
+		}
+		fmt.Fprintln(a.Out, "built-in roles seeded")
+		return nil
+	}
+	result, err := c.GeoData.Seed(a.context())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.Out, "dataset %v: created %v states and %v cities, linked %v states and %v cities\n",
+		result.Version, result.CreatedStates, result.CreatedCities, result.LinkedStates, result.LinkedCities)
+	return nil
+}
+
+// flags returns a flag set for a subcommand that reports its errors as
+// ErrUsage instead of exiting.
+func flagSet(name string) *flag.FlagSet {
+	set := flag.NewFlagSet(name, flag.ContinueOnError)
+	set.SetOutput(io.Discard)
+	return set
+}
+
+func parseFlags(set *flag.FlagSet, args []string, required ...string) error {
+	if err := set.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+	missing := []string{}
+	for _, name := range required {
+		if set.Lookup(name).Value.String() == "" {
+			missing = append(missing, "--"+name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %v %v required", ErrUsage, set.Name(), strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// context is what the commands pass to the use cases. Mutations made from
+// the command line are audited without an actor and with "cli" as their
+// request id.
+func (a App) context() context.Context {
+	return context.WithValue(context.Background(), "requestId", "cli")
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+)
+
+func (a App) migrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: migrate needs a subcommand", ErrUsage)
+	}
+	if args[0] == "create" {
+		if len(args) != 2 {
+			return fmt.Errorf("%w: migrate create needs a name", ErrUsage)
+		}
+		up, down, err := database.CreateMigration(database.MigrationsDir, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.Out, "created %v\ncreated %v\n", up, down)
+		return nil
+	}
+	if args[0] != "up" && args[0] != "down" && args[0] != "status" {
+		return fmt.Errorf("%w: unknown migrate subcommand %q", ErrUsage, args[0])
+	}
+	steps := 1
+	if args[0] == "down" && len(args) == 2 {
+		var err error
+		steps, err = strconv.Atoi(args[1])
+		if err != nil || steps < 1 {
+			return fmt.Errorf("%w: steps must be a positive number", ErrUsage)
+		}
+	} else if len(args) > 1 {
+		return fmt.Errorf("%w: too many arguments", ErrUsage)
+	}
+	c, err := a.Load()
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp(c.DB)
+		for _, migration := range applied {
+			fmt.Fprintf(a.Out, "applied %v\n", migration)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(a.Out, "schema is up to date")
+		}
+		return err
+	case "down":
+		reverted, err := database.MigrateDown(c.DB, steps)
+		for _, migration := range reverted {
+			fmt.Fprintf(a.Out, "reverted %v\n", migration)
+		}
+		return err
+	}
+	statuses, err := database.MigrationStatuses(c.DB)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(a.Out, "%-40v %v\n", status, appliedAt)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/cli"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func newApp() (cli.App, *bytes.Buffer, *app.Container) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	container := app.TestContainer()
+	out := &bytes.Buffer{}
+	load := func() (*app.Container, error) { return container, nil }
+	return cli.App{Out: out, Load: load}, out, container
+}
+
+func TestUserCommands(t *testing.T) {
+	commands, out, container := newApp()
+
+	err := commands.Run([]string{"user", "create-admin"})
+	assert.ErrorIs(t, err, cli.ErrUsage)
+	assert.Contains(t, out.String(), "usage:")
+
+	assert.NoError(t, commands.Run([]string{"user", "create-admin", "--mobile", "09120000001", "--name", "Jane Doe"}))
+	assert.Contains(t, out.String(), "created admin +989120000001")
+	admin, err := container.Users.GetUserById(1)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.AdminRole, admin.Role)
+	assert.Equal(t, "Jane Doe", admin.FullName)
+	assert.NoError(t, commands.Run([]string{"user", "create-admin", "--mobile", "+989120000001"}))
+	count, _ := container.Users.Count()
+	assert.Equal(t, 1, count)
+
+	err = commands.Run([]string{"user", "set-role", "--mobile", "09120000001", "--role", entity.UserRole})
+	assert.ErrorIs(t, err, usecase.ErrLastAdmin)
+	err = commands.Run([]string{"user", "set-role", "--mobile", "09120000001", "--role", "nobody"})
+	assert.Error(t, err)
+	err = commands.Run([]string{"user", "set-role", "--mobile", "09129999999", "--role", entity.UserRole})
+	assert.ErrorIs(t, err, cli.ErrUserNotFound)
+
+	user, err := container.Users.GetUserOrCreate("+989120000002")
+	assert.NoError(t, err)
+	assert.NoError(t, commands.Run([]string{"user", "create-admin", "--mobile", "09120000002"}))
+	assert.NoError(t, commands.Run([]string{"user", "set-role", "--mobile", "09120000001", "--role", entity.UserRole}))
+	demoted, _ := container.Users.GetUserById(admin.ID)
+	assert.Equal(t, entity.UserRole, demoted.Role)
+	promoted, _ := container.Users.GetUserById(user.ID)
+	assert.Equal(t, entity.AdminRole, promoted.Role)
+
+	logs, err := container.AuditLogs.ByTarget(context.TODO(), "user", admin.ID)
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, "cli", logs[0].RequestID)
+}
+
+func TestTokenIssue(t *testing.T) {
+	commands, out, container := newApp()
+	assert.ErrorIs(t, commands.Run([]string{"token", "issue", "--mobile", "09120000001"}), cli.ErrUserNotFound)
+
+	_, err := container.Users.GetUserOrCreate("+989120000001")
+	assert.NoError(t, err)
+	out.Reset()
+	assert.NoError(t, commands.Run([]string{"token", "issue", "--mobile", "09120000001"}))
+	assert.Regexp(t, `^ey[\w-]+\.[\w-]+\.[\w-]+\n$`, out.String())
+}
+
+func TestCacheFlush(t *testing.T) {
+	commands, out, container := newApp()
+	ctx := context.TODO()
+	_, err := container.Roles.ByName(ctx, entity.AdminRole)
+	assert.NoError(t, err)
+	_, err = container.Roles.ByName(ctx, entity.UserRole)
+	assert.NoError(t, err)
+	assert.NoError(t, container.OAuth.Tokens.RevokeAccessToken(ctx, "revoked-jti", time.Hour))
+	_, err = container.OTP.GenerateCode(ctx, "+989120000001", entity.LoginPurpose)
+	assert.NoError(t, err)
+
+	assert.NoError(t, commands.Run([]string{"cache", "flush"}))
+	assert.Contains(t, out.String(), "removed 2 keys")
+	revoked, err := container.OAuth.Tokens.IsAccessTokenRevoked(ctx, "revoked-jti")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	_, err = container.OTP.Repo.GetCode(ctx, "+989120000001", entity.LoginPurpose)
+	assert.NoError(t, err)
+}
+
+func TestMigrateAndSeed(t *testing.T) {
+	commands, out, _ := newApp()
+	assert.NoError(t, commands.Run([]string{"migrate", "status"}))
+	assert.Regexp(t, `0001_baseline\s+\d{4}-`, out.String())
+	assert.NoError(t, commands.Run([]string{"migrate", "up"}))
+	assert.Contains(t, out.String(), "schema is up to date")
+	assert.ErrorIs(t, commands.Run([]string{"migrate", "down", "zero"}), cli.ErrUsage)
+	assert.NoError(t, commands.Run([]string{"seed"}))
+	assert.ErrorIs(t, commands.Run([]string{"unknown"}), cli.ErrUsage)
+}
+
+func TestSeedGeo(t *testing.T) {
+	commands, out, _ := newApp()
+	assert.NoError(t, commands.Run([]string{"seed", "geo"}))
+	assert.Contains(t, out.String(), "created 31 states")
+	out.Reset()
+	assert.NoError(t, commands.Run([]string{"seed", "geo"}))
+	assert.Contains(t, out.String(), "created 0 states and 0 cities, linked 0 states and 0 cities")
+	assert.ErrorIs(t, commands.Run([]string{"seed", "everything"}), cli.ErrUsage)
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+)
+
+// token issues access tokens for debugging without going through the OTP
+// login.
+func (a App) token(args []string) error {
+	if len(args) == 0 || args[0] != "issue" {
+		return fmt.Errorf("%w: token needs the issue subcommand", ErrUsage)
+	}
+	set := flagSet("issue")
+	mobile := set.String("mobile", "", "mobile number")
+	if err := parseFlags(set, args[1:], "mobile"); err != nil {
+		return err
+	}
+	c, err := a.Load()
+	if err != nil {
+		return err
+	}
+	user, err := userByMobileNumber(c.Users.Repo, *mobile)
+	if err != nil {
+		return err
+	}
+	token, err := utils.GenerateAccessToken(c.Config.SecretKey, user.ID, user.MobileNumber, user.Role)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(a.Out, token)
+	return nil
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/validators"
+	"gorm.io/gorm"
+)
+
+var ErrUserNotFound = errors.New("no user has this mobile number")
+
+func (a App) user(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: user needs a subcommand", ErrUsage)
+	}
+	switch args[0] {
+	case "create-admin":
+		return a.createAdmin(args[1:])
+	case "set-role":
+		return a.setRole(args[1:])
+	}
+	return fmt.Errorf("%w: unknown user subcommand %q", ErrUsage, args[0])
+}
+
+// createAdmin is how the first admin of a fresh database is made. An
+// existing user with the mobile number is promoted instead.
+func (a App) createAdmin(args []string) error {
+	set := flagSet("create-admin")
+	mobile := set.String("mobile", "", "mobile number")
+	name := set.String("name", "", "full name")
+	if err := parseFlags(set, args, "mobile"); err != nil {
+		return err
+	}
+	c, err := a.Load()
+	if err != nil {
+		return err
+	}
+	user, created, err := c.Users.MakeAdmin(a.context(), *name, *mobile)
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Fprintf(a.Out, "created admin %v with id %v\n", user.MobileNumber, user.ID)
+	} else {
+		fmt.Fprintf(a.Out, "user %v with id %v is an admin\n", user.MobileNumber, user.ID)
+	}
+	return nil
+}
+
+func (a App) setRole(args []string) error {
+	set := flagSet("set-role")
+	mobile := set.String("mobile", "", "mobile number")
+	role := set.String("role", "", "role name")
+	if err := parseFlags(set, args, "mobile", "role"); err != nil {
+		return err
+	}
+	c, err := a.Load()
+	if err != nil {
+		return err
+	}
+	ctx := a.context()
+	if !c.Roles.DoesRoleExist(ctx, *role) {
+		return fmt.Errorf("role %q does not exist", *role)
+	}
+	user, err := userByMobileNumber(c.Users.Repo, *mobile)
+	if err != nil {
+		return err
+	}
+	if err = c.Users.CheckRoleChange(0, user, *role, true); err != nil {
+		return err
+	}
+	if err = c.Users.Update(ctx, user.ID, map[string]any{"role": *role}); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.Out, "user %v with id %v is now %v\n", user.MobileNumber, user.ID, *role)
+	return nil
+}
+
+func userByMobileNumber(repo repository.UserRepository, mobileNumber string) (entity.User, error) {
+	normalized, err := validators.NormalizeMobileNumber(mobileNumber)
+	if err != nil {
+		return entity.User{}, err
+	}
+	user := entity.User{}
+	err = repo.ByMobileNumber(normalized, &user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.User{}, ErrUserNotFound
+	}
+	return user, err
+}
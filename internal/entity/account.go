@@ -0,0 +1,21 @@
+package entity
+
+import "fmt"
+
+// AccountExport is everything stored about a user, as handed to them when
+// they ask for a copy of their data.
+type AccountExport struct {
+	User           User
+	LoginEvents    []LoginEvent
+	Suspensions    []Suspension
+	APIKeys        []APIKey
+	TwoFactor      *TwoFactor
+	Impersonations []Impersonation
+	AuditLogs      []AuditLog
+}
+
+// AnonymizedMobileNumber replaces the mobile number of an anonymized user.
+// It is unique per user and can never be a real number.
+func AnonymizedMobileNumber(userId uint) string {
+	return fmt.Sprintf("anonymized-%v", userId)
+}
@@ -0,0 +1,126 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ChangeMobileNumberAction    string = "users.change_mobile_number"
+	IssueAPIKeyAction           string = "api_keys.issue"
+	RevokeAPIKeyAction          string = "api_keys.revoke"
+	ResetTwoFactorAction        string = "users.reset_two_factor"
+	RequireTwoFactorAction      string = "roles.require_two_factor"
+	SuspendUserAction           string = "users.suspend"
+	UnsuspendUserAction         string = "users.unsuspend"
+	ReviewLoginAction           string = "logins.review"
+	StartImpersonationAction    string = "impersonation.start"
+	EndImpersonationAction      string = "impersonation.end"
+	ImpersonatedRequestAction   string = "impersonation.request"
+	CreateUserAction            string = "users.create"
+	UpdateUserAction            string = "users.update"
+	DeleteUserAction            string = "users.delete"
+	CreateRoleAction            string = "roles.create"
+	UpdateRolePermissionsAction string = "roles.update_permissions"
+	DeleteRoleAction            string = "roles.delete"
+	CreateStateAction           string = "states.create"
+	UpdateStateAction           string = "states.update"
+	DeleteStateAction           string = "states.delete"
+	CreateCityAction            string = "cities.create"
+	UpdateCityAction            string = "cities.update"
+	DeleteCityAction            string = "cities.delete"
+	RestoreUserAction           string = "users.restore"
+	PurgeUserAction             string = "users.purge"
+	RestoreStateAction          string = "states.restore"
+	PurgeStateAction            string = "states.purge"
+	RestoreCityAction           string = "cities.restore"
+	PurgeCityAction             string = "cities.purge"
+	RequestDeletionAction       string = "users.request_deletion"
+	CancelDeletionAction        string = "users.cancel_deletion"
+	AnonymizeUserAction         string = "users.anonymize"
+	SeedGeoDataAction           string = "geo_data.seed"
+	ImportLocationsAction       string = "locations.import"
+	CreateDistrictAction        string = "districts.create"
+	UpdateDistrictAction        string = "districts.update"
+	DeleteDistrictAction        string = "districts.delete"
+	CreateNeighborhoodAction    string = "neighborhoods.create"
+	UpdateNeighborhoodAction    string = "neighborhoods.update"
+	DeleteNeighborhoodAction    string = "neighborhoods.delete"
+	TranslateStateAction        string = "states.update_translations"
+	TranslateCityAction         string = "cities.update_translations"
+)
+
+var ErrAuditLogImmutable = errors.New("audit logs can not be changed")
+
+// RedactedValue replaces personal data that is scrubbed from audit logs.
+const RedactedValue = "[redacted]"
+
+// PersonalUserFields are the User fields anonymizing an account erases, so
+// they are scrubbed from the account's audit history as well.
+var PersonalUserFields = []string{"FullName", "MobileNumber", "Country", "SuspensionReason"}
+
+// AuditLog is written once and never changed. Changes holds a JSON object of
+// the fields a mutation touched, each with its before and after value.
+type AuditLog struct {
+	gorm.Model
+	ActorID    uint   `gorm:"index"`
+	Action     string `gorm:"index"`
+	TargetType string `gorm:"index:idx_audit_target"`
+	TargetID   uint   `gorm:"index:idx_audit_target"`
+	Details    string
+	Changes    string
+	IP         string
+	RequestID  string
+}
+
+func NewAuditLog(actorId uint, action, targetType string, targetId uint, details string) AuditLog {
+	return AuditLog{
+		ActorID:    actorId,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetId,
+		Details:    details,
+	}
+}
+
+func (AuditLog) BeforeUpdate(*gorm.DB) error {
+	return ErrAuditLogImmutable
+}
+
+// BeforeDelete keeps entries from being removed one by one; the retention
+// purge deletes in bulk with hooks skipped.
+func (AuditLog) BeforeDelete(*gorm.DB) error {
+	return ErrAuditLogImmutable
+}
+
+// Redact returns the entry with the before and after values of fields
+// replaced by RedactedValue. Details of mobile number changes hold both
+// numbers, so they are replaced as a whole.
+func (auditLog AuditLog) Redact(fields []string) (AuditLog, error) {
+	if auditLog.Action == ChangeMobileNumberAction {
+		auditLog.Details = RedactedValue
+	}
+	if auditLog.Changes == "" {
+		return auditLog, nil
+	}
+	changes := map[string]map[string]any{}
+	if err := json.Unmarshal([]byte(auditLog.Changes), &changes); err != nil {
+		return auditLog, err
+	}
+	for _, field := range fields {
+		change, ok := changes[field]
+		if !ok {
+			continue
+		}
+		for side, value := range change {
+			if value != nil {
+				change[side] = RedactedValue
+			}
+		}
+	}
+	encoded, err := json.Marshal(changes)
+	auditLog.Changes = string(encoded)
+	return auditLog, err
+}
@@ -1,14 +1,30 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+	"gorm.io/gorm"
+)
 
 type City struct {
 	gorm.Model
 	Title   string
-	StateID uint
-	State   State `gorm:"foreignKey:StateID;references:ID"`
+	StateID uint `gorm:"uniqueIndex:idx_cities_title_key,priority:1,where:deleted_at IS NULL"`
+	// TitleKey is the folded title, see utils.FoldTitle. It keeps two cities
+	// of a state from having the same title.
+	TitleKey string `gorm:"uniqueIndex:idx_cities_title_key,priority:2" json:"-"`
+	State    State  `gorm:"foreignKey:StateID;references:ID"`
+	Location `gorm:"embedded"`
+	// Code links the city to the bundled geographic dataset, empty for
+	// cities created by hand
+	Code         string            `gorm:"uniqueIndex:idx_cities_code,where:code <> '' AND deleted_at IS NULL"`
+	Translations []CityTranslation `gorm:"foreignKey:CityID;constraint:OnDelete:CASCADE"`
 }
 
 func NewCity(title string, state State) City {
 	return City{Title: title, State: state}
 }
+
+func (city *City) BeforeSave(*gorm.DB) error {
+	city.TitleKey = utils.FoldTitle(city.Title)
+	return nil
+}
@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DatabaseDiagnostics describes the database connection pool: its current
+// statistics, the limits it was configured with and how long a ping took.
+type DatabaseDiagnostics struct {
+	Stats            sql.DBStats
+	PingLatency      time.Duration
+	MaxIdleConns     int
+	ConnMaxLifetime  time.Duration
+	ConnMaxIdleTime  time.Duration
+	StatementTimeout time.Duration
+}
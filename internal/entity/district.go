@@ -0,0 +1,27 @@
+package entity
+
+import "gorm.io/gorm"
+
+// District divides a large city, e.g. the municipal districts of Tehran.
+type District struct {
+	gorm.Model
+	Title  string
+	CityID uint `gorm:"index"`
+	City   City `gorm:"foreignKey:CityID;references:ID"`
+}
+
+func NewDistrict(title string, city City) District {
+	return District{Title: title, City: city, CityID: city.ID}
+}
+
+// Neighborhood is the smallest location guests search by.
+type Neighborhood struct {
+	gorm.Model
+	Title      string
+	DistrictID uint     `gorm:"index"`
+	District   District `gorm:"foreignKey:DistrictID;references:ID"`
+}
+
+func NewNeighborhood(title string, district District) Neighborhood {
+	return Neighborhood{Title: title, District: district, DistrictID: district.ID}
+}
@@ -0,0 +1,71 @@
+package entity
+
+// GeoDataset is the bundled list of provinces and their counties. Codes are
+// stable across versions, titles are Persian.
+type GeoDataset struct {
+	Version string         `json:"version"`
+	States  []DatasetState `json:"states"`
+}
+
+type DatasetState struct {
+	Code         string        `json:"code"`
+	Title        string        `json:"title"`
+	EnglishTitle string        `json:"english_title"`
+	Cities       []DatasetCity `json:"cities"`
+}
+
+type DatasetCity struct {
+	Code         string `json:"code"`
+	Title        string `json:"title"`
+	EnglishTitle string `json:"english_title"`
+}
+
+// GeoDiff describes how the states and cities in the database differ from a
+// dataset. Records match by code, or by title when they have no code yet.
+type GeoDiff struct {
+	Version       string
+	MissingStates []DatasetState
+	ChangedStates []GeoChange
+	UnknownStates []State
+	MissingCities []MissingCity
+	ChangedCities []GeoChange
+	UnknownCities []City
+	MatchedStates int
+	MatchedCities int
+}
+
+type MissingCity struct {
+	StateCode string
+	City      DatasetCity
+}
+
+// GeoChange is a record that matches the dataset but has a different title
+// or is not linked to it by code yet.
+type GeoChange struct {
+	ID           uint
+	Code         string
+	Title        string
+	DatasetTitle string
+	Unlinked     bool
+}
+
+func (diff GeoDiff) InSync() bool {
+	return len(diff.MissingStates) == 0 && len(diff.ChangedStates) == 0 && len(diff.UnknownStates) == 0 &&
+		len(diff.MissingCities) == 0 && len(diff.ChangedCities) == 0 && len(diff.UnknownCities) == 0
+}
+
+// GeoSeed is a state to write while seeding, with the cities under it to
+// write. Save is false for states that only get new cities.
+type GeoSeed struct {
+	State  State
+	Save   bool
+	Cities []City
+}
+
+type GeoSeedResult struct {
+	Version       string
+	CreatedStates int
+	LinkedStates  int
+	CreatedCities int
+	LinkedCities  int
+}
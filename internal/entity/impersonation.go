@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Impersonation is a support session in which ActorID acts as SubjectID.
+type Impersonation struct {
+	gorm.Model
+	ActorID   uint `gorm:"index"`
+	SubjectID uint `gorm:"index"`
+	Reason    string
+	ExpiresAt time.Time
+	EndedAt   *time.Time
+}
+
+func NewImpersonation(actorId, subjectId uint, reason string, ttl time.Duration) Impersonation {
+	return Impersonation{
+		ActorID:   actorId,
+		SubjectID: subjectId,
+		Reason:    reason,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+}
+
+func (impersonation Impersonation) IsActive(now time.Time) bool {
+	return impersonation.EndedAt == nil && now.Before(impersonation.ExpiresAt)
+}
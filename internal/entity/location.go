@@ -0,0 +1,40 @@
+package entity
+
+import "github.com/TheAmirhosssein/room-reservation-api/pkg/geo"
+
+// Location places a state or city on the map. Both coordinates are set or
+// neither is; Boundary optionally holds its outline as a GeoJSON Polygon or
+// MultiPolygon geometry.
+type Location struct {
+	Latitude  *float64
+	Longitude *float64
+	Boundary  string
+}
+
+func (location Location) HasCoordinates() bool {
+	return location.Latitude != nil && location.Longitude != nil
+}
+
+func (location Location) Validate() error {
+	if (location.Latitude == nil) != (location.Longitude == nil) {
+		return geo.ErrInvalidPoint
+	}
+	if location.HasCoordinates() {
+		if err := geo.ValidatePoint(*location.Latitude, *location.Longitude); err != nil {
+			return err
+		}
+	}
+	if location.Boundary != "" {
+		if _, err := geo.NormalizeBoundary([]byte(location.Boundary)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NearbyCity is a city found around a point, Distance kilometers away from
+// it.
+type NearbyCity struct {
+	City     City
+	Distance float64
+}
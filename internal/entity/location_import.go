@@ -0,0 +1,40 @@
+package entity
+
+const (
+	// ImportByTitle matches imported states by title and cities by title
+	// within their state
+	ImportByTitle string = "title"
+	// ImportByCode matches imported states and cities by their code
+	ImportByCode string = "code"
+)
+
+// LocationRow is one line of an import: a city with its state, or a state
+// alone when the city fields are empty. Location tells the uploader where
+// the row is in their file.
+type LocationRow struct {
+	Location   string
+	StateCode  string
+	StateTitle string
+	CityCode   string
+	CityTitle  string
+}
+
+func (row LocationRow) HasCity() bool {
+	return row.CityCode != "" || row.CityTitle != ""
+}
+
+type ImportError struct {
+	Location string
+	Field    string
+	Message  string
+}
+
+type ImportResult struct {
+	DryRun        bool
+	Rows          int
+	CreatedStates int
+	UpdatedStates int
+	CreatedCities int
+	UpdatedCities int
+	Errors        []ImportError
+}
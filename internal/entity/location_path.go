@@ -0,0 +1,40 @@
+package entity
+
+// PathNode is an ancestor of a location. Paths list them from the state
+// down, so a neighborhood's path is its state, city and district.
+type PathNode struct {
+	Type  string
+	ID    uint
+	Title string
+}
+
+// Path needs the state preloaded for its title.
+func (city City) Path() []PathNode {
+	return []PathNode{{Type: "state", ID: city.StateID, Title: city.State.Title}}
+}
+
+// Path needs the city and its state preloaded for their titles.
+func (district District) Path() []PathNode {
+	return append(district.City.Path(), PathNode{Type: "city", ID: district.CityID, Title: district.City.Title})
+}
+
+// Path needs the district, its city and state preloaded for their titles.
+func (neighborhood Neighborhood) Path() []PathNode {
+	return append(neighborhood.District.Path(), PathNode{Type: "district", ID: neighborhood.DistrictID, Title: neighborhood.District.Title})
+}
+
+// LocationTree is a state with every city, district and neighborhood under it.
+type LocationTree struct {
+	State  State
+	Cities []CityTree
+}
+
+type CityTree struct {
+	City      City
+	Districts []DistrictTree
+}
+
+type DistrictTree struct {
+	District      District
+	Neighborhoods []Neighborhood
+}
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	OTPRequestedEvent   string = "otp_requested"
+	LoginSucceededEvent string = "login_succeeded"
+	LoginFailedEvent    string = "login_failed"
+)
+
+const (
+	NewDeviceAnomaly      string = "new_device"
+	FailureBurstAnomaly   string = "failure_burst"
+	UnusualCountryAnomaly string = "unusual_country"
+)
+
+// LoginEvent is one step of a login attempt. Events with anomalies wait in
+// the review queue until someone from support reviews them.
+type LoginEvent struct {
+	gorm.Model
+	UserID       uint   `gorm:"index"`
+	MobileNumber string `gorm:"index"`
+	Type         string
+	IP           string
+	UserAgent    string
+	DeviceHash   string
+	Country      string
+	Anomalies    string
+	ReviewedAt   *time.Time
+	ReviewedBy   *uint
+	ReviewNote   string
+}
+
+func NewLoginEvent(userId uint, mobileNumber, eventType, ip, userAgent string) LoginEvent {
+	return LoginEvent{
+		UserID:       userId,
+		MobileNumber: mobileNumber,
+		Type:         eventType,
+		IP:           ip,
+		UserAgent:    userAgent,
+		DeviceHash:   sha256Hex(userAgent),
+	}
+}
+
+func (event *LoginEvent) AddAnomaly(anomaly string) {
+	event.Anomalies = strings.Join(append(event.AnomalyList(), anomaly), ",")
+}
+
+func (event LoginEvent) AnomalyList() []string {
+	if event.Anomalies == "" {
+		return []string{}
+	}
+	return strings.Split(event.Anomalies, ",")
+}
+
+func (event LoginEvent) IsSuspicious() bool {
+	return event.Anomalies != ""
+}
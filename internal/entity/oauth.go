@@ -0,0 +1,189 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// ProfileScope lets a third-party app read and edit the profile of the
+	// user who authorized it. Every other scope is a permission name.
+	ProfileScope = "profile"
+
+	AuthorizationCodeGrant = "authorization_code"
+	ClientCredentialsGrant = "client_credentials"
+	RefreshTokenGrant      = "refresh_token"
+
+	PKCEMethodS256 = "S256"
+
+	oauthClientIdLength     = 16
+	oauthClientSecretLength = 32
+	oauthTokenLength        = 32
+)
+
+// OAuthClient is a third-party application registered to use the OAuth2
+// endpoints. Public clients, such as mobile apps, have no secret and can
+// only use the authorization code grant. Scopes and redirect URIs are
+// stored space separated, the way OAuth2 transmits scopes.
+type OAuthClient struct {
+	gorm.Model
+	Name         string
+	ClientID     string `gorm:"uniqueIndex"`
+	HashedSecret string
+	RedirectURIs string
+	Scope        string
+}
+
+// OAuthAuthorizationCode is handed to the client after the user consents and
+// exchanged for tokens by the same client, proving possession of the PKCE
+// verifier.
+type OAuthAuthorizationCode struct {
+	Code                string `json:"-"`
+	ClientID            string `json:"client_id"`
+	UserID              uint   `json:"user_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	Scope               string `json:"scope"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+}
+
+type OAuthRefreshToken struct {
+	Token    string `json:"-"`
+	ClientID string `json:"client_id"`
+	UserID   uint   `json:"user_id"`
+	Scope    string `json:"scope"`
+}
+
+// NewOAuthClient registers a client and returns its plain text secret, which
+// is empty for public clients and never stored.
+func NewOAuthClient(name string, redirectURIs, scopes []string, confidential bool) (OAuthClient, string, error) {
+	clientId, err := randomHex(oauthClientIdLength)
+	if err != nil {
+		return OAuthClient{}, "", err
+	}
+	client := OAuthClient{
+		Name:         name,
+		ClientID:     clientId,
+		RedirectURIs: strings.Join(redirectURIs, " "),
+		Scope:        JoinScopes(scopes),
+	}
+	if !confidential {
+		return client, "", nil
+	}
+	secret, err := randomHex(oauthClientSecretLength)
+	if err != nil {
+		return OAuthClient{}, "", err
+	}
+	client.HashedSecret = sha256Hex(secret)
+	return client, secret, nil
+}
+
+func NewOAuthAuthorizationCode(clientId string, userId uint, redirectURI, scope, codeChallenge, codeChallengeMethod string) (OAuthAuthorizationCode, error) {
+	code, err := randomHex(oauthTokenLength)
+	if err != nil {
+		return OAuthAuthorizationCode{}, err
+	}
+	return OAuthAuthorizationCode{
+		Code:                code,
+		ClientID:            clientId,
+		UserID:              userId,
+		RedirectURI:         redirectURI,
+		Scope:               scope,
+		CodeChallenge:       codeChallenge,
+		CodeChallengeMethod: codeChallengeMethod,
+	}, nil
+}
+
+func NewOAuthRefreshToken(clientId string, userId uint, scope string) (OAuthRefreshToken, error) {
+	token, err := randomHex(oauthTokenLength)
+	if err != nil {
+		return OAuthRefreshToken{}, err
+	}
+	return OAuthRefreshToken{Token: token, ClientID: clientId, UserID: userId, Scope: scope}, nil
+}
+
+func (client OAuthClient) IsConfidential() bool {
+	return client.HashedSecret != ""
+}
+
+func (client OAuthClient) MatchSecret(secret string) bool {
+	return client.IsConfidential() &&
+		subtle.ConstantTimeCompare([]byte(sha256Hex(secret)), []byte(client.HashedSecret)) == 1
+}
+
+func (client OAuthClient) RedirectURIList() []string {
+	return strings.Fields(client.RedirectURIs)
+}
+
+// AllowsRedirectURI requires an exact match with a registered URI.
+func (client OAuthClient) AllowsRedirectURI(redirectURI string) bool {
+	for _, allowed := range client.RedirectURIList() {
+		if allowed == redirectURI {
+			return true
+		}
+	}
+	return false
+}
+
+func (client OAuthClient) ScopeList() []string {
+	return SplitScope(client.Scope)
+}
+
+// AllowsScopes reports whether every scope was granted to the client at
+// registration.
+func (client OAuthClient) AllowsScopes(scopes []string) bool {
+	for _, scope := range scopes {
+		if !containsString(client.ScopeList(), scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifyCodeChallenge checks a PKCE verifier against the challenge sent to
+// the authorize endpoint. Only S256 is supported.
+func (code OAuthAuthorizationCode) VerifyCodeChallenge(verifier string) bool {
+	if code.CodeChallengeMethod != PKCEMethodS256 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(challenge), []byte(code.CodeChallenge)) == 1
+}
+
+func IsScopeValid(scope string) bool {
+	return scope == ProfileScope || IsPermissionValid(scope)
+}
+
+func SplitScope(scope string) []string {
+	return strings.Fields(scope)
+}
+
+func JoinScopes(scopes []string) string {
+	var unique []string
+	for _, scope := range scopes {
+		if !containsString(unique, scope) {
+			unique = append(unique, scope)
+		}
+	}
+	return strings.Join(unique, " ")
+}
+
+// OAuthTokenHash is the form authorization codes and refresh tokens are
+// stored under.
+func OAuthTokenHash(token string) string {
+	return sha256Hex(token)
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
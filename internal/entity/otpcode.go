@@ -1,19 +1,97 @@
 package entity
 
 import (
-	"math/rand"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
+	"time"
 )
 
+type OTPPurpose string
+
+const (
+	LoginPurpose         OTPPurpose = "login"
+	ChangeMobilePurpose  OTPPurpose = "change_mobile"
+	DeleteAccountPurpose OTPPurpose = "delete_account"
+)
+
+const otpSaltLength = 16
+
 type OTPCode struct {
 	MobileNumber string
+	Purpose      OTPPurpose
 	Code         string
+	TTL          time.Duration
 }
 
-func NewOtpCode(mobileNumber string) OTPCode {
-	randomNumber := rand.Intn(900000) + 100000
+func NewOtpCode(mobileNumber string, purpose OTPPurpose, length int, ttl time.Duration) (OTPCode, error) {
+	if length <= 0 {
+		return OTPCode{}, errors.New("otp code length must be positive")
+	}
+	// the first digit is never zero so the code keeps its length when
+	// clients treat it as a number
+	var builder strings.Builder
+	for i := 0; i < length; i++ {
+		max, offset := int64(10), int64(0)
+		if i == 0 {
+			max, offset = 9, 1
+		}
+		digit, err := rand.Int(rand.Reader, big.NewInt(max))
+		if err != nil {
+			return OTPCode{}, err
+		}
+		builder.WriteString(strconv.FormatInt(digit.Int64()+offset, 10))
+	}
 	return OTPCode{
 		MobileNumber: mobileNumber,
-		Code:         strconv.Itoa(randomNumber),
+		Purpose:      purpose,
+		Code:         builder.String(),
+		TTL:          ttl,
+	}, nil
+}
+
+// Key is the namespaced redis key of the code, so codes issued for one
+// purpose can never be looked up for another.
+func (otp OTPCode) Key() string {
+	return OTPKey(otp.MobileNumber, otp.Purpose)
+}
+
+// Hash returns the salted hash of the code in "salt$hash" form, which is
+// the only representation of the code that should ever be persisted.
+func (otp OTPCode) Hash() (string, error) {
+	salt := make([]byte, otpSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
 	}
+	return hashOTPCode(salt, otp.Code), nil
+}
+
+func OTPKey(mobileNumber string, purpose OTPPurpose) string {
+	return fmt.Sprintf("otp:%v:%v", purpose, mobileNumber)
+}
+
+// MatchOTPCode reports whether code matches a hash produced by OTPCode.Hash,
+// comparing in constant time.
+func MatchOTPCode(hashedCode, code string) bool {
+	saltHex, _, found := strings.Cut(hashedCode, "$")
+	if !found {
+		return false
+	}
+	salt, err := hex.DecodeString(saltHex)
+	if err != nil {
+		return false
+	}
+	expected := hashOTPCode(salt, code)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(hashedCode)) == 1
+}
+
+func hashOTPCode(salt []byte, code string) string {
+	sum := sha256.Sum256(append(append([]byte{}, salt...), code...))
+	return hex.EncodeToString(salt) + "$" + hex.EncodeToString(sum[:])
 }
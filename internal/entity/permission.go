@@ -0,0 +1,89 @@
+package entity
+
+const (
+	UsersReadPermission              string = "users.read"
+	UsersUpdatePermission            string = "users.update"
+	UsersUpdateRolePermission        string = "users.update_role"
+	UsersDeletePermission            string = "users.delete"
+	UsersChangeMobilePermission      string = "users.change_mobile_number"
+	UsersResetTwoFactorPermission    string = "users.reset_two_factor"
+	UsersSuspendPermission           string = "users.suspend"
+	UsersImpersonatePermission       string = "users.impersonate"
+	RolesReadPermission              string = "roles.read"
+	RolesWritePermission             string = "roles.write"
+	SettingsStatesWritePermission    string = "settings.states.write"
+	SettingsCitiesWritePermission    string = "settings.cities.write"
+	SettingsDistrictsWritePermission string = "settings.districts.write"
+	APIKeysReadPermission            string = "api_keys.read"
+	APIKeysWritePermission           string = "api_keys.write"
+	OAuthClientsReadPermission       string = "oauth_clients.read"
+	OAuthClientsWritePermission      string = "oauth_clients.write"
+	LoginsReadPermission             string = "logins.read"
+	LoginsReviewPermission           string = "logins.review"
+	AuditLogsReadPermission          string = "audit_logs.read"
+	TrashPurgePermission             string = "trash.purge"
+	DiagnosticsReadPermission        string = "diagnostics.read"
+)
+
+var AllPermissions = []string{
+	UsersReadPermission,
+	UsersUpdatePermission,
+	UsersUpdateRolePermission,
+	UsersDeletePermission,
+	UsersChangeMobilePermission,
+	UsersResetTwoFactorPermission,
+	UsersSuspendPermission,
+	UsersImpersonatePermission,
+	RolesReadPermission,
+	RolesWritePermission,
+	SettingsStatesWritePermission,
+	SettingsCitiesWritePermission,
+	SettingsDistrictsWritePermission,
+	APIKeysReadPermission,
+	APIKeysWritePermission,
+	OAuthClientsReadPermission,
+	OAuthClientsWritePermission,
+	LoginsReadPermission,
+	LoginsReviewPermission,
+	AuditLogsReadPermission,
+	TrashPurgePermission,
+	DiagnosticsReadPermission,
+}
+
+// DefaultRolePermissions are the permission sets the built-in roles are
+// seeded with. Admin always holds every permission.
+var DefaultRolePermissions = map[string][]string{
+	AdminRole: AllPermissions,
+	SupportRole: {
+		UsersReadPermission,
+		UsersUpdatePermission,
+		UsersUpdateRolePermission,
+		UsersDeletePermission,
+		UsersChangeMobilePermission,
+		UsersSuspendPermission,
+		UsersImpersonatePermission,
+		RolesReadPermission,
+		SettingsStatesWritePermission,
+		SettingsCitiesWritePermission,
+		SettingsDistrictsWritePermission,
+		APIKeysReadPermission,
+		OAuthClientsReadPermission,
+		LoginsReadPermission,
+		LoginsReviewPermission,
+	},
+	UserRole: {},
+}
+
+func IsPermissionValid(permission string) bool {
+	for _, validPermission := range AllPermissions {
+		if validPermission == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func IsDefaultRole(name string) bool {
+	_, ok := DefaultRolePermissions[name]
+	return ok
+}
@@ -0,0 +1,66 @@
+package entity
+
+import "gorm.io/gorm"
+
+type Role struct {
+	gorm.Model
+	Name             string `gorm:"unique"`
+	RequireTwoFactor bool
+	Permissions      []RolePermission `gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE"`
+}
+
+type RolePermission struct {
+	ID         uint   `gorm:"primarykey"`
+	RoleID     uint   `gorm:"uniqueIndex:idx_role_permission"`
+	Permission string `gorm:"uniqueIndex:idx_role_permission"`
+}
+
+func NewRole(name string, permissions []string) Role {
+	role := Role{Name: name}
+	role.SetPermissions(permissions)
+	return role
+}
+
+func (role *Role) SetPermissions(permissions []string) {
+	role.Permissions = nil
+	for _, permission := range permissions {
+		if role.HasPermission(permission) {
+			continue
+		}
+		role.Permissions = append(role.Permissions, RolePermission{RoleID: role.ID, Permission: permission})
+	}
+}
+
+func (role Role) PermissionNames() []string {
+	names := []string{}
+	for _, permission := range role.Permissions {
+		names = append(names, permission.Permission)
+	}
+	return names
+}
+
+func (role Role) HasPermission(permission string) bool {
+	for _, rolePermission := range role.Permissions {
+		if rolePermission.Permission == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers reports whether role holds every permission of other, which is
+// required to hand other out to somebody else.
+func (role Role) Covers(other Role) bool {
+	for _, permission := range other.Permissions {
+		if !role.HasPermission(permission.Permission) {
+			return false
+		}
+	}
+	return true
+}
+
+// RequiresTwoFactor reports whether users of the role have to log in with a
+// second factor. Admin and Support always do.
+func (role Role) RequiresTwoFactor() bool {
+	return role.RequireTwoFactor || role.Name == AdminRole || role.Name == SupportRole
+}
@@ -1,12 +1,54 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// What deleting a state does with the cities it still has.
+const (
+	// RejectDeletion refuses to delete the state.
+	RejectDeletion = "reject"
+	// CascadeDeletion deletes the cities, their districts and neighborhoods
+	// along with the state.
+	CascadeDeletion = "cascade"
+	// ReassignDeletion moves the cities to another state first.
+	ReassignDeletion = "reassign"
+)
+
+func IsDeletionModeValid(mode string) bool {
+	return mode == RejectDeletion || mode == CascadeDeletion || mode == ReassignDeletion
+}
 
 type State struct {
 	gorm.Model
 	Title string
+	// TitleKey is the folded title, see utils.FoldTitle. It keeps two states
+	// from having the same title.
+	TitleKey string `gorm:"uniqueIndex:idx_states_title_key,where:deleted_at IS NULL" json:"-"`
+	Location `gorm:"embedded"`
+	// Code links the state to the bundled geographic dataset, empty for
+	// states created by hand
+	Code         string             `gorm:"uniqueIndex:idx_states_code,where:code <> '' AND deleted_at IS NULL"`
+	Translations []StateTranslation `gorm:"foreignKey:StateID;constraint:OnDelete:CASCADE"`
 }
 
 func NewState(title string) State {
 	return State{Title: title}
 }
+
+func (state *State) BeforeSave(*gorm.DB) error {
+	state.TitleKey = utils.FoldTitle(state.Title)
+	return nil
+}
+
+// WithTitleKey adds the folded title to an update of a state or city that
+// changes its title, under either the column or the field name.
+func WithTitleKey(newInfo map[string]any) map[string]any {
+	for _, key := range []string{"title", "Title"} {
+		if title, ok := newInfo[key].(string); ok {
+			newInfo["title_key"] = utils.FoldTitle(title)
+		}
+	}
+	return newInfo
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	FraudSuspensionReason      string = "fraud"
+	AbuseSuspensionReason      string = "abuse"
+	SpamSuspensionReason       string = "spam"
+	ChargebackSuspensionReason string = "chargeback"
+	OtherSuspensionReason      string = "other"
+)
+
+var SuspensionReasons = []string{
+	FraudSuspensionReason,
+	AbuseSuspensionReason,
+	SpamSuspensionReason,
+	ChargebackSuspensionReason,
+	OtherSuspensionReason,
+}
+
+// Suspension is one entry of a user's suspension history. Notes are
+// internal and never shown to the suspended user.
+type Suspension struct {
+	gorm.Model
+	UserID    uint `gorm:"index"`
+	ActorID   uint
+	Reason    string
+	Notes     string
+	EndsAt    *time.Time
+	LiftedAt  *time.Time
+	LiftedBy  *uint
+	LiftNotes string
+}
+
+func NewSuspension(userId, actorId uint, reason, notes string, endsAt *time.Time) Suspension {
+	return Suspension{UserID: userId, ActorID: actorId, Reason: reason, Notes: notes, EndsAt: endsAt}
+}
+
+func IsSuspensionReasonValid(reason string) bool {
+	for _, validReason := range SuspensionReasons {
+		if validReason == reason {
+			return true
+		}
+	}
+	return false
+}
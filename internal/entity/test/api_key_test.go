@@ -0,0 +1,41 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyEntity_New(t *testing.T) {
+	apiKey, rawKey, err := entity.NewAPIKey("partner", nil, "Agency", []string{entity.UsersReadPermission, entity.UsersReadPermission}, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.DefaultAPIKeyRateLimit, apiKey.RateLimit)
+	assert.Equal(t, []string{entity.UsersReadPermission}, apiKey.ScopeNames())
+	assert.NotContains(t, apiKey.HashedKey, rawKey)
+
+	prefix, ok := entity.APIKeyPrefix(rawKey)
+	assert.True(t, ok)
+	assert.Equal(t, apiKey.Prefix, prefix)
+	assert.True(t, apiKey.Matches(rawKey))
+	assert.False(t, apiKey.Matches(rawKey+"0"))
+
+	_, ok = entity.APIKeyPrefix("not-a-key")
+	assert.False(t, ok)
+}
+
+func TestAPIKeyEntity_IsActive(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	apiKey, _, _ := entity.NewAPIKey("partner", nil, "Agency", nil, 10, nil)
+	assert.True(t, apiKey.IsActive(now))
+	apiKey.ExpiresAt = &future
+	assert.True(t, apiKey.IsActive(now))
+	apiKey.ExpiresAt = &past
+	assert.False(t, apiKey.IsActive(now))
+	apiKey.ExpiresAt = nil
+	apiKey.RevokedAt = &past
+	assert.False(t, apiKey.IsActive(now))
+}
@@ -0,0 +1,19 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImpersonationIsActive(t *testing.T) {
+	impersonation := entity.NewImpersonation(1, 2, "ticket 42", time.Minute)
+	assert.True(t, impersonation.IsActive(time.Now()))
+	assert.False(t, impersonation.IsActive(time.Now().Add(2*time.Minute)))
+
+	endedAt := time.Now()
+	impersonation.EndedAt = &endedAt
+	assert.False(t, impersonation.IsActive(time.Now()))
+}
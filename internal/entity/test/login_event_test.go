@@ -0,0 +1,21 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginEventAnomalies(t *testing.T) {
+	event := entity.NewLoginEvent(1, "+989120000001", entity.LoginSucceededEvent, "2.180.0.1", "curl/8.0")
+	assert.NotEmpty(t, event.DeviceHash)
+	assert.NotEqual(t, event.DeviceHash, entity.NewLoginEvent(1, "", "", "", "curl/8.1").DeviceHash)
+	assert.False(t, event.IsSuspicious())
+	assert.Empty(t, event.AnomalyList())
+
+	event.AddAnomaly(entity.NewDeviceAnomaly)
+	event.AddAnomaly(entity.UnusualCountryAnomaly)
+	assert.True(t, event.IsSuspicious())
+	assert.Equal(t, []string{entity.NewDeviceAnomaly, entity.UnusualCountryAnomaly}, event.AnomalyList())
+}
@@ -0,0 +1,44 @@
+package entity_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOAuthClientEntity(t *testing.T) {
+	client, secret, err := entity.NewOAuthClient("app", []string{"https://app.example/callback"}, []string{entity.ProfileScope, entity.UsersReadPermission, entity.ProfileScope}, true)
+	assert.NoError(t, err)
+	assert.True(t, client.IsConfidential())
+	assert.True(t, client.MatchSecret(secret))
+	assert.False(t, client.MatchSecret(secret+"0"))
+	assert.Equal(t, []string{entity.ProfileScope, entity.UsersReadPermission}, client.ScopeList())
+	assert.True(t, client.AllowsScopes([]string{entity.ProfileScope}))
+	assert.False(t, client.AllowsScopes([]string{entity.UsersDeletePermission}))
+	assert.True(t, client.AllowsRedirectURI("https://app.example/callback"))
+	assert.False(t, client.AllowsRedirectURI("https://app.example/callback/other"))
+
+	publicClient, secret, err := entity.NewOAuthClient("mobile", []string{"app://callback"}, []string{entity.ProfileScope}, false)
+	assert.NoError(t, err)
+	assert.Empty(t, secret)
+	assert.False(t, publicClient.IsConfidential())
+	assert.False(t, publicClient.MatchSecret(""))
+}
+
+func TestOAuthAuthorizationCode_VerifyCodeChallenge(t *testing.T) {
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	code, err := entity.NewOAuthAuthorizationCode("client", 1, "app://callback", entity.ProfileScope, challenge, entity.PKCEMethodS256)
+	assert.NoError(t, err)
+	assert.True(t, code.VerifyCodeChallenge(verifier))
+	assert.False(t, code.VerifyCodeChallenge("wrong"))
+
+	code.CodeChallengeMethod = "plain"
+	code.CodeChallenge = verifier
+	assert.False(t, code.VerifyCodeChallenge(verifier))
+}
@@ -1,7 +1,9 @@
 package entity_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/stretchr/testify/assert"
@@ -9,8 +11,44 @@ import (
 
 func TestOTPCodeEntity_New(t *testing.T) {
 	mobileNumber := "09000000000"
-	otpCode := entity.NewOtpCode(mobileNumber)
+	otpCode, err := entity.NewOtpCode(mobileNumber, entity.LoginPurpose, 6, time.Minute)
+	assert.NoError(t, err)
 	assert.Equal(t, 6, len(otpCode.Code), "code length must be 6")
-	newOtpCode := entity.NewOtpCode(mobileNumber)
+	newOtpCode, err := entity.NewOtpCode(mobileNumber, entity.LoginPurpose, 6, time.Minute)
+	assert.NoError(t, err)
 	assert.NotEqual(t, newOtpCode.Code, otpCode.Code, "code must be random")
+
+	longOtpCode, err := entity.NewOtpCode(mobileNumber, entity.LoginPurpose, 8, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, len(longOtpCode.Code), "code length must be configurable")
+
+	_, err = entity.NewOtpCode(mobileNumber, entity.LoginPurpose, 0, time.Minute)
+	assert.Error(t, err)
+}
+
+func TestOTPCodeEntity_Key(t *testing.T) {
+	mobileNumber := "09000000000"
+	loginCode, _ := entity.NewOtpCode(mobileNumber, entity.LoginPurpose, 6, time.Minute)
+	deleteCode, _ := entity.NewOtpCode(mobileNumber, entity.DeleteAccountPurpose, 6, time.Minute)
+	assert.Equal(t, "otp:login:09000000000", loginCode.Key())
+	assert.NotEqual(t, loginCode.Key(), deleteCode.Key(), "keys must be scoped by purpose")
+}
+
+func TestOTPCodeEntity_Hash(t *testing.T) {
+	otpCode, err := entity.NewOtpCode("09000000000", entity.LoginPurpose, 6, time.Minute)
+	assert.NoError(t, err)
+
+	hashedCode, err := otpCode.Hash()
+	assert.NoError(t, err)
+	assert.NotContains(t, hashedCode, otpCode.Code, "hash must not contain the plain code")
+	assert.True(t, strings.Contains(hashedCode, "$"))
+
+	anotherHash, err := otpCode.Hash()
+	assert.NoError(t, err)
+	assert.NotEqual(t, hashedCode, anotherHash, "hash must be salted")
+
+	assert.True(t, entity.MatchOTPCode(hashedCode, otpCode.Code))
+	assert.True(t, entity.MatchOTPCode(anotherHash, otpCode.Code))
+	assert.False(t, entity.MatchOTPCode(hashedCode, "000000"))
+	assert.False(t, entity.MatchOTPCode(otpCode.Code, otpCode.Code), "plain code must not match")
 }
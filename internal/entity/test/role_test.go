@@ -0,0 +1,31 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleEntity_New(t *testing.T) {
+	role := entity.NewRole("Editor", []string{entity.UsersReadPermission, entity.UsersReadPermission, entity.UsersUpdatePermission})
+	assert.Equal(t, []string{entity.UsersReadPermission, entity.UsersUpdatePermission}, role.PermissionNames())
+	assert.True(t, role.HasPermission(entity.UsersUpdatePermission))
+	assert.False(t, role.HasPermission(entity.UsersDeletePermission))
+}
+
+func TestRoleEntity_Covers(t *testing.T) {
+	admin := entity.NewRole(entity.AdminRole, entity.AllPermissions)
+	support := entity.NewRole(entity.SupportRole, entity.DefaultRolePermissions[entity.SupportRole])
+	user := entity.NewRole(entity.UserRole, nil)
+
+	assert.True(t, admin.Covers(support))
+	assert.False(t, support.Covers(admin))
+	assert.True(t, support.Covers(user))
+	assert.True(t, user.Covers(user))
+}
+
+func TestIsPermissionValid(t *testing.T) {
+	assert.True(t, entity.IsPermissionValid(entity.SettingsStatesWritePermission))
+	assert.False(t, entity.IsPermissionValid("settings.everything"))
+}
@@ -0,0 +1,37 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserStatus(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	user := entity.NewUser("", "09121234567", entity.UserRole)
+	assert.False(t, user.IsSuspended(now))
+	assert.Equal(t, entity.ActiveStatus, user.Status(now))
+
+	user.SuspendedAt = &past
+	assert.True(t, user.IsSuspended(now))
+	assert.Equal(t, entity.BannedStatus, user.Status(now))
+
+	user.SuspendedUntil = &future
+	assert.True(t, user.IsSuspended(now))
+	assert.Equal(t, entity.SuspendedStatus, user.Status(now))
+
+	user.SuspendedUntil = &past
+	assert.False(t, user.IsSuspended(now))
+	assert.Equal(t, entity.ActiveStatus, user.Status(now))
+}
+
+func TestIsSuspensionReasonValid(t *testing.T) {
+	assert.True(t, entity.IsSuspensionReasonValid(entity.FraudSuspensionReason))
+	assert.False(t, entity.IsSuspensionReasonValid("bored"))
+	assert.True(t, entity.IsUserStatusValid(entity.BannedStatus))
+	assert.False(t, entity.IsUserStatusValid("deleted"))
+}
@@ -0,0 +1,53 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/totp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwoFactorEntity_MatchTOTP(t *testing.T) {
+	twoFactor, recoveryCodes, err := entity.NewTwoFactor(1)
+	assert.NoError(t, err)
+	assert.Len(t, recoveryCodes, 10)
+	assert.False(t, twoFactor.IsConfirmed())
+
+	now := time.Now()
+	code, _ := totp.Code(twoFactor.Secret, totp.Step(now))
+	step, ok := twoFactor.MatchTOTP(code, now)
+	assert.True(t, ok)
+
+	twoFactor.LastUsedStep = step
+	_, ok = twoFactor.MatchTOTP(code, now)
+	assert.False(t, ok, "a code can not be used twice")
+}
+
+func TestTwoFactorEntity_MatchRecoveryCode(t *testing.T) {
+	twoFactor, recoveryCodes, _ := entity.NewTwoFactor(1)
+
+	_, ok := twoFactor.MatchRecoveryCode("00000-00000")
+	assert.False(t, ok)
+	recoveryCode, ok := twoFactor.MatchRecoveryCode(" " + recoveryCodes[3] + " ")
+	assert.True(t, ok)
+
+	usedAt := time.Now()
+	for i := range twoFactor.RecoveryCodes {
+		if twoFactor.RecoveryCodes[i].HashedCode == recoveryCode.HashedCode {
+			twoFactor.RecoveryCodes[i].UsedAt = &usedAt
+		}
+	}
+	_, ok = twoFactor.MatchRecoveryCode(recoveryCodes[3])
+	assert.False(t, ok)
+}
+
+func TestRoleEntity_RequiresTwoFactor(t *testing.T) {
+	assert.True(t, entity.NewRole(entity.AdminRole, nil).RequiresTwoFactor())
+	assert.True(t, entity.NewRole(entity.SupportRole, nil).RequiresTwoFactor())
+	assert.False(t, entity.NewRole(entity.UserRole, nil).RequiresTwoFactor())
+	role := entity.NewRole("Editor", nil)
+	role.RequireTwoFactor = true
+	assert.True(t, role.RequiresTwoFactor())
+}
@@ -0,0 +1,119 @@
+package entity
+
+import "sort"
+
+const (
+	PersianLocale = "fa"
+	EnglishLocale = "en"
+	ArabicLocale  = "ar"
+	// DefaultLocale is the language of the Title of states and cities.
+	// Translations hold the other locales.
+	DefaultLocale = PersianLocale
+)
+
+var Locales = []string{PersianLocale, EnglishLocale, ArabicLocale}
+
+func IsLocaleSupported(locale string) bool {
+	for _, supported := range Locales {
+		if supported == locale {
+			return true
+		}
+	}
+	return false
+}
+
+type StateTranslation struct {
+	ID      uint   `gorm:"primarykey"`
+	StateID uint   `gorm:"uniqueIndex:idx_state_translation"`
+	Locale  string `gorm:"uniqueIndex:idx_state_translation"`
+	Title   string
+}
+
+type CityTranslation struct {
+	ID     uint   `gorm:"primarykey"`
+	CityID uint   `gorm:"uniqueIndex:idx_city_translation"`
+	Locale string `gorm:"uniqueIndex:idx_city_translation"`
+	Title  string
+}
+
+// SetTranslations replaces the state's translations with titles, keyed by
+// locale. Empty titles are left out.
+func (state *State) SetTranslations(titles map[string]string) {
+	state.Translations = nil
+	for _, locale := range sortedLocales(titles) {
+		state.Translations = append(state.Translations, StateTranslation{StateID: state.ID, Locale: locale, Title: titles[locale]})
+	}
+}
+
+// TranslationTitles returns the state's translations keyed by locale.
+func (state State) TranslationTitles() map[string]string {
+	titles := make(map[string]string, len(state.Translations))
+	for _, translation := range state.Translations {
+		titles[translation.Locale] = translation.Title
+	}
+	return titles
+}
+
+// TitleIn returns the state's title in locale, falling back to Title when it
+// has no translation for it. Translations have to be preloaded.
+func (state State) TitleIn(locale string) string {
+	for _, translation := range state.Translations {
+		if translation.Locale == locale {
+			return translation.Title
+		}
+	}
+	return state.Title
+}
+
+// Localized returns a copy of the state titled in locale.
+func (state State) Localized(locale string) State {
+	state.Title = state.TitleIn(locale)
+	return state
+}
+
+// SetTranslations replaces the city's translations with titles, keyed by
+// locale. Empty titles are left out.
+func (city *City) SetTranslations(titles map[string]string) {
+	city.Translations = nil
+	for _, locale := range sortedLocales(titles) {
+		city.Translations = append(city.Translations, CityTranslation{CityID: city.ID, Locale: locale, Title: titles[locale]})
+	}
+}
+
+// TranslationTitles returns the city's translations keyed by locale.
+func (city City) TranslationTitles() map[string]string {
+	titles := make(map[string]string, len(city.Translations))
+	for _, translation := range city.Translations {
+		titles[translation.Locale] = translation.Title
+	}
+	return titles
+}
+
+// TitleIn returns the city's title in locale, falling back to Title when it
+// has no translation for it. Translations have to be preloaded.
+func (city City) TitleIn(locale string) string {
+	for _, translation := range city.Translations {
+		if translation.Locale == locale {
+			return translation.Title
+		}
+	}
+	return city.Title
+}
+
+// Localized returns a copy of the city, and of its state, titled in locale.
+func (city City) Localized(locale string) City {
+	city.Title = city.TitleIn(locale)
+	city.State = city.State.Localized(locale)
+	return city
+}
+
+func sortedLocales(titles map[string]string) []string {
+	locales := make([]string, 0, len(titles))
+	for locale, title := range titles {
+		if title != "" {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
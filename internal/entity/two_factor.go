@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/totp"
+	"gorm.io/gorm"
+)
+
+const (
+	TwoFactorIssuer    = "Room Reservation"
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 5
+)
+
+// TwoFactor is a user's TOTP enrollment. It only protects logins once it
+// is confirmed with a first code from the authenticator app.
+type TwoFactor struct {
+	gorm.Model
+	UserID        uint `gorm:"uniqueIndex"`
+	Secret        string
+	ConfirmedAt   *time.Time
+	LastUsedStep  int64
+	RecoveryCodes []RecoveryCode `gorm:"foreignKey:TwoFactorID;constraint:OnDelete:CASCADE"`
+}
+
+// RecoveryCode replaces a TOTP code once when the authenticator is lost.
+type RecoveryCode struct {
+	ID          uint `gorm:"primarykey"`
+	TwoFactorID uint `gorm:"index"`
+	HashedCode  string
+	UsedAt      *time.Time
+}
+
+// NewTwoFactor creates an unconfirmed enrollment and returns the plain text
+// recovery codes, which are shown to the user once.
+func NewTwoFactor(userId uint) (TwoFactor, []string, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return TwoFactor{}, nil, err
+	}
+	twoFactor := TwoFactor{UserID: userId, Secret: secret}
+	var codes []string
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := randomHex(recoveryCodeLength)
+		if err != nil {
+			return TwoFactor{}, nil, err
+		}
+		code := raw[:recoveryCodeLength] + "-" + raw[recoveryCodeLength:]
+		codes = append(codes, code)
+		twoFactor.RecoveryCodes = append(twoFactor.RecoveryCodes, RecoveryCode{HashedCode: sha256Hex(code)})
+	}
+	return twoFactor, codes, nil
+}
+
+func (twoFactor TwoFactor) IsConfirmed() bool {
+	return twoFactor.ConfirmedAt != nil
+}
+
+func (twoFactor TwoFactor) ProvisioningURI(account string) string {
+	return totp.ProvisioningURI(TwoFactorIssuer, account, twoFactor.Secret)
+}
+
+// MatchTOTP returns the time step of a valid code that was not used before.
+func (twoFactor TwoFactor) MatchTOTP(code string, now time.Time) (int64, bool) {
+	step, ok := totp.Validate(twoFactor.Secret, code, now, 1)
+	if !ok || step <= twoFactor.LastUsedStep {
+		return 0, false
+	}
+	return step, true
+}
+
+// MatchRecoveryCode returns the unused recovery code matching code.
+func (twoFactor TwoFactor) MatchRecoveryCode(code string) (RecoveryCode, bool) {
+	hashed := sha256Hex(strings.ToLower(strings.TrimSpace(code)))
+	for _, recoveryCode := range twoFactor.RecoveryCodes {
+		if recoveryCode.UsedAt == nil && subtle.ConstantTimeCompare([]byte(hashed), []byte(recoveryCode.HashedCode)) == 1 {
+			return recoveryCode, true
+		}
+	}
+	return RecoveryCode{}, false
+}
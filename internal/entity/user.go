@@ -1,6 +1,10 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 const (
 	AdminRole   string = "Admin"
@@ -8,13 +12,58 @@ const (
 	UserRole    string = "User"
 )
 
+const (
+	ActiveStatus    string = "active"
+	SuspendedStatus string = "suspended"
+	BannedStatus    string = "banned"
+)
+
+// User.MobileNumber is only unique among users that are not deleted, so a
+// deleted account does not keep its number from registering again.
 type User struct {
 	gorm.Model
-	FullName     string
-	MobileNumber string `gorm:"unique"`
-	Role         string
+	FullName         string
+	MobileNumber     string `gorm:"uniqueIndex:idx_users_mobile_number,where:deleted_at IS NULL"`
+	Country          string
+	Role             string
+	SuspendedAt      *time.Time
+	SuspendedUntil   *time.Time
+	SuspensionReason string
+	// set once the user confirms deleting their account; logging in before
+	// DeletionScheduledFor cancels it
+	DeletionRequestedAt  *time.Time
+	DeletionScheduledFor *time.Time
+	AnonymizedAt         *time.Time
 }
 
 func NewUser(fullName, mobileNumber, role string) User {
 	return User{FullName: fullName, MobileNumber: mobileNumber, Role: role}
 }
+
+// IsSuspended reports whether the user is locked out at now. A suspension
+// without an end time is a ban.
+func (user User) IsSuspended(now time.Time) bool {
+	if user.SuspendedAt == nil {
+		return false
+	}
+	return user.SuspendedUntil == nil || now.Before(*user.SuspendedUntil)
+}
+
+func (user User) IsDeletionPending() bool {
+	return user.DeletionScheduledFor != nil && user.AnonymizedAt == nil
+}
+
+func (user User) Status(now time.Time) string {
+	switch {
+	case !user.IsSuspended(now):
+		return ActiveStatus
+	case user.SuspendedUntil == nil:
+		return BannedStatus
+	default:
+		return SuspendedStatus
+	}
+}
+
+func IsUserStatusValid(status string) bool {
+	return status == ActiveStatus || status == SuspendedStatus || status == BannedStatus
+}
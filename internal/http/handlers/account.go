@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// ConfirmAccountDeletion godoc
+// @Summary Confirm Account Deletion
+// @Description Verifies the code sent by DELETE /user/me and schedules the account for deletion after the grace period. Existing tokens stop working; logging in again before the scheduled time cancels the deletion. Afterwards the account is anonymized.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param confirmAccountDeletion body models.ConfirmAccountDeletion true "Verification code"
+// @Success 202 {object} models.AccountDeletionResponse "Account scheduled for deletion"
+// @Failure 400 {object} map[string]interface{} "Invalid code"
+// @Failure 409 {object} map[string]interface{} "The last active admin can not be deleted"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/me/deletion/confirm [post]
+// @Security BearerAuth
+func (h Handler) ConfirmAccountDeletion(context *gin.Context) {
+	body := new(models.ConfirmAccountDeletion)
+	err := context.BindJSON(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	err = h.OTP.ValidateCode(context, context.GetString("mobileNumber"), entity.DeleteAccountPurpose, body.Code)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	user, err := h.Accounts.ScheduleDeletion(context, context.GetUint("userId"))
+	if errors.Is(err, usecase.ErrLastAdmin) {
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	response := models.AccountDeletionResponse{Message: "account scheduled for deletion", ScheduledFor: *user.DeletionScheduledFor}
+	context.JSON(http.StatusAccepted, response)
+}
+
+// ExportAccount godoc
+// @Summary Export Account Data
+// @Description Returns everything stored about the authenticated user. With format=zip the data is downloaded as an archive holding one JSON file per section.
+// @Tags User
+// @Produce json
+// @Produce application/zip
+// @Param format query string false "Export format" Enums(json, zip) default(json)
+// @Success 200 {object} models.AccountExportResponse "Account data"
+// @Failure 400 {object} map[string]interface{} "Invalid format"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/me/export [get]
+// @Security BearerAuth
+func (h Handler) ExportAccount(context *gin.Context) {
+	format := context.DefaultQuery("format", "json")
+	if format != "json" && format != "zip" {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid format"})
+		return
+	}
+	export, err := h.Accounts.Export(context, context.GetUint("userId"))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	now := time.Now()
+	response := models.NewAccountExportResponse(export, now)
+	fileName := fmt.Sprintf("account-%v-%v", export.User.ID, now.Format("20060102"))
+	if format == "json" {
+		context.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName+".json"))
+		context.JSON(http.StatusOK, response)
+		return
+	}
+	context.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName+".zip"))
+	context.Header("Content-Type", "application/zip")
+	context.Status(http.StatusOK)
+	archive := zip.NewWriter(context.Writer)
+	for _, file := range response.Files() {
+		writer, err := archive.Create(file.Name)
+		if err != nil {
+			context.Error(err)
+			return
+		}
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(file.Content); err != nil {
+			context.Error(err)
+			return
+		}
+	}
+	if err = archive.Close(); err != nil {
+		context.Error(err)
+	}
+}
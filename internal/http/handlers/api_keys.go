@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// IssueAPIKey godoc
+// @Summary Issue API Key
+// @Description Issues an API key for a user or an organization. The key is only returned in this response. Scopes are permissions and can not exceed the caller's own.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param apiKey body models.IssueAPIKey true "API key data"
+// @Success 201 {object} models.IssuedAPIKeyResponse "Issued API key"
+// @Failure 400 {object} map[string]interface{} "Invalid request body, owner or scope"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/api-keys [post]
+// @Security BearerAuth
+func (h Handler) IssueAPIKey(context *gin.Context) {
+	body := new(models.IssueAPIKey)
+	err := context.BindJSON(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if body.UserId != nil {
+		if !h.Users.DoesUserExist(*body.UserId) {
+			context.JSON(http.StatusBadRequest, gin.H{"message": "user not found"})
+			return
+		}
+	}
+	for _, scope := range body.Scopes {
+		allowed, err := h.Roles.HasPermission(context, context.GetString("role"), scope)
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+			return
+		}
+		if !allowed {
+			context.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("invalid scope %q", scope)})
+			return
+		}
+	}
+	apiKey, rawKey, err := h.APIKeys.Issue(context, body.Name, body.UserId, body.Organization, body.Scopes, body.RateLimit, body.ExpiresAt)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	err = h.Audit.Record(context, context.GetUint("userId"), entity.IssueAPIKeyAction, "api_key", apiKey.ID, apiKey.Name)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusCreated, models.IssuedAPIKeyResponse{APIKey: models.NewAPIKeyResponse(apiKey), Key: rawKey})
+}
+
+// APIKeyList godoc
+// @Summary List API Keys
+// @Description Lists every API key, including revoked and expired ones.
+// @Tags API Keys
+// @Produce json
+// @Success 200 {object} []models.APIKeyResponse "List of API keys"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/api-keys [get]
+// @Security BearerAuth
+func (h Handler) APIKeyList(context *gin.Context) {
+	apiKeys, err := h.APIKeys.List(context)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewAPIKeyListResponse(apiKeys))
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API Key
+// @Description Revokes an API key. Revoked keys are kept for reference but can no longer authenticate.
+// @Tags API Keys
+// @Produce json
+// @Param id path int true "API key ID"
+// @Success 200 {object} models.APIKeyResponse "Revoked API key"
+// @Failure 400 {object} map[string]interface{} "Invalid endpoint"
+// @Failure 404 {object} map[string]interface{} "API key not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/api-keys/{id} [delete]
+// @Security BearerAuth
+func (h Handler) RevokeAPIKey(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return
+	}
+	apiKey, err := h.APIKeys.Revoke(context, uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "api key not found"})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	err = h.Audit.Record(context, context.GetUint("userId"), entity.RevokeAPIKeyAction, "api_key", apiKey.ID, apiKey.Name)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewAPIKeyResponse(apiKey))
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditLogs godoc
+// @Summary Audit Logs
+// @Description Lists audit log entries, newest first. Every filter is optional; from and to are RFC 3339 timestamps bounding the creation time.
+// @Tags User
+// @Produce json
+// @Param actor-id query int false "Filter by the user who made the change"
+// @Param action query string false "Filter by action, e.g. users.update"
+// @Param target-type query string false "Filter by target type, e.g. user, role, state, city"
+// @Param target-id query int false "Filter by target ID"
+// @Param from query string false "Only entries created at or after this time"
+// @Param to query string false "Only entries created before this time"
+// @Param page query int false "Page number" default(1)
+// @Param page-size query int false "Number of entries per page" default(10)
+// @Success 200 {object} utils.PaginatedResponse{result=[]models.AuditLogResponse} "Audit logs"
+// @Failure 400 {object} map[string]interface{} "Invalid filter"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/audit-logs [get]
+// @Security BearerAuth
+func (h Handler) AuditLogs(context *gin.Context) {
+	filter, ok := auditLogFilter(context)
+	if !ok {
+		return
+	}
+	pageSize := utils.ParseQueryParamToInt(context.Query("page-size"), 10)
+	pageNumber := utils.ParseQueryParamToInt(context.Query("page"), 1)
+	auditLogs, count, err := h.Audit.List(context, filter, pageNumber, pageSize)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	response := utils.GenerateListResponse(models.NewAuditLogListResponse(auditLogs), count, pageSize, pageNumber)
+	context.JSON(http.StatusOK, response)
+}
+
+func auditLogFilter(context *gin.Context) (repository.AuditLogFilter, bool) {
+	filter := repository.AuditLogFilter{
+		Action:     context.Query("action"),
+		TargetType: context.Query("target-type"),
+	}
+	ids := []struct {
+		param string
+		value *uint
+	}{{"actor-id", &filter.ActorID}, {"target-id", &filter.TargetID}}
+	for _, id := range ids {
+		if value := context.Query(id.param); value != "" {
+			parsed, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				context.JSON(http.StatusBadRequest, gin.H{"message": "invalid " + id.param})
+				return filter, false
+			}
+			*id.value = uint(parsed)
+		}
+	}
+	times := []struct {
+		param string
+		value *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}}
+	for _, moment := range times {
+		if value := context.Query(moment.param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				context.JSON(http.StatusBadRequest, gin.H{"message": "invalid " + moment.param})
+				return filter, false
+			}
+			*moment.value = parsed
+		}
+	}
+	return filter, true
+}
@@ -1,31 +1,28 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
-	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
-	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/geo"
 	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
 	"github.com/gin-gonic/gin"
 )
 
-func CreateCity(context *gin.Context) {
+func (h Handler) CreateCity(context *gin.Context) {
 	stateId, err := strconv.ParseInt(context.Param("stateId"), 10, 64)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	db := database.GetDb()
-	stateRepo := repository.NewStateRepository(db)
-	stateUseCase := usecase.NewStateUseCase(stateRepo)
-	if !stateUseCase.DoesStateExist(context, uint(stateId)) {
+	if !h.States.DoesStateExist(context, uint(stateId)) {
 		context.JSON(http.StatusNotFound, gin.H{"message": "state not found"})
 		return
 	}
-	state, err := stateUseCase.GetStateById(context, uint(stateId))
+	state, err := h.States.GetStateById(context, uint(stateId))
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -36,9 +33,16 @@ func CreateCity(context *gin.Context) {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	cityRepo := repository.NewCityRepository(db)
-	cityUseCase := usecase.NewCityUseCase(cityRepo)
-	city, err := cityUseCase.Create(context, body.Title, state)
+	location, err := body.Location()
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	city, err := h.Cities.Create(context, body.Title, state, location)
+	if errors.Is(err, usecase.ErrCityTitleTaken) {
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
@@ -47,49 +51,45 @@ func CreateCity(context *gin.Context) {
 	context.JSON(http.StatusCreated, response)
 }
 
-func CityList(context *gin.Context) {
+func (h Handler) CityList(context *gin.Context) {
 	stateId, err := strconv.ParseInt(context.Param("id"), 10, 64)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	db := database.GetDb()
-	stateRepo := repository.NewStateRepository(db)
-	stateUseCase := usecase.NewStateUseCase(stateRepo)
-	if !stateUseCase.DoesStateExist(context, uint(stateId)) {
+	if !h.States.DoesStateExist(context, uint(stateId)) {
 		context.JSON(http.StatusNotFound, gin.H{"message": "state not found"})
 		return
 	}
 	pageSize := utils.ParseQueryParamToInt(context.Query("page-size"), 10)
 	pageNumber := utils.ParseQueryParamToInt(context.Query("page"), 1)
 	title := context.Query("title")
-	cityRepo := repository.NewCityRepository(db)
-	cityUseCase := usecase.NewCityUseCase(cityRepo)
-	cities, err := cityUseCase.CityList(context, pageNumber, pageSize, int(stateId), title)
+	cities, err := h.Cities.CityList(context, pageNumber, pageSize, int(stateId), title)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
-	citiesCount, err := cityUseCase.Count(context)
+	citiesCount, err := h.Cities.Count(context)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
+	locale := requestLocale(context)
+	for i := range cities {
+		cities[i] = cities[i].Localized(locale)
+	}
 	city_list := models.NewCityListResponse(cities)
 	response := utils.GenerateListResponse(city_list, citiesCount, pageSize, pageNumber)
 	context.JSON(http.StatusOK, response)
 }
 
-func RetrieveCity(context *gin.Context) {
+func (h Handler) RetrieveCity(context *gin.Context) {
 	stateId, err := strconv.ParseInt(context.Param("id"), 10, 64)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	db := database.GetDb()
-	stateRepo := repository.NewStateRepository(db)
-	stateUseCase := usecase.NewStateUseCase(stateRepo)
-	if !stateUseCase.DoesStateExist(context, uint(stateId)) {
+	if !h.States.DoesStateExist(context, uint(stateId)) {
 		context.JSON(http.StatusNotFound, gin.H{"message": "city not found"})
 		return
 	}
@@ -98,31 +98,26 @@ func RetrieveCity(context *gin.Context) {
 		context.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
 		return
 	}
-	cityRepo := repository.NewCityRepository(db)
-	cityUseCase := usecase.NewCityUseCase(cityRepo)
-	if !cityUseCase.DoesCityExist(context, uint(cityId), uint(stateId)) {
+	if !h.Cities.DoesCityExist(context, uint(cityId), uint(stateId)) {
 		context.JSON(http.StatusNotFound, gin.H{"message": "city not found"})
 		return
 	}
-	city, err := cityUseCase.ById(context, uint(cityId))
+	city, err := h.Cities.ById(context, uint(cityId))
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
-	response := models.NewCityResponse(city)
+	response := models.NewCityResponse(city.Localized(requestLocale(context)))
 	context.JSON(http.StatusOK, response)
 }
 
-func UpdateCity(context *gin.Context) {
+func (h Handler) UpdateCity(context *gin.Context) {
 	stateId, err := strconv.ParseInt(context.Param("id"), 10, 64)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	db := database.GetDb()
-	stateRepo := repository.NewStateRepository(db)
-	stateUseCase := usecase.NewStateUseCase(stateRepo)
-	if !stateUseCase.DoesStateExist(context, uint(stateId)) {
+	if !h.States.DoesStateExist(context, uint(stateId)) {
 		context.JSON(http.StatusNotFound, gin.H{"message": "city not found"})
 		return
 	}
@@ -131,9 +126,7 @@ func UpdateCity(context *gin.Context) {
 		context.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
 		return
 	}
-	cityRepo := repository.NewCityRepository(db)
-	cityUseCase := usecase.NewCityUseCase(cityRepo)
-	if !cityUseCase.DoesCityExist(context, uint(cityId), uint(stateId)) {
+	if !h.Cities.DoesCityExist(context, uint(cityId), uint(stateId)) {
 		context.JSON(http.StatusNotFound, gin.H{"message": "city not found"})
 		return
 	}
@@ -143,8 +136,17 @@ func UpdateCity(context *gin.Context) {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	updateInfo := map[string]any{"title": body.Title}
-	city, err := cityUseCase.Update(context, uint(cityId), updateInfo)
+	updateInfo, err := body.LocationUpdates()
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	updateInfo["title"] = body.Title
+	city, err := h.Cities.Update(context, uint(cityId), updateInfo)
+	if errors.Is(err, usecase.ErrCityTitleTaken) {
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -153,16 +155,13 @@ func UpdateCity(context *gin.Context) {
 	context.JSON(http.StatusOK, response)
 }
 
-func DeleteCity(context *gin.Context) {
+func (h Handler) DeleteCity(context *gin.Context) {
 	stateId, err := strconv.ParseInt(context.Param("id"), 10, 64)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	db := database.GetDb()
-	stateRepo := repository.NewStateRepository(db)
-	stateUseCase := usecase.NewStateUseCase(stateRepo)
-	if !stateUseCase.DoesStateExist(context, uint(stateId)) {
+	if !h.States.DoesStateExist(context, uint(stateId)) {
 		context.JSON(http.StatusNotFound, gin.H{"message": "city not found"})
 		return
 	}
@@ -171,16 +170,61 @@ func DeleteCity(context *gin.Context) {
 		context.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
 		return
 	}
-	cityRepo := repository.NewCityRepository(db)
-	cityUseCase := usecase.NewCityUseCase(cityRepo)
-	if !cityUseCase.DoesCityExist(context, uint(cityId), uint(stateId)) {
+	if !h.Cities.DoesCityExist(context, uint(cityId), uint(stateId)) {
 		context.JSON(http.StatusNotFound, gin.H{"message": "city not found"})
 		return
 	}
-	err = cityUseCase.DeleteById(context, uint(cityId))
+	err = h.Cities.DeleteById(context, uint(cityId))
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 	context.JSON(http.StatusNoContent, nil)
 }
+
+// NearbyCities godoc
+// @Summary Nearby Cities
+// @Description Lists the cities with coordinates within radius kilometers of a point, nearest first.
+// @Tags states
+// @Produce json
+// @Param lat query number true "Latitude"
+// @Param lng query number true "Longitude"
+// @Param radius query number false "Radius in kilometers, at most 1000" default(50)
+// @Param limit query int false "Maximum number of cities" default(20)
+// @Param lang query string false "Locale of the titles, Accept-Language is used when omitted" Enums(fa, en, ar)
+// @Success 200 {array} models.NearbyCityResponse "Cities with their distance"
+// @Failure 400 {object} map[string]interface{} "Invalid coordinates or radius"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/cities/nearby [get]
+func (h Handler) NearbyCities(context *gin.Context) {
+	lat, latErr := strconv.ParseFloat(context.Query("lat"), 64)
+	lng, lngErr := strconv.ParseFloat(context.Query("lng"), 64)
+	if latErr != nil || lngErr != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "lat and lng are required numbers"})
+		return
+	}
+	radius := 50.0
+	if value := context.Query("radius"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"message": usecase.ErrInvalidRadius.Error()})
+			return
+		}
+		radius = parsed
+	}
+	limit := min(max(utils.ParseQueryParamToInt(context.Query("limit"), 20), 1), 100)
+	cities, err := h.Cities.Nearby(context, lat, lng, radius, limit)
+	switch {
+	case errors.Is(err, geo.ErrInvalidPoint), errors.Is(err, usecase.ErrInvalidRadius):
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	case err != nil:
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	locale := requestLocale(context)
+	for i := range cities {
+		cities[i].City = cities[i].City.Localized(locale)
+	}
+	context.JSON(http.StatusOK, models.NewNearbyCityListResponse(cities))
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// DatabaseDiagnostics godoc
+// @Summary Database Diagnostics
+// @Description Pings the database and reports the connection pool limits and statistics. Durations are in milliseconds.
+// @Tags Diagnostics
+// @Produce json
+// @Success 200 {object} models.DatabaseDiagnosticsResponse "Connection pool statistics"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Failure 503 {object} map[string]interface{} "Database unreachable"
+// @Router /user/diagnostics/database [get]
+// @Security BearerAuth
+func (h Handler) DatabaseDiagnostics(context *gin.Context) {
+	diagnostics, err := h.Diagnostics.Database(context)
+	if errors.Is(err, usecase.ErrDatabaseUnreachable) {
+		context.JSON(http.StatusServiceUnavailable, gin.H{"message": usecase.ErrDatabaseUnreachable.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewDatabaseDiagnosticsResponse(diagnostics))
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateDistrict godoc
+// @Summary Create District
+// @Description Adds a district to a city.
+// @Tags districts
+// @Accept json
+// @Produce json
+// @Param cityId path int true "City ID"
+// @Param district body models.District true "District"
+// @Success 201 {object} models.DistrictResponse "Created district"
+// @Failure 400 {object} map[string]interface{} "Invalid ID or body"
+// @Failure 404 {object} map[string]interface{} "City not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/cities/{cityId}/districts [post]
+// @Security BearerAuth
+func (h Handler) CreateDistrict(context *gin.Context) {
+	city, ok := h.districtCity(context)
+	if !ok {
+		return
+	}
+	body := new(models.District)
+	if err := context.BindJSON(body); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	district, err := h.Districts.Create(context, body.Title, city)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	district.City = city
+	context.JSON(http.StatusCreated, models.NewDistrictResponse(district))
+}
+
+// DistrictList godoc
+// @Summary Districts
+// @Description Lists a city's districts.
+// @Tags districts
+// @Produce json
+// @Param cityId path int true "City ID"
+// @Param title query string false "Part of the title"
+// @Param page query int false "Page number" default(1)
+// @Param page-size query int false "Number of districts per page" default(10)
+// @Success 200 {object} utils.PaginatedResponse{result=[]models.DistrictResponse} "Districts"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "City not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/cities/{cityId}/districts [get]
+func (h Handler) DistrictList(context *gin.Context) {
+	city, ok := h.districtCity(context)
+	if !ok {
+		return
+	}
+	pageSize := utils.ParseQueryParamToInt(context.Query("page-size"), 10)
+	pageNumber := utils.ParseQueryParamToInt(context.Query("page"), 1)
+	districts, count, err := h.Districts.List(context, city.ID, pageNumber, pageSize, context.Query("title"))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	response := utils.GenerateListResponse(models.NewDistrictListResponse(districts), count, pageSize, pageNumber)
+	context.JSON(http.StatusOK, response)
+}
+
+// RetrieveDistrict godoc
+// @Summary District
+// @Description Returns a district of a city with its path.
+// @Tags districts
+// @Produce json
+// @Param cityId path int true "City ID"
+// @Param districtId path int true "District ID"
+// @Success 200 {object} models.DistrictResponse "District"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "District not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/cities/{cityId}/districts/{districtId} [get]
+func (h Handler) RetrieveDistrict(context *gin.Context) {
+	district, ok := h.cityDistrict(context)
+	if !ok {
+		return
+	}
+	context.JSON(http.StatusOK, models.NewDistrictResponse(district))
+}
+
+// UpdateDistrict godoc
+// @Summary Update District
+// @Description Renames a district of a city.
+// @Tags districts
+// @Accept json
+// @Produce json
+// @Param cityId path int true "City ID"
+// @Param districtId path int true "District ID"
+// @Param district body models.District true "District"
+// @Success 200 {object} models.DistrictResponse "Updated district"
+// @Failure 400 {object} map[string]interface{} "Invalid ID or body"
+// @Failure 404 {object} map[string]interface{} "District not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/cities/{cityId}/districts/{districtId} [put]
+// @Security BearerAuth
+func (h Handler) UpdateDistrict(context *gin.Context) {
+	district, ok := h.cityDistrict(context)
+	if !ok {
+		return
+	}
+	body := new(models.District)
+	if err := context.BindJSON(body); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	district, err := h.Districts.Update(context, district.ID, map[string]any{"title": body.Title})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewDistrictResponse(district))
+}
+
+// DeleteDistrict godoc
+// @Summary Delete District
+// @Description Deletes a district of a city.
+// @Tags districts
+// @Param cityId path int true "City ID"
+// @Param districtId path int true "District ID"
+// @Success 204 "District deleted"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "District not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/cities/{cityId}/districts/{districtId} [delete]
+// @Security BearerAuth
+func (h Handler) DeleteDistrict(context *gin.Context) {
+	district, ok := h.cityDistrict(context)
+	if !ok {
+		return
+	}
+	if err := h.Districts.DeleteById(context, district.ID); err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	context.Status(http.StatusNoContent)
+}
+
+// districtCity loads the city of the cityId parameter, writing the error
+// response when it can't.
+func (h Handler) districtCity(context *gin.Context) (entity.City, bool) {
+	cityId, ok := locationId(context, "cityId")
+	if !ok {
+		return entity.City{}, false
+	}
+	city, err := h.Cities.ById(context, cityId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "city not found"})
+		return entity.City{}, false
+	} else if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return entity.City{}, false
+	}
+	return city, true
+}
+
+// cityDistrict loads the district of the districtId parameter when it
+// belongs to the city of the cityId parameter.
+func (h Handler) cityDistrict(context *gin.Context) (entity.District, bool) {
+	cityId, ok := locationId(context, "cityId")
+	if !ok {
+		return entity.District{}, false
+	}
+	districtId, ok := locationId(context, "districtId")
+	if !ok {
+		return entity.District{}, false
+	}
+	district, err := h.Districts.ById(context, districtId)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && district.CityID != cityId) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "district not found"})
+		return entity.District{}, false
+	} else if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return entity.District{}, false
+	}
+	return district, true
+}
+
+func locationId(context *gin.Context, param string) (uint, bool) {
+	id, err := strconv.ParseUint(context.Param(param), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
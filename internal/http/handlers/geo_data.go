@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GeoDataDiff reports how the states and cities differ from the bundled dataset.
+//
+// @Summary      Compare states and cities with the dataset
+// @Description  Lists the dataset's provinces and counties missing from the database, the ones whose title differs or that are not linked by code yet, and the states and cities the dataset does not know. Run the `seed geo` command to create the missing ones.
+// @Tags         states
+// @Produce      json
+// @Success      200  {object}  models.GeoDiffResponse  "Differences"
+// @Failure      500  {object}  map[string]string       "Internal server error"
+// @Router       /settings/dataset/diff [get]
+// @Security BearerAuth
+func (h Handler) GeoDataDiff(context *gin.Context) {
+	diff, err := h.GeoData.Diff(context)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewGeoDiffResponse(diff))
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/sms"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+)
+
+// Handler serves the HTTP endpoints. Its use cases are built once when the
+// application starts and shared by every request.
+type Handler struct {
+	Users          usecase.UserUseCase
+	OTP            usecase.OTPUseCase
+	States         usecase.StateUseCase
+	Cities         usecase.CityUseCase
+	Districts      usecase.DistrictUseCase
+	Neighborhoods  usecase.NeighborhoodUseCase
+	Roles          usecase.RoleUseCase
+	APIKeys        usecase.APIKeyUseCase
+	OAuth          usecase.OAuthUseCase
+	TwoFactor      usecase.TwoFactorUseCase
+	Suspensions    usecase.SuspensionUseCase
+	LoginEvents    usecase.LoginEventUseCase
+	Impersonations usecase.ImpersonationUseCase
+	Audit          usecase.AuditLogUseCase
+	Accounts       usecase.AccountUseCase
+	Diagnostics    usecase.DiagnosticsUseCase
+	GeoData        usecase.GeoDataUseCase
+	Locations      usecase.LocationUseCase
+	SMS            *sms.Router
+	// SecretKey signs the access tokens
+	SecretKey string
+}
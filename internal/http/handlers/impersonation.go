@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// StartImpersonation godoc
+// @Summary Impersonate User
+// @Description Issues a short-lived token that acts as the user, for support staff who need to see the app as the customer does. Every request made with it is logged and audited under the staff member, and sensitive actions such as deleting the account are refused.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param impersonation body models.StartImpersonation true "Reason for the impersonation"
+// @Success 201 {object} models.ImpersonationTokenResponse "Impersonation token"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 403 {object} map[string]interface{} "Forbidden: insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/users/{id}/impersonate [post]
+// @Security BearerAuth
+func (h Handler) StartImpersonation(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return
+	}
+	body := new(models.StartImpersonation)
+	if err = context.BindJSON(body); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if !h.canManageUser(context, uint(id)) {
+		return
+	}
+	subject, err := h.Users.GetUserById(uint(id))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	actorId := context.GetUint("userId")
+	impersonation, token, err := h.Impersonations.Start(context, actorId, subject, body.Reason)
+	if errors.Is(err, usecase.ErrImpersonateSelf) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	err = h.Audit.Record(context, actorId, entity.StartImpersonationAction, "user", subject.ID, body.Reason)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	response := models.ImpersonationTokenResponse{Token: token, Impersonation: models.NewImpersonationResponse(impersonation)}
+	context.JSON(http.StatusCreated, response)
+}
+
+// EndImpersonation godoc
+// @Summary End Impersonation
+// @Description Ends the impersonation session the token belongs to; the token stops working immediately.
+// @Tags User
+// @Produce json
+// @Success 200 {object} models.ImpersonationResponse "Impersonation ended"
+// @Failure 400 {object} map[string]interface{} "Not an impersonation token"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/impersonation [delete]
+// @Security BearerAuth
+func (h Handler) EndImpersonation(context *gin.Context) {
+	impersonationId := context.GetUint("impersonationId")
+	if impersonationId == 0 {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "not impersonating"})
+		return
+	}
+	impersonation, err := h.Impersonations.End(context, impersonationId)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	err = h.Audit.Record(context, impersonation.ActorID, entity.EndImpersonationAction, "user", impersonation.SubjectID, "")
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewImpersonationResponse(impersonation))
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	maxImportSize   = 10 << 20
+	exportBatchSize = 100
+)
+
+// ImportLocations godoc
+// @Summary Import states and cities
+// @Description Upserts the states and cities of a CSV or JSON file in a single transaction. CSV files have a header with the state_title column and optionally state_code, city_code and city_title, one row per city. JSON files use the export format. The whole file is validated first; when any row is invalid nothing is imported and the errors are returned with their line or path.
+// @Tags states
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or JSON file, at most 10 MB"
+// @Param format query string false "File format, taken from the file extension when omitted" Enums(csv, json)
+// @Param mode query string false "Match existing states and cities by title or by code" Enums(title, code) default(title)
+// @Param dry-run query bool false "Only report what would change"
+// @Success 200 {object} models.ImportResultResponse "Import result"
+// @Failure 400 {object} map[string]interface{} "Invalid file or parameters"
+// @Failure 413 {object} map[string]interface{} "File too large"
+// @Failure 422 {object} models.ImportResultResponse "Invalid rows"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/locations/import [post]
+// @Security BearerAuth
+func (h Handler) ImportLocations(context *gin.Context) {
+	header, err := context.FormFile("file")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "file is required"})
+		return
+	}
+	if header.Size > maxImportSize {
+		context.JSON(http.StatusRequestEntityTooLarge, gin.H{"message": "file is too large"})
+		return
+	}
+	format := context.Query("format")
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+	if format != "csv" && format != "json" {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid format"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	defer file.Close()
+	var rows []entity.LocationRow
+	if format == "csv" {
+		rows, err = models.ParseLocationsCSV(file)
+	} else {
+		rows, err = models.ParseLocationsJSON(file)
+	}
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	mode := context.DefaultQuery("mode", entity.ImportByTitle)
+	dryRun := context.Query("dry-run") == "true"
+	result, err := h.Locations.Import(context, rows, mode, dryRun)
+	if errors.Is(err, usecase.ErrInvalidImportMode) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if errors.Is(err, usecase.ErrInvalidImport) {
+		context.JSON(http.StatusUnprocessableEntity, models.NewImportResultResponse(result))
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewImportResultResponse(result))
+}
+
+// ExportLocations godoc
+// @Summary Export states and cities
+// @Description Streams every state with its cities. CSV files have one row per city and a row without city for states that have none. The JSON and CSV formats are accepted by the import.
+// @Tags states
+// @Produce json
+// @Produce text/csv
+// @Param format query string false "Export format" Enums(json, csv) default(json)
+// @Success 200 {array} models.LocationState "States with their cities"
+// @Failure 400 {object} map[string]interface{} "Invalid format"
+// @Router /settings/locations/export [get]
+// @Security BearerAuth
+func (h Handler) ExportLocations(context *gin.Context) {
+	format := context.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid format"})
+		return
+	}
+	fileName := fmt.Sprintf("locations-%v.%v", time.Now().Format("20060102"), format)
+	context.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	if format == "csv" {
+		context.Header("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		context.Header("Content-Type", "application/json; charset=utf-8")
+	}
+	context.Status(http.StatusOK)
+
+	// the status is sent with the first batch, so later errors can only
+	// cut the file short
+	var err error
+	if format == "csv" {
+		err = h.exportLocationsCSV(context)
+	} else {
+		err = h.exportLocationsJSON(context)
+	}
+	if err != nil {
+		context.Error(err)
+	}
+}
+
+func (h Handler) exportLocationsCSV(context *gin.Context) error {
+	writer := csv.NewWriter(context.Writer)
+	if err := writer.Write(models.LocationCSVHeader); err != nil {
+		return err
+	}
+	err := h.Locations.Export(context, exportBatchSize, func(state entity.State, cities []entity.City) error {
+		if err := writer.WriteAll(models.LocationCSVRecords(state, cities)); err != nil {
+			return err
+		}
+		context.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (h Handler) exportLocationsJSON(context *gin.Context) error {
+	if _, err := context.Writer.WriteString("["); err != nil {
+		return err
+	}
+	first := true
+	err := h.Locations.Export(context, exportBatchSize, func(state entity.State, cities []entity.City) error {
+		if !first {
+			if _, err := context.Writer.WriteString(","); err != nil {
+				return err
+			}
+		}
+		first = false
+		content, err := json.Marshal(models.NewLocationState(state, cities))
+		if err != nil {
+			return err
+		}
+		if _, err = context.Writer.Write(content); err != nil {
+			return err
+		}
+		context.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = context.Writer.WriteString("]\n")
+	return err
+}
+
+// LocationTree godoc
+// @Summary Location Tree
+// @Description Returns a state with all of its cities, their districts and the districts' neighborhoods in one response.
+// @Tags states
+// @Produce json
+// @Param id path int true "State ID"
+// @Success 200 {object} models.LocationTreeResponse "State tree"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "State not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/states/{id}/tree [get]
+func (h Handler) LocationTree(context *gin.Context) {
+	stateId, ok := locationId(context, "id")
+	if !ok {
+		return
+	}
+	tree, err := h.Locations.Tree(context, stateId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "state not found"})
+		return
+	} else if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewLocationTreeResponse(tree))
+}
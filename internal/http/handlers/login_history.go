@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var anomalyMessages = map[string]string{
+	entity.NewDeviceAnomaly:      "from a new device",
+	entity.UnusualCountryAnomaly: "from an unusual country",
+	entity.FailureBurstAnomaly:   "with several wrong codes",
+}
+
+// MyLogins godoc
+// @Summary Login History
+// @Description Lists the authenticated user's login attempts, newest first, with the anomalies each one was flagged for.
+// @Tags User
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page-size query int false "Number of events per page" default(10)
+// @Success 200 {object} utils.PaginatedResponse{result=[]models.LoginEventResponse} "Login history"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/me/logins [get]
+// @Security BearerAuth
+func (h Handler) MyLogins(context *gin.Context) {
+	pageSize := utils.ParseQueryParamToInt(context.Query("page-size"), 10)
+	pageNumber := utils.ParseQueryParamToInt(context.Query("page"), 1)
+	events, count, err := h.LoginEvents.History(context, context.GetUint("userId"), pageNumber, pageSize)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	response := utils.GenerateListResponse(models.NewLoginEventListResponse(events), count, pageSize, pageNumber)
+	context.JSON(http.StatusOK, response)
+}
+
+// UserLogins godoc
+// @Summary User Login History
+// @Description Lists a user's login attempts, newest first, including their review state.
+// @Tags User
+// @Produce json
+// @Param id path int true "User ID"
+// @Param page query int false "Page number" default(1)
+// @Param page-size query int false "Number of events per page" default(10)
+// @Success 200 {object} utils.PaginatedResponse{result=[]models.StaffLoginEventResponse} "Login history"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/users/{id}/logins [get]
+// @Security BearerAuth
+func (h Handler) UserLogins(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return
+	}
+	if !h.Users.DoesUserExist(uint(id)) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "user not found"})
+		return
+	}
+	pageSize := utils.ParseQueryParamToInt(context.Query("page-size"), 10)
+	pageNumber := utils.ParseQueryParamToInt(context.Query("page"), 1)
+	events, count, err := h.LoginEvents.History(context, uint(id), pageNumber, pageSize)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	response := utils.GenerateListResponse(models.NewStaffLoginEventListResponse(events), count, pageSize, pageNumber)
+	context.JSON(http.StatusOK, response)
+}
+
+// SuspiciousLogins godoc
+// @Summary Suspicious Login Review Queue
+// @Description Lists flagged login attempts, newest first. By default only the ones still waiting for review are returned.
+// @Tags User
+// @Produce json
+// @Param reviewed query bool false "List reviewed logins instead" default(false)
+// @Param page query int false "Page number" default(1)
+// @Param page-size query int false "Number of events per page" default(10)
+// @Success 200 {object} utils.PaginatedResponse{result=[]models.StaffLoginEventResponse} "Flagged logins"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/logins/suspicious [get]
+// @Security BearerAuth
+func (h Handler) SuspiciousLogins(context *gin.Context) {
+	reviewed := context.Query("reviewed") == "true"
+	pageSize := utils.ParseQueryParamToInt(context.Query("page-size"), 10)
+	pageNumber := utils.ParseQueryParamToInt(context.Query("page"), 1)
+	events, count, err := h.LoginEvents.ReviewQueue(context, reviewed, pageNumber, pageSize)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	response := utils.GenerateListResponse(models.NewStaffLoginEventListResponse(events), count, pageSize, pageNumber)
+	context.JSON(http.StatusOK, response)
+}
+
+// ReviewLogin godoc
+// @Summary Review Suspicious Login
+// @Description Marks a flagged login attempt as reviewed and takes it off the queue.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path int true "Login event ID"
+// @Param review body models.ReviewLogin true "Review note"
+// @Success 200 {object} models.StaffLoginEventResponse "Login reviewed"
+// @Failure 400 {object} map[string]interface{} "Invalid request, login not flagged or already reviewed"
+// @Failure 404 {object} map[string]interface{} "Login not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/logins/{id}/review [post]
+// @Security BearerAuth
+func (h Handler) ReviewLogin(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return
+	}
+	body := new(models.ReviewLogin)
+	if err = context.BindJSON(body); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	event, err := h.LoginEvents.Review(context, uint(id), context.GetUint("userId"), body.Note)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "login not found"})
+		return
+	}
+	if errors.Is(err, usecase.ErrLoginNotSuspicious) || errors.Is(err, usecase.ErrLoginAlreadyReviewed) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	err = h.Audit.Record(context, context.GetUint("userId"), entity.ReviewLoginAction, "login_event", event.ID, body.Note)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewStaffLoginEventResponse(event))
+}
+
+// recordLogin stores a login event and texts the user when it looks
+// suspicious. Recording must never block a login, so failures are only
+// logged.
+func (h Handler) recordLogin(context *gin.Context, user entity.User, mobileNumber, eventType string) {
+	event := entity.NewLoginEvent(user.ID, mobileNumber, eventType, context.ClientIP(), context.Request.UserAgent())
+	if err := h.LoginEvents.Record(context, &event, user.Country); err != nil {
+		log.Printf("could not record login event for %v: %v", mobileNumber, err)
+		return
+	}
+	if !event.IsSuspicious() || user.ID == 0 {
+		return
+	}
+	var reasons []string
+	for _, anomaly := range event.AnomalyList() {
+		reasons = append(reasons, anomalyMessages[anomaly])
+	}
+	message := "someone tried to log in to your account " + strings.Join(reasons, " and ") + ". if it was not you, contact support."
+	if eventType == entity.LoginSucceededEvent {
+		message = "new login to your account " + strings.Join(reasons, " and ") + ". if it was not you, contact support."
+	}
+	if err := h.SMS.Send(context, user.Country, user.MobileNumber, message); err != nil {
+		log.Printf("could not notify user %v about a suspicious login: %v", user.ID, err)
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/validators"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestMobileNumberChange godoc
+// @Summary Request Mobile Number Change
+// @Description Sends a verification code to both the current and the new mobile number of the authenticated user.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param changeMobileNumber body models.ChangeMobileNumber true "New mobile number"
+// @Success 200 {object} map[string]interface{} "OTP codes sent successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid mobile number or other errors"
+// @Failure 409 {object} map[string]interface{} "Mobile number is already in use"
+// @Router /user/me/mobile [post]
+// @Security BearerAuth
+func (h Handler) RequestMobileNumberChange(context *gin.Context) {
+	body := new(models.ChangeMobileNumber)
+	err := context.BindJSON(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	newNumber, err := validators.ParseMobileNumber(body.NewMobileNumber)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	user, err := h.Users.GetUserById(context.GetUint("userId"))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	if newNumber.E164() == user.MobileNumber {
+		context.JSON(http.StatusBadRequest, gin.H{"message": usecase.ErrMobileNumberUnchanged.Error()})
+		return
+	}
+	if h.Users.IsMobileNumberTaken(newNumber.E164()) {
+		context.JSON(http.StatusConflict, gin.H{"message": usecase.ErrMobileNumberTaken.Error()})
+		return
+	}
+	recipients := []struct{ country, mobileNumber string }{
+		{user.Country, user.MobileNumber},
+		{newNumber.Region, newNumber.E164()},
+	}
+	for _, recipient := range recipients {
+		code, err := h.OTP.GenerateCode(context, recipient.mobileNumber, entity.ChangeMobilePurpose)
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+		message := fmt.Sprintf("your mobile number change code is %v", code)
+		err = h.SMS.Send(context, recipient.country, recipient.mobileNumber, message)
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"message": "could not send otp code"})
+			return
+		}
+	}
+	response := gin.H{"message": "otp codes sent", "mobile_number": user.MobileNumber, "new_mobile_number": newNumber.E164()}
+	context.JSON(http.StatusOK, response)
+}
+
+// ConfirmMobileNumberChange godoc
+// @Summary Confirm Mobile Number Change
+// @Description Verifies the codes sent to the current and the new mobile number, switches the account to the new number and issues a new access token. Tokens issued for the old number stop working.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param confirmMobileNumberChange body models.ConfirmMobileNumberChange true "Verification codes"
+// @Success 200 {object} map[string]interface{} "Mobile number changed successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid codes or mobile number"
+// @Failure 409 {object} map[string]interface{} "Mobile number is already in use"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/me/mobile/confirm [post]
+// @Security BearerAuth
+func (h Handler) ConfirmMobileNumberChange(context *gin.Context) {
+	body := new(models.ConfirmMobileNumberChange)
+	err := context.BindJSON(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	newMobileNumber, err := validators.NormalizeMobileNumber(body.NewMobileNumber)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	user, err := h.Users.GetUserById(context.GetUint("userId"))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	err = h.OTP.ValidateCode(context, user.MobileNumber, entity.ChangeMobilePurpose, body.OldCode)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("current mobile number: %v", err.Error())})
+		return
+	}
+	err = h.OTP.ValidateCode(context, newMobileNumber, entity.ChangeMobilePurpose, body.NewCode)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("new mobile number: %v", err.Error())})
+		return
+	}
+	user, err = h.Users.ChangeMobileNumber(user.ID, newMobileNumber)
+	if err != nil {
+		respondMobileNumberChangeError(context, err)
+		return
+	}
+	required, enabled, err := h.secondFactorStatus(context, user)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	accessToken, _, err := h.accessTokenFor(user, required, enabled)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"token": accessToken, "user": models.NewUserResponse(user)})
+}
+
+// AdminChangeMobileNumber godoc
+// @Summary Change User Mobile Number
+// @Description Lets support staff move a user to a new mobile number without OTP verification. The reason is stored in the audit log.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param changeMobileNumber body models.AdminChangeMobileNumber true "New mobile number and reason"
+// @Success 200 {object} models.UserResponse "Mobile number changed successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or mobile number"
+// @Failure 403 {object} map[string]interface{} "The user holds a role the caller cannot manage"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 409 {object} map[string]interface{} "Mobile number is already in use"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/users/{id}/mobile [put]
+// @Security BearerAuth
+func (h Handler) AdminChangeMobileNumber(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return
+	}
+	body := new(models.AdminChangeMobileNumber)
+	err = context.BindJSON(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if !h.canManageUser(context, uint(id)) {
+		return
+	}
+	oldUser, err := h.Users.GetUserById(uint(id))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	user, err := h.Users.ChangeMobileNumber(uint(id), body.MobileNumber)
+	if err != nil {
+		respondMobileNumberChangeError(context, err)
+		return
+	}
+	details := fmt.Sprintf("%v -> %v: %v", oldUser.MobileNumber, user.MobileNumber, body.Reason)
+	err = h.Audit.Record(context, context.GetUint("userId"), entity.ChangeMobileNumberAction, "user", user.ID, details)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewUserResponse(user))
+}
+
+func respondMobileNumberChangeError(context *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrMobileNumberTaken):
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case errors.Is(err, usecase.ErrMobileNumberUnchanged), errors.Is(err, validators.ErrInvalidMobileNumber):
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	default:
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+	}
+}
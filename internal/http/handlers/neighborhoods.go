@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateNeighborhood godoc
+// @Summary Create Neighborhood
+// @Description Adds a neighborhood to a district.
+// @Tags districts
+// @Accept json
+// @Produce json
+// @Param districtId path int true "District ID"
+// @Param neighborhood body models.Neighborhood true "Neighborhood"
+// @Success 201 {object} models.NeighborhoodResponse "Created neighborhood"
+// @Failure 400 {object} map[string]interface{} "Invalid ID or body"
+// @Failure 404 {object} map[string]interface{} "District not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/districts/{districtId}/neighborhoods [post]
+// @Security BearerAuth
+func (h Handler) CreateNeighborhood(context *gin.Context) {
+	district, ok := h.neighborhoodDistrict(context)
+	if !ok {
+		return
+	}
+	body := new(models.Neighborhood)
+	if err := context.BindJSON(body); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	neighborhood, err := h.Neighborhoods.Create(context, body.Title, district)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	neighborhood.District = district
+	context.JSON(http.StatusCreated, models.NewNeighborhoodResponse(neighborhood))
+}
+
+// NeighborhoodList godoc
+// @Summary Neighborhoods
+// @Description Lists a district's neighborhoods.
+// @Tags districts
+// @Produce json
+// @Param districtId path int true "District ID"
+// @Param title query string false "Part of the title"
+// @Param page query int false "Page number" default(1)
+// @Param page-size query int false "Number of neighborhoods per page" default(10)
+// @Success 200 {object} utils.PaginatedResponse{result=[]models.NeighborhoodResponse} "Neighborhoods"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "District not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/districts/{districtId}/neighborhoods [get]
+func (h Handler) NeighborhoodList(context *gin.Context) {
+	district, ok := h.neighborhoodDistrict(context)
+	if !ok {
+		return
+	}
+	pageSize := utils.ParseQueryParamToInt(context.Query("page-size"), 10)
+	pageNumber := utils.ParseQueryParamToInt(context.Query("page"), 1)
+	neighborhoods, count, err := h.Neighborhoods.List(context, district.ID, pageNumber, pageSize, context.Query("title"))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	response := utils.GenerateListResponse(models.NewNeighborhoodListResponse(neighborhoods), count, pageSize, pageNumber)
+	context.JSON(http.StatusOK, response)
+}
+
+// RetrieveNeighborhood godoc
+// @Summary Neighborhood
+// @Description Returns a neighborhood of a district with its path.
+// @Tags districts
+// @Produce json
+// @Param districtId path int true "District ID"
+// @Param neighborhoodId path int true "Neighborhood ID"
+// @Success 200 {object} models.NeighborhoodResponse "Neighborhood"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "Neighborhood not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/districts/{districtId}/neighborhoods/{neighborhoodId} [get]
+func (h Handler) RetrieveNeighborhood(context *gin.Context) {
+	neighborhood, ok := h.districtNeighborhood(context)
+	if !ok {
+		return
+	}
+	context.JSON(http.StatusOK, models.NewNeighborhoodResponse(neighborhood))
+}
+
+// UpdateNeighborhood godoc
+// @Summary Update Neighborhood
+// @Description Renames a neighborhood of a district.
+// @Tags districts
+// @Accept json
+// @Produce json
+// @Param districtId path int true "District ID"
+// @Param neighborhoodId path int true "Neighborhood ID"
+// @Param neighborhood body models.Neighborhood true "Neighborhood"
+// @Success 200 {object} models.NeighborhoodResponse "Updated neighborhood"
+// @Failure 400 {object} map[string]interface{} "Invalid ID or body"
+// @Failure 404 {object} map[string]interface{} "Neighborhood not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/districts/{districtId}/neighborhoods/{neighborhoodId} [put]
+// @Security BearerAuth
+func (h Handler) UpdateNeighborhood(context *gin.Context) {
+	neighborhood, ok := h.districtNeighborhood(context)
+	if !ok {
+		return
+	}
+	body := new(models.Neighborhood)
+	if err := context.BindJSON(body); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	neighborhood, err := h.Neighborhoods.Update(context, neighborhood.ID, map[string]any{"title": body.Title})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewNeighborhoodResponse(neighborhood))
+}
+
+// DeleteNeighborhood godoc
+// @Summary Delete Neighborhood
+// @Description Deletes a neighborhood of a district.
+// @Tags districts
+// @Param districtId path int true "District ID"
+// @Param neighborhoodId path int true "Neighborhood ID"
+// @Success 204 "Neighborhood deleted"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "Neighborhood not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/districts/{districtId}/neighborhoods/{neighborhoodId} [delete]
+// @Security BearerAuth
+func (h Handler) DeleteNeighborhood(context *gin.Context) {
+	neighborhood, ok := h.districtNeighborhood(context)
+	if !ok {
+		return
+	}
+	if err := h.Neighborhoods.DeleteById(context, neighborhood.ID); err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	context.Status(http.StatusNoContent)
+}
+
+// neighborhoodDistrict loads the district of the districtId parameter,
+// writing the error response when it can't.
+func (h Handler) neighborhoodDistrict(context *gin.Context) (entity.District, bool) {
+	districtId, ok := locationId(context, "districtId")
+	if !ok {
+		return entity.District{}, false
+	}
+	district, err := h.Districts.ById(context, districtId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "district not found"})
+		return entity.District{}, false
+	} else if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return entity.District{}, false
+	}
+	return district, true
+}
+
+// districtNeighborhood loads the neighborhood of the neighborhoodId
+// parameter when it belongs to the district of the districtId parameter.
+func (h Handler) districtNeighborhood(context *gin.Context) (entity.Neighborhood, bool) {
+	districtId, ok := locationId(context, "districtId")
+	if !ok {
+		return entity.Neighborhood{}, false
+	}
+	neighborhoodId, ok := locationId(context, "neighborhoodId")
+	if !ok {
+		return entity.Neighborhood{}, false
+	}
+	neighborhood, err := h.Neighborhoods.ById(context, neighborhoodId)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && neighborhood.DistrictID != districtId) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "neighborhood not found"})
+		return entity.Neighborhood{}, false
+	} else if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return entity.Neighborhood{}, false
+	}
+	return neighborhood, true
+}
@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/validators"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OAuthAuthorizeInfo godoc
+// @Summary OAuth2 Authorization Request
+// @Description Validates an authorization request and describes the client and scopes the user is asked to consent to. Only the code response type with a S256 PKCE challenge is supported.
+// @Tags OAuth
+// @Produce json
+// @Param response_type query string true "Must be code"
+// @Param client_id query string true "Client ID"
+// @Param redirect_uri query string true "Registered redirect URI"
+// @Param scope query string false "Space separated scopes"
+// @Param code_challenge query string true "PKCE code challenge"
+// @Param code_challenge_method query string true "Must be S256"
+// @Success 200 {object} models.OAuthAuthorizeInfo "Consent details"
+// @Failure 400 {object} models.OAuthErrorResponse "Invalid authorization request"
+// @Router /user/oauth/authorize [get]
+func (h Handler) OAuthAuthorizeInfo(context *gin.Context) {
+	if context.Query("response_type") != "code" {
+		context.JSON(http.StatusBadRequest, models.OAuthErrorResponse{Error: "unsupported_response_type", ErrorDescription: "only the code response type is supported"})
+		return
+	}
+	client, scope, err := h.OAuth.ValidateAuthorizeRequest(
+		context,
+		context.Query("client_id"),
+		context.Query("redirect_uri"),
+		context.Query("scope"),
+		context.Query("code_challenge"),
+		context.Query("code_challenge_method"),
+	)
+	if err != nil {
+		respondOAuthError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, models.OAuthAuthorizeInfo{ClientName: client.Name, RedirectURI: context.Query("redirect_uri"), Scope: scope})
+}
+
+// OAuthAuthorize godoc
+// @Summary OAuth2 Consent
+// @Description Grants the client access on behalf of the user. The user proves ownership of the account with a login code requested from /user/authenticate, plus a TOTP or recovery code when two-factor authentication is enabled. The response holds the redirect URI carrying the authorization code and state.
+// @Tags OAuth
+// @Accept json
+// @Produce json
+// @Param consent body models.OAuthAuthorize true "Authorization request and login code"
+// @Success 200 {object} models.OAuthAuthorizeResponse "Redirect URI with the authorization code"
+// @Failure 400 {object} models.OAuthErrorResponse "Invalid authorization request or login code"
+// @Failure 403 {object} models.OAuthErrorResponse "Account suspended"
+// @Router /user/oauth/authorize [post]
+func (h Handler) OAuthAuthorize(context *gin.Context) {
+	body := new(models.OAuthAuthorize)
+	err := context.BindJSON(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, models.OAuthErrorResponse{Error: "invalid_request", ErrorDescription: err.Error()})
+		return
+	}
+	_, _, err = h.OAuth.ValidateAuthorizeRequest(context, body.ClientId, body.RedirectURI, body.Scope, body.CodeChallenge, body.CodeChallengeMethod)
+	if err != nil {
+		respondOAuthError(context, err)
+		return
+	}
+	mobileNumber, err := validators.NormalizeMobileNumber(body.MobileNumber)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, models.OAuthErrorResponse{Error: "access_denied", ErrorDescription: err.Error()})
+		return
+	}
+	user := entity.User{}
+	userErr := h.Users.Repo.ByMobileNumber(mobileNumber, &user).Error
+	err = h.OTP.ValidateCode(context, mobileNumber, entity.LoginPurpose, body.Code)
+	if err != nil {
+		h.recordLogin(context, user, mobileNumber, entity.LoginFailedEvent)
+		context.JSON(http.StatusBadRequest, models.OAuthErrorResponse{Error: "access_denied", ErrorDescription: err.Error()})
+		return
+	}
+	if userErr != nil {
+		context.JSON(http.StatusBadRequest, models.OAuthErrorResponse{Error: "access_denied", ErrorDescription: "user not found"})
+		return
+	}
+	if user.IsSuspended(time.Now()) {
+		context.JSON(http.StatusForbidden, models.OAuthErrorResponse{Error: "access_denied", ErrorDescription: "account suspended"})
+		return
+	}
+	required, enabled, err := h.secondFactorStatus(context, user)
+	if err != nil {
+		respondOAuthError(context, err)
+		return
+	}
+	if required && !enabled {
+		context.JSON(http.StatusBadRequest, models.OAuthErrorResponse{Error: "access_denied", ErrorDescription: "two-factor enrollment required"})
+		return
+	}
+	if enabled {
+		err = h.TwoFactor.Verify(context, user.ID, body.TOTPCode, body.RecoveryCode)
+		if errors.Is(err, usecase.ErrTwoFactorRequired) || errors.Is(err, usecase.ErrTwoFactorInvalid) {
+			h.recordLogin(context, user, mobileNumber, entity.LoginFailedEvent)
+			context.JSON(http.StatusBadRequest, models.OAuthErrorResponse{Error: "access_denied", ErrorDescription: err.Error()})
+			return
+		}
+		if err != nil {
+			respondOAuthError(context, err)
+			return
+		}
+	}
+	code, err := h.OAuth.Authorize(context, body.ClientId, user.ID, body.RedirectURI, body.Scope, body.CodeChallenge, body.CodeChallengeMethod)
+	if err != nil {
+		respondOAuthError(context, err)
+		return
+	}
+	h.recordLogin(context, user, mobileNumber, entity.LoginSucceededEvent)
+	redirectURI, _ := url.Parse(body.RedirectURI)
+	query := redirectURI.Query()
+	query.Set("code", code)
+	if body.State != "" {
+		query.Set("state", body.State)
+	}
+	redirectURI.RawQuery = query.Encode()
+	context.JSON(http.StatusOK, models.OAuthAuthorizeResponse{RedirectURI: redirectURI.String()})
+}
+
+// OAuthToken godoc
+// @Summary OAuth2 Token
+// @Description Issues tokens for the authorization_code (with PKCE), client_credentials and refresh_token grants. Clients authenticate with HTTP Basic or the client_id and client_secret fields. Refresh tokens are rotated on use.
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "authorization_code, client_credentials or refresh_token"
+// @Param code formData string false "Authorization code"
+// @Param redirect_uri formData string false "Redirect URI used in the authorization request"
+// @Param code_verifier formData string false "PKCE code verifier"
+// @Param refresh_token formData string false "Refresh token"
+// @Param scope formData string false "Space separated scopes"
+// @Param client_id formData string false "Client ID"
+// @Param client_secret formData string false "Client secret"
+// @Success 200 {object} models.OAuthTokenResponse "Issued tokens"
+// @Failure 400 {object} models.OAuthErrorResponse "Invalid grant or request"
+// @Failure 401 {object} models.OAuthErrorResponse "Client authentication failed"
+// @Router /user/oauth/token [post]
+func (h Handler) OAuthToken(context *gin.Context) {
+	body := new(models.OAuthTokenRequest)
+	if err := context.ShouldBind(body); err != nil {
+		context.JSON(http.StatusBadRequest, models.OAuthErrorResponse{Error: "invalid_request", ErrorDescription: err.Error()})
+		return
+	}
+	client, err := authenticateOAuthClient(context, h.OAuth, body.ClientId, body.ClientSecret)
+	if err != nil {
+		respondOAuthError(context, err)
+		return
+	}
+	var tokens usecase.OAuthTokens
+	switch body.GrantType {
+	case entity.AuthorizationCodeGrant:
+		tokens, err = h.OAuth.ExchangeCode(context, client, body.Code, body.RedirectURI, body.CodeVerifier)
+	case entity.ClientCredentialsGrant:
+		tokens, err = h.OAuth.ClientCredentials(context, client, body.Scope)
+	case entity.RefreshTokenGrant:
+		tokens, err = h.OAuth.Refresh(context, client, body.RefreshToken, body.Scope)
+	default:
+		err = usecase.ErrOAuthUnsupported
+	}
+	if err != nil {
+		respondOAuthError(context, err)
+		return
+	}
+	context.Header("Cache-Control", "no-store")
+	context.JSON(http.StatusOK, models.OAuthTokenResponse{
+		AccessToken:  tokens.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+		RefreshToken: tokens.RefreshToken,
+		Scope:        tokens.Scope,
+	})
+}
+
+// OAuthIntrospect godoc
+// @Summary OAuth2 Token Introspection
+// @Description Describes an access or refresh token as defined by RFC 7662. Only confidential clients may introspect tokens.
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Token to introspect"
+// @Param client_id formData string false "Client ID"
+// @Param client_secret formData string false "Client secret"
+// @Success 200 {object} models.OAuthIntrospectionResponse "Token details"
+// @Failure 401 {object} models.OAuthErrorResponse "Client authentication failed"
+// @Router /user/oauth/introspect [post]
+func (h Handler) OAuthIntrospect(context *gin.Context) {
+	body := new(models.OAuthTokenActionRequest)
+	if err := context.ShouldBind(body); err != nil {
+		context.JSON(http.StatusBadRequest, models.OAuthErrorResponse{Error: "invalid_request", ErrorDescription: err.Error()})
+		return
+	}
+	client, err := authenticateOAuthClient(context, h.OAuth, body.ClientId, body.ClientSecret)
+	if err == nil && !client.IsConfidential() {
+		err = usecase.ErrOAuthUnauthorized
+	}
+	if err != nil {
+		respondOAuthError(context, err)
+		return
+	}
+	introspection, err := h.OAuth.Introspect(context, body.Token)
+	if err != nil {
+		respondOAuthError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, models.OAuthIntrospectionResponse{
+		Active:    introspection.Active,
+		TokenType: introspection.TokenType,
+		ClientId:  introspection.ClientId,
+		UserId:    introspection.UserId,
+		Scope:     introspection.Scope,
+		Exp:       introspection.ExpiresAt,
+	})
+}
+
+// OAuthRevoke godoc
+// @Summary OAuth2 Token Revocation
+// @Description Revokes an access or refresh token issued to the calling client, as defined by RFC 7009. Unknown tokens are ignored.
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Token to revoke"
+// @Param token_type_hint formData string false "access_token or refresh_token"
+// @Param client_id formData string false "Client ID"
+// @Param client_secret formData string false "Client secret"
+// @Success 200 "Token revoked"
+// @Failure 401 {object} models.OAuthErrorResponse "Client authentication failed"
+// @Router /user/oauth/revoke [post]
+func (h Handler) OAuthRevoke(context *gin.Context) {
+	body := new(models.OAuthTokenActionRequest)
+	if err := context.ShouldBind(body); err != nil {
+		context.JSON(http.StatusBadRequest, models.OAuthErrorResponse{Error: "invalid_request", ErrorDescription: err.Error()})
+		return
+	}
+	client, err := authenticateOAuthClient(context, h.OAuth, body.ClientId, body.ClientSecret)
+	if err != nil {
+		respondOAuthError(context, err)
+		return
+	}
+	if err := h.OAuth.Revoke(context, client, body.Token); err != nil {
+		respondOAuthError(context, err)
+		return
+	}
+	context.Status(http.StatusOK)
+}
+
+// RegisterOAuthClient godoc
+// @Summary Register OAuth2 Client
+// @Description Registers a third-party application. The client secret of confidential clients is only returned in this response.
+// @Tags OAuth
+// @Accept json
+// @Produce json
+// @Param client body models.RegisterOAuthClient true "Client data"
+// @Success 201 {object} models.RegisteredOAuthClientResponse "Registered client"
+// @Failure 400 {object} map[string]interface{} "Invalid request body, scope or redirect URI"
+// @Router /user/oauth/clients [post]
+// @Security BearerAuth
+func (h Handler) RegisterOAuthClient(context *gin.Context) {
+	body := new(models.RegisterOAuthClient)
+	err := context.BindJSON(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	client, secret, err := h.OAuth.RegisterClient(context, body.Name, body.RedirectURIs, body.Scopes, body.Confidential)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	context.JSON(http.StatusCreated, models.RegisteredOAuthClientResponse{Client: models.NewOAuthClientResponse(client), ClientSecret: secret})
+}
+
+// OAuthClientList godoc
+// @Summary List OAuth2 Clients
+// @Description Lists the registered third-party applications.
+// @Tags OAuth
+// @Produce json
+// @Success 200 {object} []models.OAuthClientResponse "List of clients"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/oauth/clients [get]
+// @Security BearerAuth
+func (h Handler) OAuthClientList(context *gin.Context) {
+	clients, err := h.OAuth.ListClients(context)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewOAuthClientListResponse(clients))
+}
+
+// DeleteOAuthClient godoc
+// @Summary Delete OAuth2 Client
+// @Description Deletes a client. Access tokens issued to it stop working immediately.
+// @Tags OAuth
+// @Produce json
+// @Param id path int true "Client ID"
+// @Success 204 "Client deleted successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid endpoint"
+// @Failure 404 {object} map[string]interface{} "Client not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/oauth/clients/{id} [delete]
+// @Security BearerAuth
+func (h Handler) DeleteOAuthClient(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return
+	}
+	err = h.OAuth.DeleteClient(context, uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "client not found"})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusNoContent, nil)
+}
+
+// authenticateOAuthClient prefers HTTP Basic credentials over the ones sent
+// in the form.
+func authenticateOAuthClient(context *gin.Context, oauthUseCase usecase.OAuthUseCase, clientId, clientSecret string) (entity.OAuthClient, error) {
+	if basicId, basicSecret, ok := context.Request.BasicAuth(); ok {
+		clientId, clientSecret = basicId, basicSecret
+	}
+	return oauthUseCase.AuthenticateClient(context, clientId, clientSecret)
+}
+
+func respondOAuthError(context *gin.Context, err error) {
+	var oauthErr usecase.OAuthError
+	if !errors.As(err, &oauthErr) {
+		context.JSON(http.StatusInternalServerError, models.OAuthErrorResponse{Error: "server_error", ErrorDescription: "something went wrong!"})
+		return
+	}
+	status := http.StatusBadRequest
+	if oauthErr == usecase.ErrOAuthInvalidClient {
+		status = http.StatusUnauthorized
+	}
+	context.JSON(status, models.OAuthErrorResponse{Error: oauthErr.Code, ErrorDescription: oauthErr.Description})
+}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RoleList godoc
+// @Summary List Roles
+// @Description Lists every role together with the permissions it grants.
+// @Tags Roles
+// @Produce json
+// @Success 200 {object} []models.RoleResponse "List of roles"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/roles [get]
+// @Security BearerAuth
+func (h Handler) RoleList(context *gin.Context) {
+	roles, err := h.Roles.List(context)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewRoleListResponse(roles))
+}
+
+// CreateRole godoc
+// @Summary Create Role
+// @Description Creates a custom role with the given permissions.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param role body models.Role true "Role data"
+// @Success 201 {object} models.RoleResponse "Created role"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or permission"
+// @Failure 403 {object} map[string]interface{} "The role grants permissions the caller does not hold"
+// @Failure 409 {object} map[string]interface{} "Role already exists"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/roles [post]
+// @Security BearerAuth
+func (h Handler) CreateRole(context *gin.Context) {
+	body := new(models.Role)
+	err := context.BindJSON(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if !checkPermissionsGrantable(context, h.Roles, body.Permissions) {
+		return
+	}
+	role, err := h.Roles.Create(context, body.Name, body.Permissions)
+	if errors.Is(err, usecase.ErrRoleExists) {
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	context.JSON(http.StatusCreated, models.NewRoleResponse(role))
+}
+
+// UpdateRole godoc
+// @Summary Update Role Permissions
+// @Description Replaces the permission set of a role.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param id path int true "Role ID"
+// @Param permissions body models.RolePermissions true "New permission set"
+// @Success 200 {object} models.RoleResponse "Updated role"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or permission"
+// @Failure 403 {object} map[string]interface{} "The role grants permissions the caller does not hold, or is the admin role"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/roles/{id} [put]
+// @Security BearerAuth
+func (h Handler) UpdateRole(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return
+	}
+	body := new(models.RolePermissions)
+	err = context.BindJSON(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	role, err := h.Roles.ById(context, uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "role not found"})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	// the caller has to hold what the role grants now as well as what it
+	// is about to grant, so nobody edits a role above their own
+	if !checkPermissionsGrantable(context, h.Roles, append(role.PermissionNames(), body.Permissions...)) {
+		return
+	}
+	role, err = h.Roles.UpdatePermissions(context, uint(id), body.Permissions)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "role not found"})
+		return
+	}
+	if errors.Is(err, usecase.ErrBuiltInRole) {
+		context.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewRoleResponse(role))
+}
+
+// DeleteRole godoc
+// @Summary Delete Role
+// @Description Deletes a custom role that is not assigned to any user.
+// @Tags Roles
+// @Produce json
+// @Param id path int true "Role ID"
+// @Success 204 "Role deleted successfully"
+// @Failure 400 {object} map[string]interface{} "Default role or role in use"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/roles/{id} [delete]
+// @Security BearerAuth
+func (h Handler) DeleteRole(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return
+	}
+	err = h.Roles.DeleteById(context, uint(id))
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		context.JSON(http.StatusNotFound, gin.H{"message": "role not found"})
+	case errors.Is(err, usecase.ErrDefaultRole), errors.Is(err, usecase.ErrRoleInUse):
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case err != nil:
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+	default:
+		context.JSON(http.StatusNoContent, nil)
+	}
+}
+
+// AssignRole godoc
+// @Summary Assign Role
+// @Description Assigns a role to a user. Neither the user's current role nor the new one may grant permissions the caller does not hold, admins removing their own admin role have to send confirm, and the last active admin can not be demoted.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param role body models.AssignRole true "Role to assign"
+// @Success 200 {object} models.UserResponse "User with the new role"
+// @Failure 400 {object} map[string]interface{} "Invalid role or unconfirmed self-demotion"
+// @Failure 403 {object} map[string]interface{} "The user holds a role the caller cannot manage"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 409 {object} map[string]interface{} "The last active admin can not be demoted"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/users/{id}/role [put]
+// @Security BearerAuth
+func (h Handler) AssignRole(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return
+	}
+	body := new(models.AssignRole)
+	err = context.BindJSON(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if !h.canManageUser(context, uint(id)) {
+		return
+	}
+	if !checkRoleGrantable(context, h.Roles, body.Role) {
+		return
+	}
+	user, err := h.Users.GetUserById(uint(id))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	if !checkRoleChange(context, h.Users, user, body.Role, body.Confirm) {
+		return
+	}
+	err = h.Users.Update(context, uint(id), map[string]any{"role": body.Role})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	user, err = h.Users.GetUserById(uint(id))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewUserResponse(user))
+}
+
+// checkRoleGrantable writes the error response and returns false when the
+// caller may not hand out the given role.
+func checkRoleGrantable(context *gin.Context, roleUseCase usecase.RoleUseCase, role string) bool {
+	if !roleUseCase.DoesRoleExist(context, role) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid role"})
+		return false
+	}
+	grantable, err := roleUseCase.CanGrant(context, context.GetString("role"), role)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return false
+	}
+	if !grantable {
+		context.JSON(http.StatusBadRequest, gin.H{"message": usecase.ErrRoleNotGrantable.Error()})
+		return false
+	}
+	return true
+}
+
+// checkPermissionsGrantable writes the error response and returns false when
+// the caller does not hold every one of permissions.
+func checkPermissionsGrantable(context *gin.Context, roleUseCase usecase.RoleUseCase, permissions []string) bool {
+	grantable, err := roleUseCase.CanGrantPermissions(context, context.GetString("role"), permissions)
+	if errors.Is(err, usecase.ErrInvalidPermission) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return false
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return false
+	}
+	if !grantable {
+		context.JSON(http.StatusForbidden, gin.H{"message": usecase.ErrPermissionsNotHeld.Error()})
+		return false
+	}
+	return true
+}
+
+func checkRoleChange(context *gin.Context, userUseCase usecase.UserUseCase, user entity.User, role string, confirmed bool) bool {
+	err := userUseCase.CheckRoleChange(context.GetUint("userId"), user, role, confirmed)
+	if errors.Is(err, usecase.ErrSelfDemotion) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error(), "confirmation_required": true})
+		return false
+	}
+	if errors.Is(err, usecase.ErrLastAdmin) {
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return false
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return false
+	}
+	return true
+}
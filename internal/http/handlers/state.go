@@ -1,13 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
-	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
-	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
 	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
 	"github.com/gin-gonic/gin"
@@ -16,28 +15,36 @@ import (
 // CreateState handles the creation of a new state.
 //
 // @Summary      Create a new state
-// @Description  This endpoint creates a new state record in the database.
+// @Description  This endpoint creates a new state record in the database. Latitude and longitude are optional and given together, the boundary is an optional GeoJSON Polygon or MultiPolygon.
 // @Tags         states
 // @Accept       json
 // @Produce      json
 // @Param        state  body      models.State         true  "State data"
 // @Success      201    {object}  models.StateResponse  "Created state"
 // @Failure      400    {object}  map[string]string     "Bad request"
+// @Failure      409    {object}  map[string]string     "A state with the same title exists"
 // @Failure      500    {object}  map[string]string     "Internal server error"
 // @Router       /settings/states [post]
 // @Security BearerAuth
-func CreateState(context *gin.Context) {
+func (h Handler) CreateState(context *gin.Context) {
 	body := new(models.State)
 	err := context.BindJSON(body)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
+	location, err := body.Location()
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
 	state := entity.NewState(body.Title)
-	db := database.GetDb()
-	repo := repository.NewStateRepository(db)
-	useCase := usecase.NewStateUseCase(repo)
-	err = useCase.Create(context, &state)
+	state.Location = location
+	err = h.States.Create(context, &state)
+	if errors.Is(err, usecase.ErrStateTitleTaken) {
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -56,27 +63,29 @@ func CreateState(context *gin.Context) {
 // @Produce      json
 // @Param        page        query     int    false  "Page number"         default(1)
 // @Param        page-size   query     int    false  "Page size"           default(10)
-// @Param        title       query     string false  "Filter by state title"
+// @Param        title       query     string false  "Filter by state title in any locale"
+// @Param        lang        query     string false  "Locale of the titles, Accept-Language is used when omitted" Enums(fa, en, ar)
 // @Success      200         {object}  utils.PaginatedResponse{result=[]models.StateResponse}  "List of states"
 // @Failure      500         {object}  map[string]string     "Internal server error"
 // @Router       /settings/states [get]
-func StateList(context *gin.Context) {
-	db := database.GetDb()
-	repo := repository.NewStateRepository(db)
-	useCase := usecase.NewStateUseCase(repo)
+func (h Handler) StateList(context *gin.Context) {
 	pageSize := utils.ParseQueryParamToInt(context.Query("page-size"), 10)
 	pageNumber := utils.ParseQueryParamToInt(context.Query("page"), 1)
 	title := context.Query("title")
-	states, err := useCase.GetStateList(context, pageNumber, pageSize, title)
+	states, err := h.States.GetStateList(context, pageNumber, pageSize, title)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong"})
 		return
 	}
-	usersCount, err := useCase.Count(context)
+	usersCount, err := h.States.Count(context)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong"})
 		return
 	}
+	locale := requestLocale(context)
+	for i := range states {
+		states[i] = states[i].Localized(locale)
+	}
 	userResponse := models.NewStateListResponse(states)
 	response := utils.GenerateListResponse(userResponse, usersCount, pageSize, pageNumber)
 	context.JSON(http.StatusOK, response)
@@ -90,37 +99,35 @@ func StateList(context *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int   true   "State ID"
+// @Param        lang query     string false "Locale of the title, Accept-Language is used when omitted" Enums(fa, en, ar)
 // @Success      200  {object}  models.StateResponse  "State details"
 // @Failure      400  {object}  map[string]string     "Invalid state ID"
 // @Failure      404  {object}  map[string]string     "State not found"
 // @Failure      500  {object}  map[string]string     "Failed to retrieve state"
 // @Router       /settings/states/{id} [get]
-func RetrieveState(context *gin.Context) {
+func (h Handler) RetrieveState(context *gin.Context) {
 	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	db := database.GetDb()
-	repo := repository.NewStateRepository(db)
-	useCase := usecase.NewStateUseCase(repo)
-	if !useCase.DoesStateExist(context, uint(id)) {
+	if !h.States.DoesStateExist(context, uint(id)) {
 		context.JSON(http.StatusNotFound, gin.H{"message": "state not found"})
 		return
 	}
-	state, err := useCase.GetStateById(context, uint(id))
+	state, err := h.States.GetStateById(context, uint(id))
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	response := models.NewStateResponse(state)
+	response := models.NewStateResponse(state.Localized(requestLocale(context)))
 	context.JSON(http.StatusOK, response)
 }
 
 // UpdateState updates a specific state by its ID.
 //
 // @Summary      Update state by ID
-// @Description  This endpoint updates the details of a specific state by its ID.
+// @Description  This endpoint updates the details of a specific state by its ID. Coordinates and boundary are only changed when given.
 // @Tags         states
 // @Accept       json
 // @Produce      json
@@ -129,19 +136,17 @@ func RetrieveState(context *gin.Context) {
 // @Success      200   {object}  models.StateResponse  "Updated state"
 // @Failure      400   {object}  map[string]string     "Invalid request"
 // @Failure      404   {object}  map[string]string     "State not found"
+// @Failure      409   {object}  map[string]string     "A state with the same title exists"
 // @Failure      500   {object}  map[string]string     "Failed to update state"
 // @Router       /settings/states/{id} [put]
 // @Security BearerAuth
-func UpdateState(context *gin.Context) {
+func (h Handler) UpdateState(context *gin.Context) {
 	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	db := database.GetDb()
-	repo := repository.NewStateRepository(db)
-	useCase := usecase.NewStateUseCase(repo)
-	if !useCase.DoesStateExist(context, uint(id)) {
+	if !h.States.DoesStateExist(context, uint(id)) {
 		context.JSON(http.StatusNotFound, gin.H{"message": "state not found"})
 		return
 	}
@@ -151,12 +156,22 @@ func UpdateState(context *gin.Context) {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	err = useCase.Update(context, uint(id), map[string]any{"title": body.Title})
+	updateInfo, err := body.LocationUpdates()
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	updateInfo["title"] = body.Title
+	err = h.States.Update(context, uint(id), updateInfo)
+	if errors.Is(err, usecase.ErrStateTitleTaken) {
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
-	state, err := useCase.GetStateById(context, uint(id))
+	state, err := h.States.GetStateById(context, uint(id))
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
@@ -168,34 +183,47 @@ func UpdateState(context *gin.Context) {
 // DeleteState deletes a specific state by its ID.
 //
 // @Summary      Delete state by ID
-// @Description  This endpoint deletes a specific state from the database using its ID.
+// @Description  This endpoint deletes a specific state from the database using its ID. A state that still has cities is kept unless its cities are deleted with it (cascade) or moved to the target state (reassign).
 // @Tags         states
 // @Accept       json
 // @Produce      json
-// @Param        id   path      int   true   "State ID"
+// @Param        id      path      int     true   "State ID"
+// @Param        cities  query     string  false  "What to do with the state's cities, the configured mode when omitted" Enums(reject, cascade, reassign)
+// @Param        target  query     int     false  "State that receives the cities when reassigning"
 // @Success      204  "State deleted successfully"
-// @Failure      400  {object}  map[string]string  "Invalid state ID"
+// @Failure      400  {object}  map[string]string  "Invalid state ID, mode or target"
 // @Failure      404  {object}  map[string]string  "State not found"
+// @Failure      409  {object}  map[string]string  "State still has cities or the target has a city with the same title"
 // @Failure      500  {object}  map[string]string  "Failed to delete state"
 // @Router       /settings/states/{id} [delete]
 // @Security BearerAuth
-func DeleteState(context *gin.Context) {
+func (h Handler) DeleteState(context *gin.Context) {
 	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	db := database.GetDb()
-	repo := repository.NewStateRepository(db)
-	useCase := usecase.NewStateUseCase(repo)
-	if !useCase.DoesStateExist(context, uint(id)) {
+	if !h.States.DoesStateExist(context, uint(id)) {
 		context.JSON(http.StatusNotFound, gin.H{"message": "state not found"})
 		return
 	}
-	err = useCase.DeleteById(context, uint(id))
-	if err != nil {
+	mode := context.DefaultQuery("cities", h.States.DeletionMode)
+	var targetId uint64
+	if target := context.Query("target"); target != "" {
+		if targetId, err = strconv.ParseUint(target, 10, 64); err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"message": "invalid target state"})
+			return
+		}
+	}
+	err = h.States.Delete(context, uint(id), mode, uint(targetId))
+	switch {
+	case errors.Is(err, usecase.ErrInvalidDeletionMode), errors.Is(err, usecase.ErrInvalidReassignment):
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case errors.Is(err, usecase.ErrStateInUse), errors.Is(err, usecase.ErrCityTitleTaken):
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case err != nil:
 		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
+	default:
+		context.JSON(http.StatusNoContent, nil)
 	}
-	context.JSON(http.StatusNoContent, nil)
 }
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SuspendUser godoc
+// @Summary Suspend User
+// @Description Locks a user out until ends_at, or indefinitely (a ban) when it is omitted. Suspending a suspended user replaces the running suspension. Notes are internal and never shown to the user.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param suspension body models.SuspendUser true "Reason (fraud, abuse, spam, chargeback or other), notes and optional end time"
+// @Success 200 {object} models.UserResponse "User suspended"
+// @Failure 400 {object} map[string]interface{} "Invalid request, reason or end time"
+// @Failure 403 {object} map[string]interface{} "Forbidden: insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 409 {object} map[string]interface{} "The last active admin can not be suspended"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/users/{id}/suspend [post]
+// @Security BearerAuth
+func (h Handler) SuspendUser(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return
+	}
+	body := new(models.SuspendUser)
+	err = context.BindJSON(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if !h.canManageUser(context, uint(id)) {
+		return
+	}
+	user, err := h.Suspensions.Suspend(context, context.GetUint("userId"), uint(id), body.Reason, body.Notes, body.EndsAt)
+	if errors.Is(err, usecase.ErrInvalidSuspensionReason) || errors.Is(err, usecase.ErrSuspensionEnd) || errors.Is(err, usecase.ErrSuspendSelf) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if errors.Is(err, usecase.ErrLastAdmin) {
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	err = h.Audit.Record(context, context.GetUint("userId"), entity.SuspendUserAction, "user", uint(id), body.Reason)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewUserResponse(user))
+}
+
+// UnsuspendUser godoc
+// @Summary Lift User Suspension
+// @Description Lifts the running suspension or ban of a user.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param notes body models.UnsuspendUser false "Internal notes"
+// @Success 200 {object} models.UserResponse "Suspension lifted"
+// @Failure 400 {object} map[string]interface{} "Invalid request or user not suspended"
+// @Failure 403 {object} map[string]interface{} "Forbidden: insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/users/{id}/unsuspend [post]
+// @Security BearerAuth
+func (h Handler) UnsuspendUser(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return
+	}
+	body := new(models.UnsuspendUser)
+	if context.Request.ContentLength > 0 {
+		if err = context.BindJSON(body); err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+	if !h.canManageUser(context, uint(id)) {
+		return
+	}
+	user, err := h.Suspensions.Unsuspend(context, context.GetUint("userId"), uint(id), body.Notes)
+	if errors.Is(err, usecase.ErrNotSuspended) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	err = h.Audit.Record(context, context.GetUint("userId"), entity.UnsuspendUserAction, "user", uint(id), body.Notes)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewUserResponse(user))
+}
+
+// SuspensionHistory godoc
+// @Summary User Suspension History
+// @Description Lists every suspension of a user, newest first, including internal notes.
+// @Tags User
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {array} models.SuspensionResponse "Suspension history"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/users/{id}/suspensions [get]
+// @Security BearerAuth
+func (h Handler) SuspensionHistory(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return
+	}
+	if !h.Users.DoesUserExist(uint(id)) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "user not found"})
+		return
+	}
+	suspensions, err := h.Suspensions.History(context, uint(id))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewSuspensionListResponse(suspensions))
+}
+
+// canManageUser writes the response and returns false when the user does not
+// exist or holds a role the caller could not grant.
+func (h Handler) canManageUser(context *gin.Context, id uint) bool {
+	user, err := h.Users.GetUserById(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "user not found"})
+		return false
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return false
+	}
+	allowed, err := h.Roles.CanGrant(context, context.GetString("role"), user.Role)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return false
+	}
+	if !allowed && err == nil {
+		context.JSON(http.StatusForbidden, gin.H{"message": "you have no permission to perform this action"})
+		return false
+	}
+	return true
+}
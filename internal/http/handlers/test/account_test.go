@@ -0,0 +1,51 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportAccount(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	user, token := createUserAndToken(userRepo, entity.UserRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	w := authorizedRequest(server, "GET", "/user/me/export?format=xml", token, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = authorizedRequest(server, "GET", "/user/me/export", token, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), ".json")
+	response := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, user.MobileNumber, response["user"].(map[string]any)["mobile_number"])
+
+	w = authorizedRequest(server, "GET", "/user/me/export?format=zip", token, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	assert.NoError(t, err)
+	names := []string{}
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	assert.Contains(t, names, "user.json")
+	assert.Contains(t, names, "login_events.json")
+}
@@ -0,0 +1,117 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueAPIKey(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	_, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	body, _ := json.Marshal(map[string]any{"name": "agency", "organization": "Agency", "scopes": []string{entity.UsersReadPermission}})
+	req, _ := http.NewRequest("POST", "/user/api-keys", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	body, _ = json.Marshal(map[string]any{"name": "agency", "scopes": []string{entity.UsersReadPermission}})
+	req, _ = http.NewRequest("POST", "/user/api-keys", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body, _ = json.Marshal(map[string]any{"name": "agency", "organization": "Agency", "scopes": []string{entity.UsersReadPermission}})
+	req, _ = http.NewRequest("POST", "/user/api-keys", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	response := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NotEmpty(t, response["key"])
+
+	req, _ = http.NewRequest("GET", "/user/api-keys", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), response["key"])
+}
+
+func TestAPIKeyAuthentication(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	user, _ := createUserAndToken(userRepo, entity.UserRole)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	issue := func(payload map[string]any) (string, float64) {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", "/user/api-keys", bytes.NewBuffer(body))
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		response := struct {
+			APIKey map[string]any `json:"api_key"`
+			Key    string         `json:"key"`
+		}{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Key, response.APIKey["id"].(float64)
+	}
+	request := func(method, url, key string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, nil)
+		req.Header.Set(entity.APIKeyHeader, key)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	orgKey, orgKeyId := issue(map[string]any{"name": "agency", "organization": "Agency", "scopes": []string{entity.UsersReadPermission}, "rate_limit": 2})
+	assert.Equal(t, http.StatusUnauthorized, request("GET", "/user/users", "rrk_00000000_abc").Code)
+	w := request("GET", "/user/users", orgKey)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, http.StatusForbidden, request("DELETE", fmt.Sprintf("/user/users/%v", user.ID), orgKey).Code)
+	assert.Equal(t, http.StatusTooManyRequests, request("GET", "/user/users", orgKey).Code)
+	assert.Equal(t, http.StatusUnauthorized, request("GET", "/user/api-keys", orgKey).Code)
+
+	req, _ := http.NewRequest("DELETE", fmt.Sprintf("/user/api-keys/%v", orgKeyId), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	redis.InitiateTestClient()
+	assert.Equal(t, http.StatusUnauthorized, request("GET", "/user/users", orgKey).Code)
+
+	// keys bound to a user never exceed the user's role
+	userKey, _ := issue(map[string]any{"name": "own", "user_id": user.ID, "scopes": []string{entity.UsersReadPermission}})
+	assert.Equal(t, http.StatusForbidden, request("GET", "/user/users", userKey).Code)
+}
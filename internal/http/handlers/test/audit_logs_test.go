@@ -0,0 +1,68 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/middlewares"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLogs(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	admin, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+	_, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+	user, _ := createUserAndToken(userRepo, entity.UserRole)
+
+	server := gin.Default()
+	server.Use(middlewares.RequestID)
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	w := authorizedRequest(server, "PUT", fmt.Sprintf("/user/users/%v", user.ID), adminToken, map[string]any{"full_name": "Sara", "role": entity.SupportRole})
+	assert.Equal(t, http.StatusOK, w.Code)
+	requestId := w.Header().Get(middlewares.RequestIDHeader)
+	assert.NotEmpty(t, requestId)
+
+	w = authorizedRequest(server, "GET", "/user/audit-logs", supportToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = authorizedRequest(server, "GET", "/user/audit-logs?target-id=abc", adminToken, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	url := fmt.Sprintf("/user/audit-logs?action=%v&target-type=user&target-id=%v", entity.UpdateUserAction, user.ID)
+	w = authorizedRequest(server, "GET", url, adminToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := struct {
+		Result []struct {
+			ActorId   uint                      `json:"actor_id"`
+			RequestId string                    `json:"request_id"`
+			Changes   map[string]map[string]any `json:"changes"`
+		} `json:"result"`
+	}{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Result, 1)
+	auditLog := response.Result[0]
+	assert.Equal(t, admin.ID, auditLog.ActorId)
+	assert.Equal(t, requestId, auditLog.RequestId)
+	assert.Equal(t, map[string]any{"before": entity.UserRole, "after": entity.SupportRole}, auditLog.Changes["Role"])
+	assert.Equal(t, map[string]any{"before": user.FullName, "after": "Sara"}, auditLog.Changes["FullName"])
+	assert.NotContains(t, auditLog.Changes, "MobileNumber")
+
+	w = authorizedRequest(server, "DELETE", fmt.Sprintf("/user/users/%v", user.ID), adminToken, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = authorizedRequest(server, "GET", fmt.Sprintf("/user/audit-logs?action=%v", entity.DeleteUserAction), adminToken, nil)
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Result, 1)
+}
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
@@ -19,9 +20,13 @@ import (
 	"gorm.io/gorm"
 )
 
+// createState saves a state with a title of its own, since state titles
+// are unique.
 func createState(db *gorm.DB) (entity.State, error) {
 	stateRepo := repository.NewStateRepository(db)
-	state := entity.NewState("something")
+	var count int64
+	db.Unscoped().Model(&entity.State{}).Count(&count)
+	state := entity.NewState(fmt.Sprintf("something %v", count+1))
 	return state, stateRepo.Save(context.Background(), &state).Error
 }
 
@@ -42,7 +47,7 @@ func TestCreateCity(t *testing.T) {
 	db.Model(&entity.City{}).Count(&countBeforeSave)
 
 	server := gin.Default()
-	routers.SettingsRouters(server, "settings")
+	routers.SettingsRouters(server, "settings", app.TestContainer())
 	_, userToken := createUserAndToken(userRepo, entity.UserRole)
 	req, _ := http.NewRequest("POST", address, bytes.NewReader(body))
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", userToken))
@@ -71,7 +76,7 @@ func TestCreateCity(t *testing.T) {
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
 	w = httptest.NewRecorder()
 	server.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, http.StatusConflict, w.Code)
 
 	req, _ = http.NewRequest("POST", "/settings/states/505050/city", bytes.NewReader(body))
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
@@ -92,7 +97,7 @@ func TestListCity(t *testing.T) {
 	address := fmt.Sprintf("/settings/states/%v/city", state.ID)
 
 	server := gin.Default()
-	routers.SettingsRouters(server, "settings")
+	routers.SettingsRouters(server, "settings", app.TestContainer())
 	_, userToken := createUserAndToken(userRepo, entity.UserRole)
 	req, _ := http.NewRequest("GET", address, nil)
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", userToken))
@@ -137,7 +142,7 @@ func TestRetrieveCity(t *testing.T) {
 	otherAddress := fmt.Sprintf("/settings/states/%v/city/1", otherState.ID)
 
 	server := gin.Default()
-	routers.SettingsRouters(server, "settings")
+	routers.SettingsRouters(server, "settings", app.TestContainer())
 	_, userToken := createUserAndToken(userRepo, entity.UserRole)
 	req, _ := http.NewRequest("GET", address, nil)
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", userToken))
@@ -196,7 +201,7 @@ func TestUpdateCity(t *testing.T) {
 	body, _ := json.Marshal(map[string]string{"title": "something"})
 
 	server := gin.Default()
-	routers.SettingsRouters(server, "settings")
+	routers.SettingsRouters(server, "settings", app.TestContainer())
 
 	_, userToken := createUserAndToken(userRepo, entity.UserRole)
 	req, _ := http.NewRequest("PUT", address, nil)
@@ -270,7 +275,7 @@ func TestDeleteCity(t *testing.T) {
 	db.Model(&entity.City{}).Count(&count)
 
 	server := gin.Default()
-	routers.SettingsRouters(server, "settings")
+	routers.SettingsRouters(server, "settings", app.TestContainer())
 
 	req, _ := http.NewRequest("DELETE", address, nil)
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
@@ -0,0 +1,38 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabaseDiagnostics(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	userRepo := repository.NewUserRepository(database.TestDb())
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+	_, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	w := authorizedRequest(server, "GET", "/user/diagnostics/database", supportToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = authorizedRequest(server, "GET", "/user/diagnostics/database", adminToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Contains(t, response, "open_connections")
+	assert.Contains(t, response, "wait_count")
+	assert.GreaterOrEqual(t, response["open_connections"], float64(1))
+}
@@ -0,0 +1,88 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistrictsAndNeighborhoods(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	_, userToken := createUserAndToken(userRepo, entity.UserRole)
+	_, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+
+	state, err := createState(db)
+	assert.NoError(t, err)
+	city := entity.NewCity("something", state)
+	assert.NoError(t, db.Create(&city).Error)
+
+	server := gin.Default()
+	routers.SettingsRouters(server, "settings", app.TestContainer())
+
+	districtsUrl := fmt.Sprintf("/settings/cities/%v/districts", city.ID)
+	w := authorizedRequest(server, "POST", districtsUrl, userToken, map[string]string{"title": "district"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = authorizedRequest(server, "POST", "/settings/cities/505050/districts", supportToken, map[string]string{"title": "district"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = authorizedRequest(server, "POST", districtsUrl, supportToken, map[string]string{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = authorizedRequest(server, "POST", districtsUrl, supportToken, map[string]string{"title": "district"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	district := models.DistrictResponse{}
+	json.Unmarshal(w.Body.Bytes(), &district)
+	assert.Equal(t, []models.PathNodeResponse{
+		{Type: "state", Id: state.ID, Title: state.Title},
+		{Type: "city", Id: city.ID, Title: city.Title},
+	}, district.Path)
+
+	districtUrl := fmt.Sprintf("%v/%v", districtsUrl, district.Id)
+	w = authorizedRequest(server, "PUT", districtUrl, supportToken, map[string]string{"title": "renamed"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = authorizedRequest(server, "GET", fmt.Sprintf("/settings/cities/505050/districts/%v", district.Id), "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = authorizedRequest(server, "GET", districtsUrl+"?title=ren", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"renamed"`)
+
+	neighborhoodsUrl := fmt.Sprintf("/settings/districts/%v/neighborhoods", district.Id)
+	w = authorizedRequest(server, "POST", neighborhoodsUrl, supportToken, map[string]string{"title": "neighborhood"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	neighborhood := models.NeighborhoodResponse{}
+	json.Unmarshal(w.Body.Bytes(), &neighborhood)
+	assert.Len(t, neighborhood.Path, 3)
+	assert.Equal(t, models.PathNodeResponse{Type: "district", Id: district.Id, Title: "renamed"}, neighborhood.Path[2])
+
+	w = authorizedRequest(server, "GET", fmt.Sprintf("%v/%v", neighborhoodsUrl, neighborhood.Id), "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = authorizedRequest(server, "GET", fmt.Sprintf("/settings/states/%v/tree", state.ID), "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	tree := models.LocationTreeResponse{}
+	json.Unmarshal(w.Body.Bytes(), &tree)
+	assert.Len(t, tree.Cities, 1)
+	assert.Equal(t, "renamed", tree.Cities[0].Districts[0].Title)
+	assert.Equal(t, "neighborhood", tree.Cities[0].Districts[0].Neighborhoods[0].Title)
+	w = authorizedRequest(server, "GET", "/settings/states/505050/tree", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = authorizedRequest(server, "DELETE", fmt.Sprintf("%v/%v", neighborhoodsUrl, neighborhood.Id), supportToken, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = authorizedRequest(server, "DELETE", districtUrl, supportToken, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = authorizedRequest(server, "GET", districtUrl, "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,46 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoDataDiff(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	userRepo := repository.NewUserRepository(database.TestDb())
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+	_, userToken := createUserAndToken(userRepo, entity.UserRole)
+	container := app.TestContainer()
+
+	server := gin.Default()
+	routers.SettingsRouters(server, "settings", container)
+
+	w := authorizedRequest(server, "GET", "/settings/dataset/diff", userToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = authorizedRequest(server, "GET", "/settings/dataset/diff", adminToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, false, response["in_sync"])
+	assert.Len(t, response["missing_states"], 31)
+
+	_, err := container.GeoData.Seed(context.TODO())
+	assert.NoError(t, err)
+	w = authorizedRequest(server, "GET", "/settings/dataset/diff", adminToken, nil)
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, true, response["in_sync"])
+	assert.Equal(t, float64(31), response["matched_states"])
+}
@@ -0,0 +1,108 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImpersonation(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	support, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+	admin, _ := createUserAndToken(userRepo, entity.AdminRole)
+	user, userToken := createUserAndToken(userRepo, entity.UserRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+	impersonateURL := fmt.Sprintf("/user/users/%v/impersonate", user.ID)
+
+	w := authorizedRequest(server, "POST", impersonateURL, userToken, map[string]string{"reason": "ticket 42"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = authorizedRequest(server, "POST", fmt.Sprintf("/user/users/%v/impersonate", admin.ID), supportToken, map[string]string{"reason": "ticket 42"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = authorizedRequest(server, "POST", impersonateURL, supportToken, map[string]string{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = authorizedRequest(server, "POST", impersonateURL, supportToken, map[string]string{"reason": "ticket 42"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	response := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	token := response["token"].(string)
+	impersonation := response["impersonation"].(map[string]any)
+	assert.Equal(t, float64(support.ID), impersonation["actor_id"])
+	assert.Equal(t, float64(user.ID), impersonation["subject_id"])
+
+	w = authorizedRequest(server, "GET", "/user/me", token, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, fmt.Sprint(support.ID), w.Header().Get("X-Impersonated-By"))
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, float64(user.ID), response["id"])
+
+	w = authorizedRequest(server, "DELETE", "/user/me", token, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = authorizedRequest(server, "POST", "/user/me/mobile", token, map[string]string{"new_mobile_number": "09121112233"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NoError(t, userRepo.ById(user.ID, &entity.User{}).Error)
+
+	w = authorizedRequest(server, "DELETE", "/user/impersonation", userToken, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = authorizedRequest(server, "DELETE", "/user/impersonation", token, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = authorizedRequest(server, "GET", "/user/me", token, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	impersonationId := uint(impersonation["id"].(float64))
+	auditLogs, _ := repository.NewAuditLogRepository(db).ByTarget(context.TODO(), "impersonation", impersonationId)
+	var actions []string
+	for _, auditLog := range auditLogs {
+		assert.Equal(t, support.ID, auditLog.ActorID)
+		actions = append(actions, auditLog.Details)
+	}
+	assert.Equal(t, []string{"GET /user/me 200", "DELETE /user/me 403", "POST /user/me/mobile 403", "DELETE /user/impersonation 200"}, actions)
+	auditLogs, _ = repository.NewAuditLogRepository(db).ByTarget(context.TODO(), "user", user.ID)
+	assert.Equal(t, entity.StartImpersonationAction, auditLogs[0].Action)
+	assert.Equal(t, entity.EndImpersonationAction, auditLogs[1].Action)
+}
+
+func TestImpersonation_StaffPermissionsNotBorrowed(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	support, _ := createUserAndToken(userRepo, entity.SupportRole)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+	user, _ := createUserAndToken(userRepo, entity.UserRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	w := authorizedRequest(server, "POST", fmt.Sprintf("/user/users/%v/impersonate", support.ID), adminToken, map[string]string{"reason": "ticket 42"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	response := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	token := response["token"].(string)
+
+	w = authorizedRequest(server, "GET", "/user/me", token, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = authorizedRequest(server, "GET", "/user/users", token, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = authorizedRequest(server, "DELETE", fmt.Sprintf("/user/users/%v", user.ID), token, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = authorizedRequest(server, "PUT", fmt.Sprintf("/user/users/%v/role", user.ID), token, map[string]string{"role": entity.SupportRole})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NoError(t, userRepo.ById(user.ID, &entity.User{}).Error)
+}
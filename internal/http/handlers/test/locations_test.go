@@ -0,0 +1,100 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func uploadRequest(server *gin.Engine, url, token, fileName, content string) *httptest.ResponseRecorder {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	file, _ := form.CreateFormFile("file", fileName)
+	file.Write([]byte(content))
+	form.Close()
+	req, _ := http.NewRequest("POST", url, body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	return w
+}
+
+func TestImportAndExportLocations(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+	_, userToken := createUserAndToken(userRepo, entity.UserRole)
+
+	server := gin.Default()
+	routers.SettingsRouters(server, "settings", app.TestContainer())
+
+	csvFile := "state_title,city_title,state_code\nتهران,ری,IR-23\nتهران,شمیرانات,IR-23\nقم,,IR-25\n"
+	w := uploadRequest(server, "/settings/locations/import", userToken, "locations.csv", csvFile)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = uploadRequest(server, "/settings/locations/import?dry-run=true", adminToken, "locations.csv", csvFile)
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, true, response["dry_run"])
+	assert.Equal(t, float64(2), response["created_states"])
+	assert.Equal(t, float64(2), response["created_cities"])
+	var count int64
+	db.Model(&entity.State{}).Count(&count)
+	assert.Zero(t, count)
+
+	w = uploadRequest(server, "/settings/locations/import", adminToken, "locations.csv", csvFile)
+	assert.Equal(t, http.StatusOK, w.Code)
+	db.Model(&entity.City{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+
+	w = uploadRequest(server, "/settings/locations/import", adminToken, "locations.csv", "state_title,city_title\nتهران,\"\nری")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = uploadRequest(server, "/settings/locations/import", adminToken, "locations.txt", csvFile)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	invalid := "state_code,state_title,city_code,city_title\nIR-23,Tehran,,Rey\nIR-23,تهران,IR-23-02,\n"
+	w = uploadRequest(server, "/settings/locations/import?mode=code", adminToken, "locations.csv", invalid)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	json.Unmarshal(w.Body.Bytes(), &response)
+	errors := response["errors"].([]any)
+	assert.Len(t, errors, 2)
+	assert.Equal(t, "line 2", errors[0].(map[string]any)["location"])
+	assert.Equal(t, "city_code", errors[0].(map[string]any)["field"])
+
+	w = authorizedRequest(server, "GET", "/settings/locations/export?format=csv", adminToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), ".csv")
+	assert.Equal(t, "state_code,state_title,city_code,city_title\nIR-23,تهران,,ری\nIR-23,تهران,,شمیرانات\nIR-25,قم,,\n", w.Body.String())
+
+	w = authorizedRequest(server, "GET", "/settings/locations/export", adminToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	exported := []map[string]any{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &exported))
+	assert.Len(t, exported, 2)
+	assert.Len(t, exported[0]["cities"], 2)
+
+	renamed := strings.Replace(w.Body.String(), "شمیرانات", "شمیران", 1)
+	w = uploadRequest(server, "/settings/locations/import", adminToken, "locations.json", renamed)
+	assert.Equal(t, http.StatusOK, w.Code)
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, float64(0), response["created_states"])
+	assert.Equal(t, float64(1), response["created_cities"])
+}
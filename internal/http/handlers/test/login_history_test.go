@@ -0,0 +1,88 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSender struct {
+	messages []string
+}
+
+func (sender *recordingSender) Send(ctx context.Context, to, message string) error {
+	sender.messages = append(sender.messages, message)
+	return nil
+}
+
+func TestLoginHistory(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	user, token := createUserAndToken(userRepo, entity.UserRole)
+	_, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+	sender := &recordingSender{}
+	container := app.TestContainer()
+	container.SMS.Register("IR", sender)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", container)
+
+	w := requestToken(server, user, map[string]string{})
+	assert.Equal(t, http.StatusOK, w.Code)
+	for i := 0; i < 3; i++ {
+		body, _ := json.Marshal(map[string]string{"mobile_number": user.MobileNumber, "code": "000000"})
+		req, _ := http.NewRequest("POST", "/user/token", bytes.NewBuffer(body))
+		w = httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+	assert.Len(t, sender.messages, 1)
+	assert.Contains(t, sender.messages[0], "several wrong codes")
+
+	w = authorizedRequest(server, "GET", "/user/me/logins", token, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	events := response["result"].([]any)
+	assert.Len(t, events, 4)
+	assert.Equal(t, entity.LoginFailedEvent, events[0].(map[string]any)["type"])
+	assert.NotContains(t, events[0], "review_note")
+
+	w = authorizedRequest(server, "GET", fmt.Sprintf("/user/users/%v/logins", user.ID), token, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = authorizedRequest(server, "GET", fmt.Sprintf("/user/users/%v/logins", user.ID), supportToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = authorizedRequest(server, "GET", "/user/logins/suspicious", supportToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	json.Unmarshal(w.Body.Bytes(), &response)
+	queue := response["result"].([]any)
+	assert.Len(t, queue, 1)
+	flagged := queue[0].(map[string]any)
+	assert.Equal(t, []any{entity.FailureBurstAnomaly}, flagged["anomalies"])
+	assert.Equal(t, float64(user.ID), flagged["user_id"])
+
+	reviewURL := fmt.Sprintf("/user/logins/%v/review", flagged["id"])
+	w = authorizedRequest(server, "POST", reviewURL, supportToken, map[string]string{"note": "user mistyped"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = authorizedRequest(server, "POST", reviewURL, supportToken, map[string]string{"note": "user mistyped"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = authorizedRequest(server, "GET", "/user/logins/suspicious", supportToken, nil)
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Empty(t, response["result"])
+}
@@ -0,0 +1,175 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestMobileNumberChange(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	user, token := createUserAndToken(userRepo, entity.UserRole)
+	otherUser, _ := createUserAndToken(userRepo, entity.UserRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	body, _ := json.Marshal(map[string]string{"new_mobile_number": otherUser.MobileNumber})
+	req, _ := http.NewRequest("POST", "/user/me/mobile", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	body, _ = json.Marshal(map[string]string{"new_mobile_number": user.MobileNumber})
+	req, _ = http.NewRequest("POST", "/user/me/mobile", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body, _ = json.Marshal(map[string]string{"new_mobile_number": "09350000001"})
+	req, _ = http.NewRequest("POST", "/user/me/mobile", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	client := redis.TestClient()
+	assert.Equal(t, int64(1), client.Exists(context.TODO(), entity.OTPKey(user.MobileNumber, entity.ChangeMobilePurpose)).Val())
+	assert.Equal(t, int64(1), client.Exists(context.TODO(), entity.OTPKey("+989350000001", entity.ChangeMobilePurpose)).Val())
+}
+
+func TestConfirmMobileNumberChange(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	user, token := createUserAndToken(userRepo, entity.UserRole)
+	newMobileNumber := "+989350000002"
+
+	otpRepo := repository.NewOTPCodeRepository(redis.TestClient())
+	oldCode := entity.OTPCode{MobileNumber: user.MobileNumber, Purpose: entity.ChangeMobilePurpose, Code: "111111", TTL: time.Hour}
+	newCode := entity.OTPCode{MobileNumber: newMobileNumber, Purpose: entity.ChangeMobilePurpose, Code: "222222", TTL: time.Hour}
+	loginCode := entity.OTPCode{MobileNumber: user.MobileNumber, Purpose: entity.LoginPurpose, Code: "333333", TTL: time.Hour}
+	otpRepo.Save(context.TODO(), &oldCode)
+	otpRepo.Save(context.TODO(), &newCode)
+	otpRepo.Save(context.TODO(), &loginCode)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	body, _ := json.Marshal(map[string]string{"new_mobile_number": newMobileNumber, "old_code": "333333", "new_code": "222222"})
+	req, _ := http.NewRequest("POST", "/user/me/mobile/confirm", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "login codes must not confirm a mobile number change")
+
+	body, _ = json.Marshal(map[string]string{"new_mobile_number": "09350000002", "old_code": "111111", "new_code": "222222"})
+	req, _ = http.NewRequest("POST", "/user/me/mobile/confirm", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	response, _ := io.ReadAll(w.Body)
+	var result map[string]any
+	json.Unmarshal(response, &result)
+
+	updatedUser := new(entity.User)
+	userRepo.ById(user.ID, updatedUser)
+	assert.Equal(t, newMobileNumber, updatedUser.MobileNumber)
+
+	req, _ = http.NewRequest("GET", "/user/me", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "tokens with the old mobile number must be revoked")
+
+	req, _ = http.NewRequest("GET", "/user/me", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", result["token"]))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAdminChangeMobileNumber(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	user, _ := createUserAndToken(userRepo, entity.UserRole)
+	otherUser, _ := createUserAndToken(userRepo, entity.UserRole)
+	supportUser, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+	_, userToken := createUserAndToken(userRepo, entity.UserRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+	address := fmt.Sprintf("/user/users/%v/mobile", user.ID)
+
+	body, _ := json.Marshal(map[string]string{"mobile_number": "09350000003", "reason": "lost sim card"})
+	req, _ := http.NewRequest("PUT", address, bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", userToken))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	noReason, _ := json.Marshal(map[string]string{"mobile_number": "09350000003"})
+	req, _ = http.NewRequest("PUT", address, bytes.NewBuffer(noReason))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	taken, _ := json.Marshal(map[string]string{"mobile_number": otherUser.MobileNumber, "reason": "lost sim card"})
+	req, _ = http.NewRequest("PUT", address, bytes.NewBuffer(taken))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	req, _ = http.NewRequest("PUT", address, bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	auditLogs, err := repository.NewAuditLogRepository(db).ByTarget(context.TODO(), "user", user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(auditLogs))
+	assert.Equal(t, supportUser.ID, auditLogs[0].ActorID)
+	assert.Equal(t, entity.ChangeMobileNumberAction, auditLogs[0].Action)
+	assert.Contains(t, auditLogs[0].Details, "lost sim card")
+
+	admin, _ := createUserAndToken(userRepo, entity.AdminRole)
+	body, _ = json.Marshal(map[string]string{"mobile_number": "09350000004", "reason": "lost sim card"})
+	req, _ = http.NewRequest("PUT", fmt.Sprintf("/user/users/%v/mobile", admin.ID), bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	unchanged := entity.User{}
+	assert.NoError(t, userRepo.ById(admin.ID, &unchanged).Error)
+	assert.Equal(t, admin.MobileNumber, unchanged.MobileNumber)
+}
@@ -0,0 +1,74 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoordinatesAndNearbyCities(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+
+	server := gin.Default()
+	routers.SettingsRouters(server, "settings", app.TestContainer())
+
+	boundary := map[string]any{"type": "Polygon", "coordinates": [][][]float64{{{50, 34}, {52, 34}, {52, 36}, {50, 34}}}}
+	w := authorizedRequest(server, "POST", "/settings/states", adminToken, map[string]any{"title": "تهران", "latitude": 35.7, "longitude": 51.4, "boundary": boundary})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	state := models.StateResponse{}
+	json.Unmarshal(w.Body.Bytes(), &state)
+	assert.Equal(t, 35.7, *state.Latitude)
+	assert.Contains(t, string(state.Boundary), `"Polygon"`)
+
+	w = authorizedRequest(server, "POST", "/settings/states", adminToken, map[string]any{"title": "x", "latitude": 35.7})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = authorizedRequest(server, "POST", "/settings/states", adminToken, map[string]any{"title": "x", "boundary": map[string]any{"type": "Point"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	citiesUrl := fmt.Sprintf("/settings/states/%v/city", state.Id)
+	w = authorizedRequest(server, "POST", citiesUrl, adminToken, map[string]any{"title": "ری", "latitude": 35.5961, "longitude": 51.434})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = authorizedRequest(server, "POST", citiesUrl, adminToken, map[string]any{"title": "قم", "latitude": 134.64, "longitude": 50.8764})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = authorizedRequest(server, "POST", citiesUrl, adminToken, map[string]any{"title": "قم"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	qom := models.CityResponse{}
+	json.Unmarshal(w.Body.Bytes(), &qom)
+	assert.Nil(t, qom.Latitude)
+
+	w = authorizedRequest(server, "PUT", fmt.Sprintf("%v/%v", citiesUrl, qom.Id), adminToken, map[string]any{"title": "قم", "latitude": 34.6401, "longitude": 50.8764})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = authorizedRequest(server, "GET", "/settings/cities/nearby?lat=35.6892&lng=51.389&radius=200", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	nearby := []models.NearbyCityResponse{}
+	json.Unmarshal(w.Body.Bytes(), &nearby)
+	assert.Len(t, nearby, 2)
+	assert.Equal(t, "ری", nearby[0].Title)
+	assert.Equal(t, qom.Id, nearby[1].Id)
+	assert.InDelta(t, 125.4, nearby[1].Distance, 1)
+
+	w = authorizedRequest(server, "GET", "/settings/cities/nearby?lat=35.6892&lng=51.389&radius=20", "", nil)
+	json.Unmarshal(w.Body.Bytes(), &nearby)
+	assert.Len(t, nearby, 1)
+
+	w = authorizedRequest(server, "GET", "/settings/cities/nearby?lat=35.6892", "", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = authorizedRequest(server, "GET", "/settings/cities/nearby?lat=35.6892&lng=51.389&radius=5000", "", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
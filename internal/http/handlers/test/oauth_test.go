@@ -0,0 +1,184 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func registerOAuthClient(server *gin.Engine, adminToken string, payload map[string]any) (string, string) {
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest("POST", "/user/oauth/clients", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	response := struct {
+		Client       map[string]any `json:"client"`
+		ClientSecret string         `json:"client_secret"`
+	}{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return response.Client["client_id"].(string), response.ClientSecret
+}
+
+func postForm(server *gin.Engine, path string, form url.Values) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	return w
+}
+
+func TestOAuthAuthorizationCodeFlow(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	user, _ := createUserAndToken(userRepo, entity.UserRole)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+	clientId, _ := registerOAuthClient(server, adminToken, map[string]any{
+		"name": "mobile", "redirect_uris": []string{"app://callback"}, "scopes": []string{entity.ProfileScope},
+	})
+	verifier := "a-long-enough-verifier-for-pkce-purposes-0123456789"
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	query := url.Values{
+		"response_type": {"code"}, "client_id": {clientId}, "redirect_uri": {"app://callback"},
+		"code_challenge": {challenge}, "code_challenge_method": {"S256"},
+	}
+	req, _ := http.NewRequest("GET", "/user/oauth/authorize?"+query.Encode(), nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"scope":"profile"`)
+
+	otpRepo := repository.NewOTPCodeRepository(redis.TestClient())
+	otpRepo.Save(context.TODO(), &entity.OTPCode{MobileNumber: user.MobileNumber, Purpose: entity.LoginPurpose, Code: "123456", TTL: time.Minute})
+	consent := map[string]string{
+		"client_id": clientId, "redirect_uri": "app://callback", "state": "xyz",
+		"code_challenge": challenge, "code_challenge_method": "S256",
+		"mobile_number": user.MobileNumber, "code": "654321",
+	}
+	body, _ := json.Marshal(consent)
+	req, _ = http.NewRequest("POST", "/user/oauth/authorize", bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	consent["code"] = "123456"
+	body, _ = json.Marshal(consent)
+	req, _ = http.NewRequest("POST", "/user/oauth/authorize", bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	authorizeResponse := map[string]string{}
+	json.Unmarshal(w.Body.Bytes(), &authorizeResponse)
+	redirectURI, _ := url.Parse(authorizeResponse["redirect_uri"])
+	assert.Equal(t, "xyz", redirectURI.Query().Get("state"))
+
+	w = postForm(server, "/user/oauth/token", url.Values{
+		"grant_type": {"authorization_code"}, "client_id": {clientId}, "code": {redirectURI.Query().Get("code")},
+		"redirect_uri": {"app://callback"}, "code_verifier": {verifier},
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	tokens := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &tokens)
+	accessToken := tokens["access_token"].(string)
+
+	req, _ = http.NewRequest("GET", "/user/me", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", accessToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest("DELETE", "/user/me", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", accessToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = postForm(server, "/user/oauth/token", url.Values{
+		"grant_type": {"refresh_token"}, "client_id": {clientId}, "refresh_token": {tokens["refresh_token"].(string)},
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = postForm(server, "/user/oauth/revoke", url.Values{"client_id": {clientId}, "token": {accessToken}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	req, _ = http.NewRequest("GET", "/user/me", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", accessToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestOAuthClientCredentials(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+	clientId, clientSecret := registerOAuthClient(server, adminToken, map[string]any{
+		"name": "agency", "scopes": []string{entity.UsersReadPermission}, "confidential": true,
+	})
+
+	w := postForm(server, "/user/oauth/token", url.Values{"grant_type": {"client_credentials"}, "client_id": {clientId}, "client_secret": {"wrong"}})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), `"error":"invalid_client"`)
+
+	w = postForm(server, "/user/oauth/token", url.Values{"grant_type": {"password"}, "client_id": {clientId}, "client_secret": {clientSecret}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"error":"unsupported_grant_type"`)
+
+	req, _ := http.NewRequest("POST", "/user/oauth/token", strings.NewReader("grant_type=client_credentials"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientId, clientSecret)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	tokens := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &tokens)
+	accessToken := tokens["access_token"].(string)
+	assert.Nil(t, tokens["refresh_token"])
+
+	req, _ = http.NewRequest("GET", "/user/users", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", accessToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest("GET", "/user/roles", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", accessToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = postForm(server, "/user/oauth/introspect", url.Values{"client_id": {clientId}, "client_secret": {clientSecret}, "token": {accessToken}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"active":true`)
+	w = postForm(server, "/user/oauth/introspect", url.Values{"client_id": {clientId}, "client_secret": {clientSecret}, "token": {"garbage"}})
+	assert.Equal(t, `{"active":false}`, w.Body.String())
+}
@@ -0,0 +1,278 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleList(t *testing.T) {
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	_, userToken := createUserAndToken(userRepo, entity.UserRole)
+	_, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	req, _ := http.NewRequest("GET", "/user/roles", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", userToken))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	req, _ = http.NewRequest("GET", "/user/roles", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var roles []map[string]any
+	json.Unmarshal(w.Body.Bytes(), &roles)
+	assert.Len(t, roles, len(entity.DefaultRolePermissions))
+}
+
+func TestCreateUpdateDeleteRole(t *testing.T) {
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	_, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	body, _ := json.Marshal(map[string]any{"name": "Editor", "permissions": []string{entity.SettingsStatesWritePermission}})
+	req, _ := http.NewRequest("POST", "/user/roles", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	req, _ = http.NewRequest("POST", "/user/roles", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	role := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &role)
+
+	req, _ = http.NewRequest("POST", "/user/roles", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	url := fmt.Sprintf("/user/roles/%v", role["id"])
+	body, _ = json.Marshal(map[string]any{"permissions": []string{"unknown.permission"}})
+	req, _ = http.NewRequest("PUT", url, bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body, _ = json.Marshal(map[string]any{"permissions": []string{entity.SettingsCitiesWritePermission}})
+	req, _ = http.NewRequest("PUT", url, bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest("DELETE", url, nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req, _ = http.NewRequest("DELETE", url, nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	admin := entity.Role{}
+	assert.NoError(t, db.First(&admin, "name = ?", entity.AdminRole).Error)
+	req, _ = http.NewRequest("PUT", fmt.Sprintf("/user/roles/%v", admin.ID), bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAssignRole(t *testing.T) {
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	user, _ := createUserAndToken(userRepo, entity.UserRole)
+	_, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+	url := fmt.Sprintf("/user/users/%v/role", user.ID)
+
+	body, _ := json.Marshal(map[string]string{"role": entity.AdminRole})
+	req, _ := http.NewRequest("PUT", url, bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body, _ = json.Marshal(map[string]string{"role": "Unknown"})
+	req, _ = http.NewRequest("PUT", url, bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body, _ = json.Marshal(map[string]string{"role": entity.SupportRole})
+	req, _ = http.NewRequest("PUT", url, bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	updatedUser := entity.User{}
+	userRepo.ById(user.ID, &updatedUser)
+	assert.Equal(t, entity.SupportRole, updatedUser.Role)
+}
+
+func TestAdminInvariants(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	admin, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+	roleURL := fmt.Sprintf("/user/users/%v/role", admin.ID)
+
+	w := authorizedRequest(server, "DELETE", fmt.Sprintf("/user/users/%v", admin.ID), adminToken, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = authorizedRequest(server, "DELETE", "/user/me", adminToken, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = authorizedRequest(server, "PUT", roleURL, adminToken, map[string]any{"role": entity.UserRole})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "confirmation_required")
+	w = authorizedRequest(server, "PUT", roleURL, adminToken, map[string]any{"role": entity.UserRole, "confirm": true})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	createUserAndToken(userRepo, entity.AdminRole)
+	w = authorizedRequest(server, "PUT", fmt.Sprintf("/user/users/%v", admin.ID), adminToken, map[string]any{"full_name": "x", "role": entity.UserRole})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = authorizedRequest(server, "PUT", fmt.Sprintf("/user/users/%v", admin.ID), adminToken, map[string]any{"full_name": "x", "role": entity.UserRole, "confirm": true})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the old token now carries a stale role claim
+	w = authorizedRequest(server, "GET", "/user/users", adminToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRoleChangeTakesEffectImmediately(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+	user, userToken := createUserAndToken(userRepo, entity.UserRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	w := authorizedRequest(server, "GET", "/user/roles", userToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = authorizedRequest(server, "POST", "/user/roles", adminToken, map[string]any{"name": "Auditor", "permissions": []string{entity.RolesReadPermission}})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = authorizedRequest(server, "PUT", fmt.Sprintf("/user/users/%v/role", user.ID), adminToken, map[string]any{"role": "Auditor"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = authorizedRequest(server, "GET", "/user/roles", userToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// roles requiring a second factor need a new login
+	w = authorizedRequest(server, "PUT", fmt.Sprintf("/user/users/%v/role", user.ID), adminToken, map[string]any{"role": entity.SupportRole})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = authorizedRequest(server, "GET", "/user/roles", userToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "two-factor")
+}
+
+func TestCreateUpdateRole_PermissionsNotHeld(t *testing.T) {
+	database.InitiateTestDB()
+	db := database.TestDb()
+	manager := entity.NewRole("RoleManager", []string{entity.RolesWritePermission, entity.SettingsStatesWritePermission})
+	assert.NoError(t, db.Create(&manager).Error)
+	_, managerToken := createUserAndToken(repository.NewUserRepository(db), manager.Name)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	body, _ := json.Marshal(map[string]any{"name": "Deleter", "permissions": []string{entity.UsersDeletePermission}})
+	req, _ := http.NewRequest("POST", "/user/roles", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", managerToken))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	body, _ = json.Marshal(map[string]any{"name": "Editor", "permissions": []string{entity.SettingsStatesWritePermission}})
+	req, _ = http.NewRequest("POST", "/user/roles", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", managerToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	role := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &role)
+
+	url := fmt.Sprintf("/user/roles/%v", role["id"])
+	body, _ = json.Marshal(map[string]any{"permissions": []string{entity.SettingsStatesWritePermission, entity.UsersDeletePermission}})
+	req, _ = http.NewRequest("PUT", url, bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", managerToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	support := entity.Role{}
+	assert.NoError(t, db.First(&support, "name = ?", entity.SupportRole).Error)
+	body, _ = json.Marshal(map[string]any{"permissions": []string{entity.SettingsStatesWritePermission}})
+	req, _ = http.NewRequest("PUT", fmt.Sprintf("/user/roles/%v", support.ID), bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", managerToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAssignRole_CurrentRoleAboveCaller(t *testing.T) {
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	admin, _ := createUserAndToken(userRepo, entity.AdminRole)
+	createUserAndToken(userRepo, entity.AdminRole)
+	_, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	body, _ := json.Marshal(map[string]string{"role": entity.UserRole})
+	req, _ := http.NewRequest("PUT", fmt.Sprintf("/user/users/%v/role", admin.ID), bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	unchanged := entity.User{}
+	assert.NoError(t, userRepo.ById(admin.ID, &unchanged).Error)
+	assert.Equal(t, entity.AdminRole, unchanged.Role)
+}
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
@@ -31,7 +32,7 @@ func TestCreateState(t *testing.T) {
 	db.Model(&entity.State{}).Count(&countBeforeSave)
 
 	server := gin.Default()
-	routers.SettingsRouters(server, "settings")
+	routers.SettingsRouters(server, "settings", app.TestContainer())
 	_, userToken := createUserAndToken(userRepo, entity.UserRole)
 	req, _ := http.NewRequest("POST", "/settings/states", bytes.NewReader(body))
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", userToken))
@@ -55,7 +56,7 @@ func TestCreateState(t *testing.T) {
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
 	w = httptest.NewRecorder()
 	server.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, http.StatusConflict, w.Code)
 }
 
 func TestStateList(t *testing.T) {
@@ -66,7 +67,7 @@ func TestStateList(t *testing.T) {
 	userRepo := repository.NewUserRepository(db)
 
 	server := gin.Default()
-	routers.SettingsRouters(server, "settings")
+	routers.SettingsRouters(server, "settings", app.TestContainer())
 
 	_, userToken := createUserAndToken(userRepo, entity.UserRole)
 	req, _ := http.NewRequest("GET", "/settings/states", nil)
@@ -103,7 +104,7 @@ func TestRetrieveState(t *testing.T) {
 	assert.NoError(t, err)
 
 	server := gin.Default()
-	routers.SettingsRouters(server, "settings")
+	routers.SettingsRouters(server, "settings", app.TestContainer())
 
 	_, userToken := createUserAndToken(userRepo, entity.UserRole)
 	req, _ := http.NewRequest("GET", "/settings/states/1", nil)
@@ -148,7 +149,7 @@ func TestUpdateState(t *testing.T) {
 	body, _ := json.Marshal(map[string]string{"title": "something"})
 
 	server := gin.Default()
-	routers.SettingsRouters(server, "settings")
+	routers.SettingsRouters(server, "settings", app.TestContainer())
 
 	_, userToken := createUserAndToken(userRepo, entity.UserRole)
 	req, _ := http.NewRequest("PUT", "/settings/states/1", nil)
@@ -218,7 +219,7 @@ func TestDeleteState(t *testing.T) {
 	db.Model(&entity.State{}).Count(&count)
 
 	server := gin.Default()
-	routers.SettingsRouters(server, "settings")
+	routers.SettingsRouters(server, "settings", app.TestContainer())
 
 	req, _ := http.NewRequest("DELETE", "/settings/states/1", nil)
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
@@ -248,3 +249,40 @@ func TestDeleteState(t *testing.T) {
 	server.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestDeleteStateWithCities(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+
+	stateRepo := repository.NewStateRepository(db)
+	state := entity.NewState("Tehran")
+	assert.NoError(t, stateRepo.Save(context.Background(), &state).Error)
+	target := entity.NewState("Alborz")
+	assert.NoError(t, stateRepo.Save(context.Background(), &target).Error)
+	city := entity.NewCity("Karaj", state)
+	assert.NoError(t, repository.NewCityRepository(db).Save(context.Background(), &city).Error)
+
+	server := gin.Default()
+	routers.SettingsRouters(server, "settings", app.TestContainer())
+	deleteState := func(query string) int {
+		req, _ := http.NewRequest("DELETE", fmt.Sprintf("/settings/states/%v%v", state.ID, query), nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusConflict, deleteState(""))
+	assert.Equal(t, http.StatusBadRequest, deleteState("?cities=archive"))
+	assert.Equal(t, http.StatusBadRequest, deleteState("?cities=reassign"))
+	assert.Equal(t, http.StatusBadRequest, deleteState("?cities=reassign&target=abc"))
+	assert.Equal(t, http.StatusNoContent, deleteState(fmt.Sprintf("?cities=reassign&target=%v", target.ID)))
+
+	var moved entity.City
+	assert.NoError(t, db.First(&moved, city.ID).Error)
+	assert.Equal(t, target.ID, moved.StateID)
+}
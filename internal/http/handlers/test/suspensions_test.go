@@ -0,0 +1,94 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuspendUser(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	_, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+	_, userToken := createUserAndToken(userRepo, entity.UserRole)
+	user, token := createUserAndToken(userRepo, entity.UserRole)
+	admin, _ := createUserAndToken(userRepo, entity.AdminRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+	suspendURL := fmt.Sprintf("/user/users/%v/suspend", user.ID)
+
+	w := authorizedRequest(server, "POST", suspendURL, userToken, map[string]string{"reason": entity.SpamSuspensionReason})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = authorizedRequest(server, "POST", fmt.Sprintf("/user/users/%v/suspend", admin.ID), supportToken, map[string]string{"reason": entity.SpamSuspensionReason})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = authorizedRequest(server, "POST", suspendURL, supportToken, map[string]string{"reason": "bored"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	endsAt := time.Now().Add(time.Hour).UTC()
+	w = authorizedRequest(server, "POST", suspendURL, supportToken, map[string]any{"reason": entity.SpamSuspensionReason, "notes": "bulk messages", "ends_at": endsAt})
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, entity.SuspendedStatus, response["status"])
+
+	w = authorizedRequest(server, "GET", "/user/me", token, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "account suspended", response["message"])
+	assert.Equal(t, entity.SpamSuspensionReason, response["reason"])
+	assert.NotContains(t, w.Body.String(), "bulk messages")
+
+	body, _ := json.Marshal(map[string]string{"mobile_number": user.MobileNumber})
+	req, _ := http.NewRequest("POST", "/user/authenticate", bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = requestToken(server, user, map[string]string{})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = authorizedRequest(server, "GET", "/user/users?status=suspended", adminToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	list := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &list)
+	result := list["result"].([]any)
+	assert.Len(t, result, 1)
+	assert.Equal(t, float64(user.ID), result[0].(map[string]any)["id"])
+	w = authorizedRequest(server, "GET", "/user/users?status=deleted", adminToken, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = authorizedRequest(server, "POST", fmt.Sprintf("/user/users/%v/unsuspend", user.ID), supportToken, map[string]string{"notes": "appeal accepted"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = authorizedRequest(server, "POST", fmt.Sprintf("/user/users/%v/unsuspend", user.ID), supportToken, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = authorizedRequest(server, "GET", "/user/me", token, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = authorizedRequest(server, "GET", fmt.Sprintf("/user/users/%v/suspensions", user.ID), supportToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	history := []map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &history)
+	assert.Len(t, history, 1)
+	assert.Equal(t, "bulk messages", history[0]["notes"])
+	assert.Equal(t, "appeal accepted", history[0]["lift_notes"])
+
+	auditLogs, _ := repository.NewAuditLogRepository(db).ByTarget(context.TODO(), "user", user.ID)
+	assert.Len(t, auditLogs, 2)
+}
@@ -0,0 +1,77 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslations(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	_, userToken := createUserAndToken(userRepo, entity.UserRole)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+
+	state := entity.NewState("تهران")
+	assert.NoError(t, db.Create(&state).Error)
+	city := entity.NewCity("ری", state)
+	assert.NoError(t, db.Create(&city).Error)
+
+	server := gin.Default()
+	routers.SettingsRouters(server, "settings", app.TestContainer())
+
+	stateUrl := fmt.Sprintf("/settings/states/%v", state.ID)
+	cityUrl := fmt.Sprintf("%v/city/%v", stateUrl, city.ID)
+	translations := map[string]any{"translations": map[string]string{"en": "Tehran", "ar": "طهران"}}
+	w := authorizedRequest(server, "PUT", stateUrl+"/translations", userToken, translations)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = authorizedRequest(server, "PUT", stateUrl+"/translations", adminToken, map[string]any{"translations": map[string]string{"de": "Teheran"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = authorizedRequest(server, "PUT", stateUrl+"/translations", adminToken, translations)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = authorizedRequest(server, "PUT", cityUrl+"/translations", adminToken, map[string]any{"translations": map[string]string{"en": "Rey"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = authorizedRequest(server, "PUT", stateUrl+"/city/505050/translations", adminToken, translations)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = authorizedRequest(server, "GET", stateUrl+"?lang=en", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+	stateResponse := models.StateResponse{}
+	json.Unmarshal(w.Body.Bytes(), &stateResponse)
+	assert.Equal(t, "Tehran", stateResponse.Title)
+	assert.Equal(t, "طهران", stateResponse.Translations["ar"])
+
+	req, _ := http.NewRequest("GET", cityUrl, nil)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	cityResponse := models.CityResponse{}
+	json.Unmarshal(w.Body.Bytes(), &cityResponse)
+	assert.Equal(t, "Rey", cityResponse.Title)
+	assert.Equal(t, "Tehran", cityResponse.StateTitle)
+
+	// the default locale is used when a city has no translation for the locale
+	w = authorizedRequest(server, "GET", cityUrl+"?lang=ar", "", nil)
+	json.Unmarshal(w.Body.Bytes(), &cityResponse)
+	assert.Equal(t, "ری", cityResponse.Title)
+	assert.Equal(t, "طهران", cityResponse.StateTitle)
+
+	w = authorizedRequest(server, "GET", "/settings/states?title=Tehr&lang=en", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"title":"Tehran"`)
+}
@@ -0,0 +1,96 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserTrash(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+	_, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+	user, _ := createUserAndToken(userRepo, entity.UserRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	w := authorizedRequest(server, "DELETE", fmt.Sprintf("/user/users/%v", user.ID), supportToken, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = authorizedRequest(server, "GET", "/user/trash/users", supportToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response["result"], 1)
+
+	// the deleted user's number can sign up again, which blocks the restore
+	registered := entity.NewUser("", user.MobileNumber, entity.UserRole)
+	assert.NoError(t, userRepo.Save(&registered))
+	restore := fmt.Sprintf("/user/trash/users/%v/restore", user.ID)
+	w = authorizedRequest(server, "POST", restore, supportToken, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	userRepo.Delete(&registered)
+	w = authorizedRequest(server, "POST", restore, supportToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = authorizedRequest(server, "POST", restore, supportToken, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	purge := fmt.Sprintf("/user/trash/users/%v", registered.ID)
+	w = authorizedRequest(server, "DELETE", purge, supportToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = authorizedRequest(server, "DELETE", purge, adminToken, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = authorizedRequest(server, "DELETE", purge, adminToken, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestStateAndCityTrash(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+	_, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+	state, _ := createState(db)
+	city := entity.NewCity("Rey", state)
+	db.Create(&city)
+	db.Delete(&city)
+	db.Delete(&state)
+
+	server := gin.Default()
+	routers.SettingsRouters(server, "settings", app.TestContainer())
+
+	w := authorizedRequest(server, "GET", fmt.Sprintf("/settings/trash/states/%v/cities", state.ID), supportToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = authorizedRequest(server, "POST", fmt.Sprintf("/settings/trash/cities/%v/restore", city.ID), supportToken, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = authorizedRequest(server, "DELETE", fmt.Sprintf("/settings/trash/states/%v", state.ID), adminToken, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = authorizedRequest(server, "DELETE", fmt.Sprintf("/settings/trash/cities/%v", city.ID), supportToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = authorizedRequest(server, "DELETE", fmt.Sprintf("/settings/trash/cities/%v", city.ID), adminToken, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = authorizedRequest(server, "POST", fmt.Sprintf("/settings/trash/states/%v/restore", state.ID), supportToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = authorizedRequest(server, "GET", "/settings/trash/states", supportToken, nil)
+	response := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Empty(t, response["result"])
+}
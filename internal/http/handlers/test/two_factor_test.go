@@ -0,0 +1,183 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/totp"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func requestToken(server *gin.Engine, user entity.User, payload map[string]string) *httptest.ResponseRecorder {
+	otpRepo := repository.NewOTPCodeRepository(redis.TestClient())
+	otpRepo.Save(context.TODO(), &entity.OTPCode{MobileNumber: user.MobileNumber, Purpose: entity.LoginPurpose, Code: "123456", TTL: time.Minute})
+	payload["mobile_number"] = user.MobileNumber
+	payload["code"] = "123456"
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest("POST", "/user/token", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	return w
+}
+
+func authorizedRequest(server *gin.Engine, method, url, token string, payload any) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest(method, url, bytes.NewBuffer(body))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	return w
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	admin, _ := createUserAndToken(userRepo, entity.AdminRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	w := requestToken(server, admin, map[string]string{})
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, true, response["two_factor_enrollment_required"])
+	pendingToken := response["token"].(string)
+
+	w = authorizedRequest(server, "GET", "/user/users", pendingToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = authorizedRequest(server, "GET", "/user/me", pendingToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = authorizedRequest(server, "POST", "/user/me/2fa", pendingToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	enrollment := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &enrollment)
+	secret := enrollment["secret"].(string)
+	recoveryCode := enrollment["recovery_codes"].([]any)[0].(string)
+
+	w = authorizedRequest(server, "POST", "/user/me/2fa/confirm", pendingToken, map[string]string{"code": "000000"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+	w = authorizedRequest(server, "POST", "/user/me/2fa/confirm", pendingToken, map[string]string{"code": code})
+	assert.Equal(t, http.StatusOK, w.Code)
+	json.Unmarshal(w.Body.Bytes(), &response)
+	w = authorizedRequest(server, "GET", "/user/users", response["token"].(string), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = requestToken(server, admin, map[string]string{})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), `"two_factor_required":true`)
+	w = requestToken(server, admin, map[string]string{"totp_code": code})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = requestToken(server, admin, map[string]string{"recovery_code": recoveryCode})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "two_factor_enrollment_required")
+}
+
+func TestTwoFactorLogin_WrongTOTPKeepsOTP(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	admin, _ := createUserAndToken(userRepo, entity.AdminRole)
+	twoFactor, _, _ := entity.NewTwoFactor(admin.ID)
+	now := time.Now()
+	twoFactor.ConfirmedAt = &now
+	repository.NewTwoFactorRepository(db).Save(context.TODO(), &twoFactor)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	code, _ := totp.Code(twoFactor.Secret, totp.Step(now))
+	wrongCode := "000000"
+	if _, ok := twoFactor.MatchTOTP(wrongCode, now); ok {
+		wrongCode = "111111"
+	}
+	w := requestToken(server, admin, map[string]string{"totp_code": wrongCode})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// the retry reuses the sms code sent for the first attempt
+	body, _ := json.Marshal(map[string]string{"mobile_number": admin.MobileNumber, "code": "123456", "totp_code": code})
+	req, _ := http.NewRequest("POST", "/user/token", bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest("POST", "/user/token", bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestResetTwoFactor(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	support, _ := createUserAndToken(userRepo, entity.SupportRole)
+	_, supportToken := createUserAndToken(userRepo, entity.SupportRole)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	twoFactor, _, _ := entity.NewTwoFactor(support.ID)
+	now := time.Now()
+	twoFactor.ConfirmedAt = &now
+	twoFactorRepo.Save(context.TODO(), &twoFactor)
+
+	url := fmt.Sprintf("/user/users/%v/2fa/reset", support.ID)
+	w := authorizedRequest(server, "POST", url, supportToken, map[string]string{"reason": "lost phone"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = authorizedRequest(server, "POST", url, adminToken, map[string]string{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = authorizedRequest(server, "POST", url, adminToken, map[string]string{"reason": "lost phone"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	assert.Error(t, twoFactorRepo.ByUserId(context.TODO(), support.ID, &entity.TwoFactor{}).Error)
+	auditLogs, _ := repository.NewAuditLogRepository(db).ByTarget(context.TODO(), "user", support.ID)
+	assert.Len(t, auditLogs, 1)
+	assert.Equal(t, entity.ResetTwoFactorAction, auditLogs[0].Action)
+}
+
+func TestUpdateRoleTwoFactor(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+	db := database.TestDb()
+	userRepo := repository.NewUserRepository(db)
+	_, adminToken := createUserAndToken(userRepo, entity.AdminRole)
+	roleRepo := repository.NewRoleRepository(db)
+	support, editor := entity.Role{}, entity.NewRole("Editor", nil)
+	roleRepo.ByName(context.TODO(), entity.SupportRole, &support)
+	roleRepo.Save(context.TODO(), &editor)
+	editorUser, _ := createUserAndToken(userRepo, editor.Name)
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	w := authorizedRequest(server, "PUT", fmt.Sprintf("/user/roles/%v/two-factor", support.ID), adminToken, map[string]bool{"required": false})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = authorizedRequest(server, "PUT", fmt.Sprintf("/user/roles/%v/two-factor", editor.ID), adminToken, map[string]bool{"required": true})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"require_two_factor":true`)
+
+	w = requestToken(server, editorUser, map[string]string{})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"two_factor_enrollment_required":true`)
+}
@@ -9,10 +9,10 @@ import (
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
-	"strconv"
 	"testing"
 	"time"
 
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
@@ -24,10 +24,10 @@ import (
 )
 
 func createUserAndToken(userRepo repository.UserRepository, role string) (entity.User, string) {
-	mobileNumber := strconv.Itoa(rand.Int())
+	mobileNumber := fmt.Sprintf("0912%07d", rand.Intn(10000000))
 	user := entity.NewUser("something", mobileNumber, role)
 	userRepo.Save(&user)
-	token, err := utils.GenerateAccessToken(user.ID, mobileNumber, user.Role)
+	token, err := utils.GenerateAccessToken(app.TestSecretKey, user.ID, user.MobileNumber, user.Role)
 	if err != nil {
 		panic(token)
 	}
@@ -39,7 +39,7 @@ func TestAuthenticateHandler(t *testing.T) {
 	invalidMobileNumber := "1234"
 	body, _ := json.Marshal(map[string]string{"mobile_number": invalidMobileNumber})
 	server := gin.Default()
-	routers.UserRouters(server, "user")
+	routers.UserRouters(server, "user", app.TestContainer())
 	req, _ := http.NewRequest("POST", "/user/authenticate", bytes.NewBuffer(body))
 	w := httptest.NewRecorder()
 	server.ServeHTTP(w, req)
@@ -67,12 +67,38 @@ func TestAuthenticateHandler(t *testing.T) {
 	redis.InitiateTestClient()
 }
 
+func TestAuthenticateInternationalNumber(t *testing.T) {
+	redis.InitiateTestClient()
+	database.InitiateTestDB()
+
+	server := gin.Default()
+	routers.UserRouters(server, "user", app.TestContainer())
+
+	body, _ := json.Marshal(map[string]string{"mobile_number": "+44 7911 123456"})
+	req, _ := http.NewRequest("POST", "/user/authenticate", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	responseData, _ := io.ReadAll(w.Body)
+	assert.Equal(t, `{"message":"otp code sent","mobile_number":"+447911123456"}`, string(responseData))
+
+	var user entity.User
+	repository.NewUserRepository(database.TestDb()).ByMobileNumber("+447911123456", &user)
+	assert.Equal(t, "GB", user.Country)
+
+	landline, _ := json.Marshal(map[string]string{"mobile_number": "+442071234567"})
+	req, _ = http.NewRequest("POST", "/user/authenticate", bytes.NewBuffer(landline))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestTokenHandler(t *testing.T) {
 	redis.InitiateTestClient()
 	database.InitiateTestDB()
 
 	server := gin.Default()
-	routers.UserRouters(server, "user")
+	routers.UserRouters(server, "user", app.TestContainer())
 	mobileNumber := "09001234141"
 	code := "123456"
 	body, _ := json.Marshal(map[string]string{"mobile_number": mobileNumber, "code": "wrongCode"})
@@ -85,8 +111,9 @@ func TestTokenHandler(t *testing.T) {
 	assert.Equal(t, invalidTimeResponse, string(responseData))
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	client := redis.TestClient()
-	client.Set(context.TODO(), mobileNumber, code, time.Hour)
+	otpRepo := repository.NewOTPCodeRepository(redis.TestClient())
+	otpCode := entity.OTPCode{MobileNumber: mobileNumber, Purpose: entity.LoginPurpose, Code: code, TTL: time.Hour}
+	otpRepo.Save(context.TODO(), &otpCode)
 
 	invalidTimeResponse = `{"message":"this code is incorrect"}`
 	req, _ = http.NewRequest("POST", "/user/token", bytes.NewBuffer(body))
@@ -115,7 +142,7 @@ func TestMeHandler(t *testing.T) {
 	user, token := createUserAndToken(userRepo, entity.UserRole)
 
 	server := gin.Default()
-	routers.UserRouters(server, "user")
+	routers.UserRouters(server, "user", app.TestContainer())
 
 	req, _ := http.NewRequest("GET", "/user/me", nil)
 	w := httptest.NewRecorder()
@@ -143,7 +170,7 @@ func TestEditMeInfo(t *testing.T) {
 	user, token := createUserAndToken(userRepo, entity.UserRole)
 
 	server := gin.Default()
-	routers.UserRouters(server, "user")
+	routers.UserRouters(server, "user", app.TestContainer())
 
 	body, _ := json.Marshal(map[string]string{"full_name": "something else"})
 	req, _ := http.NewRequest("PUT", "/user/me", bytes.NewBuffer(body))
@@ -177,34 +204,38 @@ func TestDeleteAccount(t *testing.T) {
 	userRepo := repository.NewUserRepository(db)
 	user, token := createUserAndToken(userRepo, entity.UserRole)
 
-	userRepo.Save(&user)
-	var count int64
-	db.Model(&entity.User{}).Count(&count)
-
 	server := gin.Default()
-	routers.UserRouters(server, "user")
+	routers.UserRouters(server, "user", app.TestContainer())
 
 	req, _ := http.NewRequest("DELETE", "/user/me", nil)
 	w := httptest.NewRecorder()
 	server.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 
-	req, _ = http.NewRequest("DELETE", "/user/me", nil)
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
-	w = httptest.NewRecorder()
-	server.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = authorizedRequest(server, "DELETE", "/user/me", token, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
 
-	userRepo.Delete(&user)
-	var countAfterDelete int64
-	db.Model(&entity.User{}).Count(&countAfterDelete)
-	assert.Equal(t, countAfterDelete, count-1)
+	w = authorizedRequest(server, "POST", "/user/me/deletion/confirm", token, map[string]string{"code": "000000"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	req, _ = http.NewRequest("GET", "/user/me", nil)
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
-	w = httptest.NewRecorder()
-	server.ServeHTTP(w, req)
+	otpRepo := repository.NewOTPCodeRepository(redis.TestClient())
+	otpRepo.Save(context.TODO(), &entity.OTPCode{MobileNumber: user.MobileNumber, Purpose: entity.DeleteAccountPurpose, Code: "123456", TTL: time.Minute})
+	w = authorizedRequest(server, "POST", "/user/me/deletion/confirm", token, map[string]string{"code": "123456"})
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), "scheduled_for")
+
+	// the account is kept during the grace period but old tokens stop working
+	assert.NoError(t, userRepo.ById(user.ID, &entity.User{}).Error)
+	w = authorizedRequest(server, "GET", "/user/me", token, nil)
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = requestToken(server, user, map[string]string{})
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := map[string]any{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, true, response["deletion_cancelled"])
+	w = authorizedRequest(server, "GET", "/user/me", response["token"].(string), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
 }
 
 func TestAllUsers(t *testing.T) {
@@ -215,7 +246,7 @@ func TestAllUsers(t *testing.T) {
 	userRepo := repository.NewUserRepository(db)
 
 	server := gin.Default()
-	routers.UserRouters(server, "user")
+	routers.UserRouters(server, "user", app.TestContainer())
 
 	_, userToken := createUserAndToken(userRepo, entity.UserRole)
 	req, _ := http.NewRequest("GET", "/user/users", nil)
@@ -247,7 +278,7 @@ func TestRetrieveUser(t *testing.T) {
 	userRepo := repository.NewUserRepository(db)
 
 	server := gin.Default()
-	routers.UserRouters(server, "user")
+	routers.UserRouters(server, "user", app.TestContainer())
 
 	_, userToken := createUserAndToken(userRepo, entity.UserRole)
 	req, _ := http.NewRequest("GET", "/user/users/1", nil)
@@ -286,7 +317,7 @@ func TestUpdateUser(t *testing.T) {
 	body, _ := json.Marshal(map[string]string{"full_name": "something", "role": "Admin"})
 
 	server := gin.Default()
-	routers.UserRouters(server, "user")
+	routers.UserRouters(server, "user", app.TestContainer())
 
 	_, userToken := createUserAndToken(userRepo, entity.UserRole)
 	req, _ := http.NewRequest("PUT", "/user/users/1", nil)
@@ -358,7 +389,7 @@ func TestDeleteUser(t *testing.T) {
 	db.Model(&entity.User{}).Count(&count)
 
 	server := gin.Default()
-	routers.UserRouters(server, "user")
+	routers.UserRouters(server, "user", app.TestContainer())
 
 	req, _ := http.NewRequest("DELETE", "/user/users/1", nil)
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// requestLocale negotiates the locale of the response from the lang query
+// parameter and the Accept-Language header and announces it in the
+// Content-Language header.
+func requestLocale(context *gin.Context) string {
+	locale := utils.NegotiateLocale(context.Query("lang"), context.GetHeader("Accept-Language"), entity.Locales, entity.DefaultLocale)
+	context.Header("Content-Language", locale)
+	return locale
+}
+
+// UpdateStateTranslations godoc
+// @Summary Update State Translations
+// @Description Replaces the titles of a state in other locales than the default one. Locales left out have their translation removed.
+// @Tags states
+// @Accept json
+// @Produce json
+// @Param id path int true "State ID"
+// @Param translations body models.Translations true "Titles keyed by locale, e.g. en and ar"
+// @Success 200 {object} models.StateResponse "Updated state"
+// @Failure 400 {object} map[string]interface{} "Invalid ID, body or locale"
+// @Failure 404 {object} map[string]interface{} "State not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/states/{id}/translations [put]
+// @Security BearerAuth
+func (h Handler) UpdateStateTranslations(context *gin.Context) {
+	id, ok := locationId(context, "id")
+	if !ok {
+		return
+	}
+	body := new(models.Translations)
+	if err := context.BindJSON(body); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	state, err := h.States.UpdateTranslations(context, id, body.Translations)
+	switch {
+	case errors.Is(err, usecase.ErrInvalidTranslation):
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		context.JSON(http.StatusNotFound, gin.H{"message": "state not found"})
+	case err != nil:
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	default:
+		context.JSON(http.StatusOK, models.NewStateResponse(state))
+	}
+}
+
+// UpdateCityTranslations godoc
+// @Summary Update City Translations
+// @Description Replaces the titles of a city in other locales than the default one. Locales left out have their translation removed.
+// @Tags states
+// @Accept json
+// @Produce json
+// @Param id path int true "State ID"
+// @Param cityId path int true "City ID"
+// @Param translations body models.Translations true "Titles keyed by locale, e.g. en and ar"
+// @Success 200 {object} models.CityResponse "Updated city"
+// @Failure 400 {object} map[string]interface{} "Invalid ID, body or locale"
+// @Failure 404 {object} map[string]interface{} "City not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/states/{id}/city/{cityId}/translations [put]
+// @Security BearerAuth
+func (h Handler) UpdateCityTranslations(context *gin.Context) {
+	stateId, ok := locationId(context, "id")
+	if !ok {
+		return
+	}
+	cityId, ok := locationId(context, "cityId")
+	if !ok {
+		return
+	}
+	if !h.Cities.DoesCityExist(context, cityId, stateId) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "city not found"})
+		return
+	}
+	body := new(models.Translations)
+	if err := context.BindJSON(body); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	city, err := h.Cities.UpdateTranslations(context, cityId, body.Translations)
+	switch {
+	case errors.Is(err, usecase.ErrInvalidTranslation):
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case err != nil:
+		context.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	default:
+		context.JSON(http.StatusOK, models.NewCityResponse(city))
+	}
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TrashedUsers godoc
+// @Summary Deleted Users
+// @Description Lists deleted users that can still be restored or purged, most recently deleted first.
+// @Tags Trash
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page-size query int false "Number of users per page" default(10)
+// @Success 200 {object} utils.PaginatedResponse{result=[]models.UserResponse} "Deleted users"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/trash/users [get]
+// @Security BearerAuth
+func (h Handler) TrashedUsers(context *gin.Context) {
+	pageSize := utils.ParseQueryParamToInt(context.Query("page-size"), 10)
+	pageNumber := utils.ParseQueryParamToInt(context.Query("page"), 1)
+	users, count, err := h.Users.Trashed(pageNumber, pageSize)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, utils.GenerateListResponse(models.NewUserListResponse(users), count, pageSize, pageNumber))
+}
+
+// RestoreUser godoc
+// @Summary Restore User
+// @Description Restores a deleted user. Fails when their mobile number has been registered again since or the account was anonymized.
+// @Tags Trash
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} models.UserResponse "Restored user"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "User not in trash"
+// @Failure 409 {object} map[string]interface{} "Mobile number taken or user anonymized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/trash/users/{id}/restore [post]
+// @Security BearerAuth
+func (h Handler) RestoreUser(context *gin.Context) {
+	id, ok := trashId(context, "id")
+	if !ok {
+		return
+	}
+	user, err := h.Users.Restore(context, id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		context.JSON(http.StatusNotFound, gin.H{"message": "user not in trash"})
+	case errors.Is(err, usecase.ErrMobileNumberTaken), errors.Is(err, usecase.ErrUserAnonymized):
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case err != nil:
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+	default:
+		context.JSON(http.StatusOK, models.NewUserResponse(user))
+	}
+}
+
+// PurgeUser godoc
+// @Summary Purge User
+// @Description Permanently removes a deleted user together with their two-factor enrollment and API keys.
+// @Tags Trash
+// @Param id path int true "User ID"
+// @Success 204 "User purged"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "User not in trash"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/trash/users/{id} [delete]
+// @Security BearerAuth
+func (h Handler) PurgeUser(context *gin.Context) {
+	id, ok := trashId(context, "id")
+	if !ok {
+		return
+	}
+	err := h.Users.Purge(context, id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		context.JSON(http.StatusNotFound, gin.H{"message": "user not in trash"})
+	case err != nil:
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+	default:
+		context.Status(http.StatusNoContent)
+	}
+}
+
+// TrashedStates godoc
+// @Summary Deleted States
+// @Description Lists deleted states that can still be restored or purged, most recently deleted first.
+// @Tags Trash
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page-size query int false "Number of states per page" default(10)
+// @Success 200 {object} utils.PaginatedResponse{result=[]models.StateResponse} "Deleted states"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/trash/states [get]
+// @Security BearerAuth
+func (h Handler) TrashedStates(context *gin.Context) {
+	pageSize := utils.ParseQueryParamToInt(context.Query("page-size"), 10)
+	pageNumber := utils.ParseQueryParamToInt(context.Query("page"), 1)
+	states, count, err := h.States.Trashed(context, pageNumber, pageSize)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, utils.GenerateListResponse(models.NewStateListResponse(states), count, pageSize, pageNumber))
+}
+
+// RestoreState godoc
+// @Summary Restore State
+// @Description Restores a deleted state. Its deleted cities stay in the trash.
+// @Tags Trash
+// @Produce json
+// @Param id path int true "State ID"
+// @Success 200 {object} models.StateResponse "Restored state"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "State not in trash"
+// @Failure 409 {object} map[string]interface{} "A state with the same title exists"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/trash/states/{id}/restore [post]
+// @Security BearerAuth
+func (h Handler) RestoreState(context *gin.Context) {
+	id, ok := trashId(context, "id")
+	if !ok {
+		return
+	}
+	state, err := h.States.Restore(context, id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		context.JSON(http.StatusNotFound, gin.H{"message": "state not in trash"})
+	case errors.Is(err, usecase.ErrStateTitleTaken):
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case err != nil:
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+	default:
+		context.JSON(http.StatusOK, models.NewStateResponse(state))
+	}
+}
+
+// PurgeState godoc
+// @Summary Purge State
+// @Description Permanently removes a deleted state. Every city of the state has to be purged first.
+// @Tags Trash
+// @Param id path int true "State ID"
+// @Success 204 "State purged"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "State not in trash"
+// @Failure 409 {object} map[string]interface{} "State still has cities"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/trash/states/{id} [delete]
+// @Security BearerAuth
+func (h Handler) PurgeState(context *gin.Context) {
+	id, ok := trashId(context, "id")
+	if !ok {
+		return
+	}
+	err := h.States.Purge(context, id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		context.JSON(http.StatusNotFound, gin.H{"message": "state not in trash"})
+	case errors.Is(err, usecase.ErrStateHasCities):
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case err != nil:
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+	default:
+		context.Status(http.StatusNoContent)
+	}
+}
+
+// TrashedCities godoc
+// @Summary Deleted Cities
+// @Description Lists a state's deleted cities, most recently deleted first. The state itself may be deleted too.
+// @Tags Trash
+// @Produce json
+// @Param id path int true "State ID"
+// @Param page query int false "Page number" default(1)
+// @Param page-size query int false "Number of cities per page" default(10)
+// @Success 200 {object} utils.PaginatedResponse{result=[]models.CityResponse} "Deleted cities"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/trash/states/{id}/cities [get]
+// @Security BearerAuth
+func (h Handler) TrashedCities(context *gin.Context) {
+	stateId, ok := trashId(context, "id")
+	if !ok {
+		return
+	}
+	pageSize := utils.ParseQueryParamToInt(context.Query("page-size"), 10)
+	pageNumber := utils.ParseQueryParamToInt(context.Query("page"), 1)
+	cities, count, err := h.Cities.Trashed(context, stateId, pageNumber, pageSize)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, utils.GenerateListResponse(models.NewCityListResponse(cities), count, pageSize, pageNumber))
+}
+
+// RestoreCity godoc
+// @Summary Restore City
+// @Description Restores a deleted city. Its state has to be restored first if it is deleted too.
+// @Tags Trash
+// @Produce json
+// @Param cityId path int true "City ID"
+// @Success 200 {object} models.CityResponse "Restored city"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "City not in trash"
+// @Failure 409 {object} map[string]interface{} "State is deleted or has a city with the same title"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/trash/cities/{cityId}/restore [post]
+// @Security BearerAuth
+func (h Handler) RestoreCity(context *gin.Context) {
+	id, ok := trashId(context, "cityId")
+	if !ok {
+		return
+	}
+	city, err := h.Cities.Restore(context, id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		context.JSON(http.StatusNotFound, gin.H{"message": "city not in trash"})
+	case errors.Is(err, usecase.ErrStateDeleted), errors.Is(err, usecase.ErrCityTitleTaken):
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case err != nil:
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+	default:
+		context.JSON(http.StatusOK, models.NewCityResponse(city))
+	}
+}
+
+// PurgeCity godoc
+// @Summary Purge City
+// @Description Permanently removes a deleted city. Its districts have to be deleted first.
+// @Tags Trash
+// @Param cityId path int true "City ID"
+// @Success 204 "City purged"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 404 {object} map[string]interface{} "City not in trash"
+// @Failure 409 {object} map[string]interface{} "City still has districts"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /settings/trash/cities/{cityId} [delete]
+// @Security BearerAuth
+func (h Handler) PurgeCity(context *gin.Context) {
+	id, ok := trashId(context, "cityId")
+	if !ok {
+		return
+	}
+	err := h.Cities.Purge(context, id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		context.JSON(http.StatusNotFound, gin.H{"message": "city not in trash"})
+	case errors.Is(err, usecase.ErrCityHasDistricts):
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case err != nil:
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+	default:
+		context.Status(http.StatusNoContent)
+	}
+}
+
+func trashId(context *gin.Context, param string) (uint, bool) {
+	id, err := strconv.ParseUint(context.Param(param), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// EnrollTwoFactor godoc
+// @Summary Enroll Two-Factor Authentication
+// @Description Creates a TOTP secret for the authenticated user. The provisioning URI can be shown as a QR code; the recovery codes are only returned in this response. The enrollment is inactive until confirmed.
+// @Tags Two-Factor
+// @Produce json
+// @Success 200 {object} models.TwoFactorEnrollmentResponse "TOTP secret and recovery codes"
+// @Failure 409 {object} map[string]interface{} "Two-factor authentication is already enabled"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/me/2fa [post]
+// @Security BearerAuth
+func (h Handler) EnrollTwoFactor(context *gin.Context) {
+	user, err := h.Users.GetUserById(context.GetUint("userId"))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	twoFactor, recoveryCodes, err := h.TwoFactor.Enroll(context, user.ID)
+	if errors.Is(err, usecase.ErrTwoFactorEnabled) {
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, models.TwoFactorEnrollmentResponse{
+		Secret:          twoFactor.Secret,
+		ProvisioningURI: twoFactor.ProvisioningURI(user.MobileNumber),
+		RecoveryCodes:   recoveryCodes,
+	})
+}
+
+// ConfirmTwoFactor godoc
+// @Summary Confirm Two-Factor Authentication
+// @Description Activates the enrollment with a first code from the authenticator app and returns a new access token.
+// @Tags Two-Factor
+// @Accept json
+// @Produce json
+// @Param code body models.ConfirmTwoFactor true "TOTP code"
+// @Success 200 {object} map[string]interface{} "Two-factor authentication enabled"
+// @Failure 400 {object} map[string]interface{} "Invalid code or no enrollment"
+// @Failure 409 {object} map[string]interface{} "Two-factor authentication is already enabled"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/me/2fa/confirm [post]
+// @Security BearerAuth
+func (h Handler) ConfirmTwoFactor(context *gin.Context) {
+	body := new(models.ConfirmTwoFactor)
+	err := context.BindJSON(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	user, err := h.Users.GetUserById(context.GetUint("userId"))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	err = h.TwoFactor.Confirm(context, user.ID, body.Code)
+	switch {
+	case errors.Is(err, usecase.ErrTwoFactorEnabled):
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	case errors.Is(err, usecase.ErrTwoFactorInvalid), errors.Is(err, usecase.ErrTwoFactorNotEnrolled):
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	case err != nil:
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	accessToken, err := utils.GenerateAccessToken(h.SecretKey, user.ID, user.MobileNumber, user.Role)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"message": "two-factor authentication enabled", "token": accessToken})
+}
+
+// ResetTwoFactor godoc
+// @Summary Reset Two-Factor Authentication
+// @Description Removes a user's two-factor enrollment, e.g. after the authenticator and recovery codes were lost. The reason is kept in the audit log.
+// @Tags Two-Factor
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param reason body models.ResetTwoFactor true "Reason for the reset"
+// @Success 204 "Enrollment removed"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/users/{id}/2fa/reset [post]
+// @Security BearerAuth
+func (h Handler) ResetTwoFactor(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return
+	}
+	if !h.Users.DoesUserExist(uint(id)) {
+		context.JSON(http.StatusNotFound, gin.H{"message": "user not found"})
+		return
+	}
+	body := new(models.ResetTwoFactor)
+	err = context.BindJSON(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	err = h.TwoFactor.Reset(context, uint(id))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	err = h.Audit.Record(context, context.GetUint("userId"), entity.ResetTwoFactorAction, "user", uint(id), body.Reason)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusNoContent, nil)
+}
+
+// UpdateRoleTwoFactor godoc
+// @Summary Enforce Two-Factor Authentication for a Role
+// @Description Sets whether users of the role must log in with a second factor. Admin and Support always require it.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param id path int true "Role ID"
+// @Param twoFactor body models.RoleTwoFactor true "Whether two-factor authentication is required"
+// @Success 200 {object} models.RoleResponse "Updated role"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "Role not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /user/roles/{id}/two-factor [put]
+// @Security BearerAuth
+func (h Handler) UpdateRoleTwoFactor(context *gin.Context) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
+		return
+	}
+	body := new(models.RoleTwoFactor)
+	err = context.BindJSON(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	role, err := h.Roles.SetRequireTwoFactor(context, uint(id), *body.Required)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		context.JSON(http.StatusNotFound, gin.H{"message": "role not found"})
+		return
+	case errors.Is(err, usecase.ErrTwoFactorForced):
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	case err != nil:
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.JSON(http.StatusOK, models.NewRoleResponse(role))
+}
+
+// secondFactorStatus reports whether the user's role requires two-factor
+// authentication and whether the user has enrolled. Users may enroll
+// without their role requiring it.
+func (h Handler) secondFactorStatus(context *gin.Context, user entity.User) (bool, bool, error) {
+	role, err := h.Roles.ByName(context, user.Role)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, false, err
+	}
+	enabled, err := h.TwoFactor.IsEnabled(context, user.ID)
+	return role.RequiresTwoFactor(), enabled, err
+}
+
+// accessTokenFor issues a regular access token, or a pending one that only
+// reaches the user's own account while a required enrollment is missing.
+func (h Handler) accessTokenFor(user entity.User, required, enabled bool) (string, bool, error) {
+	if required && !enabled {
+		token, err := utils.GenerateTwoFactorPendingToken(h.SecretKey, user.ID, user.MobileNumber, user.Role)
+		return token, true, err
+	}
+	token, err := utils.GenerateAccessToken(h.SecretKey, user.ID, user.MobileNumber, user.Role)
+	return token, false, err
+}
@@ -1,19 +1,19 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
-	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
-	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/redis"
-	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
 	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
 	"github.com/TheAmirhosssein/room-reservation-api/pkg/validators"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // Authenticate godoc
@@ -25,72 +25,131 @@ import (
 // @Param authenticateUser body models.Authenticate true "User authentication data"
 // @Success 200 {object} map[string]interface{} "OTP code sent successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid mobile number format or other errors"
+// @Failure 403 {object} models.SuspendedAccountResponse "Account suspended"
 // @Router /user/authenticate [post]
-func Authenticate(context *gin.Context) {
+func (h Handler) Authenticate(context *gin.Context) {
 	authenticateUser := models.Authenticate{}
 	err := context.BindJSON(&authenticateUser)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, err.Error())
 		return
 	}
-	if !validators.ValidateMobileNumber(authenticateUser.MobileNumber) {
-		context.JSON(http.StatusBadRequest, gin.H{"message": "mobile number format is not valid"})
+	mobileNumber, err := validators.NormalizeMobileNumber(authenticateUser.MobileNumber)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	userRepo := repository.NewUserRepository(database.GetDb())
-	userUseCase := usecase.NewUserUseCase(userRepo)
-	user, err := userUseCase.GetUserOrCreate(authenticateUser.MobileNumber)
+	user, err := h.Users.GetUserOrCreate(mobileNumber)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	otpRepo := repository.NewOTPCodeRepository(redis.GetClient())
-	otpUseCase := usecase.NewOTPCase(otpRepo)
-	code, err := otpUseCase.GenerateCode(context, user.MobileNumber)
+	if user.IsSuspended(time.Now()) {
+		context.JSON(http.StatusForbidden, models.NewSuspendedAccountResponse(*user))
+		return
+	}
+	code, err := h.OTP.GenerateCode(context, user.MobileNumber, entity.LoginPurpose)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	fmt.Printf("code was %v\n", code)
+	message := fmt.Sprintf("your login code is %v", code)
+	err = h.SMS.Send(context, user.Country, user.MobileNumber, message)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not send otp code"})
+		return
+	}
+	h.recordLogin(context, *user, user.MobileNumber, entity.OTPRequestedEvent)
 	response := gin.H{"message": "otp code sent", "mobile_number": user.MobileNumber}
 	context.JSON(http.StatusOK, response)
 }
 
 // Token godoc
 // @Summary Validate OTP and Generate Token
-// @Description Validates the OTP for a given mobile number and generates an access token.
+// @Description Validates the OTP for a given mobile number and generates an access token. Users with two-factor authentication enabled also send a totp_code or recovery_code; users whose role requires it but who have not enrolled get a token that only reaches their own account until they do.
 // @Tags Authentication
 // @Accept  json
 // @Produce  json
 // @Param token body models.Token true "OTP validation data"
 // @Success 200 {object} map[string]interface{} "Access token generated successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid OTP or mobile number"
+// @Failure 401 {object} map[string]interface{} "Missing or invalid two-factor code"
+// @Failure 403 {object} models.SuspendedAccountResponse "Account suspended"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /user/token [post]
-func Token(context *gin.Context) {
+func (h Handler) Token(context *gin.Context) {
 	body := models.Token{}
 	err := context.BindJSON(&body)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	otpRepo := repository.NewOTPCodeRepository(redis.GetClient())
-	otpUseCase := usecase.NewOTPCase(otpRepo)
-	err = otpUseCase.ValidateCode(context, body.MobileNumber, body.Code)
+	mobileNumber, err := validators.NormalizeMobileNumber(body.MobileNumber)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	userRepo := repository.NewUserRepository(database.GetDb())
-	userUseCase := usecase.NewUserUseCase(userRepo)
 	var user entity.User
-	userUseCase.Repo.ByMobileNumber(body.MobileNumber, &user)
-	accessToken, err := utils.GenerateAccessToken(user.ID, user.MobileNumber, user.Role)
+	h.Users.Repo.ByMobileNumber(mobileNumber, &user)
+	err = h.OTP.CheckCode(context, mobileNumber, entity.LoginPurpose, body.Code)
+	if err != nil {
+		h.recordLogin(context, user, mobileNumber, entity.LoginFailedEvent)
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	// the code may have been sent before the account was suspended
+	if user.IsSuspended(time.Now()) {
+		context.JSON(http.StatusForbidden, models.NewSuspendedAccountResponse(user))
+		return
+	}
+	required, enabled, err := h.secondFactorStatus(context, user)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
 		return
 	}
-	context.JSON(http.StatusOK, gin.H{"token": accessToken})
+	// the otp code stays valid so the client can retry with the second factor
+	if enabled && body.TOTPCode == "" && body.RecoveryCode == "" {
+		context.JSON(http.StatusUnauthorized, gin.H{"message": usecase.ErrTwoFactorRequired.Error(), "two_factor_required": true})
+		return
+	}
+	if enabled {
+		err = h.TwoFactor.Verify(context, user.ID, body.TOTPCode, body.RecoveryCode)
+		if errors.Is(err, usecase.ErrTwoFactorInvalid) {
+			h.recordLogin(context, user, mobileNumber, entity.LoginFailedEvent)
+			context.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+			return
+		}
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+			return
+		}
+	}
+	// the otp code is only used up once both factors passed
+	err = h.OTP.ValidateCode(context, mobileNumber, entity.LoginPurpose, body.Code)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	// logging in during the grace period keeps the account
+	deletionCancelled, err := h.Accounts.CancelDeletion(context, &user)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	accessToken, pending, err := h.accessTokenFor(user, required, enabled)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	h.recordLogin(context, user, mobileNumber, entity.LoginSucceededEvent)
+	response := gin.H{"token": accessToken}
+	if pending {
+		response["two_factor_enrollment_required"] = true
+	}
+	if deletionCancelled {
+		response["deletion_cancelled"] = true
+	}
+	context.JSON(http.StatusOK, response)
 }
 
 // Me godoc
@@ -102,12 +161,9 @@ func Token(context *gin.Context) {
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /user/me [get]
 // @Security BearerAuth
-func Me(context *gin.Context) {
-	db := database.GetDb()
-	userRepo := repository.NewUserRepository(db)
-	userUseCase := usecase.NewUserUseCase(userRepo)
+func (h Handler) Me(context *gin.Context) {
 	userId := context.GetUint("userId")
-	user, err := userUseCase.GetUserById(userId)
+	user, err := h.Users.GetUserById(userId)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
 		return
@@ -128,7 +184,7 @@ func Me(context *gin.Context) {
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /user/me [put]
 // @Security BearerAuth
-func UpdateUser(context *gin.Context) {
+func (h Handler) UpdateUser(context *gin.Context) {
 	body := new(models.UpdateUser)
 	err := context.BindJSON(body)
 	if err != nil {
@@ -136,17 +192,14 @@ func UpdateUser(context *gin.Context) {
 		return
 	}
 
-	db := database.GetDb()
-	repo := repository.NewUserRepository(db)
-	useCase := usecase.NewUserUseCase(repo)
 	data := map[string]any{"FullName": body.FullName}
 	id := context.GetUint("userId")
-	err = useCase.Update(id, data)
+	err = h.Users.Update(context, id, data)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong"})
 		return
 	}
-	user, err := useCase.GetUserById(id)
+	user, err := h.Users.GetUserById(id)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong"})
 		return
@@ -156,53 +209,70 @@ func UpdateUser(context *gin.Context) {
 }
 
 // DeleteAccount godoc
-// @Summary Delete User Account
-// @Description Deletes the authenticated user's account.
+// @Summary Request Account Deletion
+// @Description Sends a code to the authenticated user's mobile number. Confirming it with /user/me/deletion/confirm schedules the account for deletion.
 // @Tags User
 // @Produce  json
-// @Success 204 "User account deleted successfully"
+// @Success 200 {object} map[string]interface{} "OTP code sent"
+// @Failure 409 {object} map[string]interface{} "The last active admin can not be deleted"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /user/me [delete]
 // @Security BearerAuth
-func DeleteAccount(context *gin.Context) {
-	db := database.GetDb()
-	repo := repository.NewUserRepository(db)
-	useCase := usecase.NewUserUseCase(repo)
-	err := useCase.DeleteById(context.GetUint("userId"))
+func (h Handler) DeleteAccount(context *gin.Context) {
+	user, err := h.Accounts.CanDelete(context.GetUint("userId"))
+	if errors.Is(err, usecase.ErrLastAdmin) {
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong"})
 		return
 	}
-	context.JSON(http.StatusNoContent, nil)
+	code, err := h.OTP.GenerateCode(context, user.MobileNumber, entity.DeleteAccountPurpose)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	message := fmt.Sprintf("your account deletion code is %v", code)
+	err = h.SMS.Send(context, user.Country, user.MobileNumber, message)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "could not send otp code"})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"message": "otp code sent", "mobile_number": user.MobileNumber})
 }
 
 // AllUsers godoc
 // @Summary Retrieve All Users
-// @Description Fetches a paginated list of users, with optional filters for mobile number and full name.
+// @Description Fetches a paginated list of users, with optional filters for mobile number, full name and status.
 // @Tags User
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param page-size query int false "Number of users per page" default(10)
 // @Param mobile-number query string false "Filter by mobile number"
 // @Param full-name query string false "Filter by full name"
+// @Param status query string false "Filter by status" Enums(active, suspended, banned)
 // @Success 200 {object} utils.PaginatedResponse{result=[]models.UserResponse} "List of users retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid status"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /user/users [get]
 // @Security BearerAuth
-func AllUsers(context *gin.Context) {
-	db := database.GetDb()
-	repo := repository.NewUserRepository(db)
-	useCase := usecase.NewUserUseCase(repo)
+func (h Handler) AllUsers(context *gin.Context) {
 	pageSize := utils.ParseQueryParamToInt(context.Query("page-size"), 10)
 	pageNumber := utils.ParseQueryParamToInt(context.Query("page"), 1)
 	mobileNumber := context.Query("mobile-number")
 	fullName := context.Query("full-name")
-	allUser, err := useCase.GetUsersList(pageNumber, pageSize, mobileNumber, fullName)
+	status := context.Query("status")
+	if status != "" && !entity.IsUserStatusValid(status) {
+		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid status"})
+		return
+	}
+	allUser, err := h.Users.GetUsersList(pageNumber, pageSize, mobileNumber, fullName, status)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong"})
 		return
 	}
-	usersCount, err := useCase.Count()
+	usersCount, err := h.Users.Count()
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong"})
 		return
@@ -224,17 +294,14 @@ func AllUsers(context *gin.Context) {
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /user/users/{id} [get]
 // @Security BearerAuth
-func RetrieveUser(context *gin.Context) {
+func (h Handler) RetrieveUser(context *gin.Context) {
 	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
 		return
 	}
-	db := database.GetDb()
-	userRepo := repository.NewUserRepository(db)
-	userUseCase := usecase.NewUserUseCase(userRepo)
-	user, err := userUseCase.GetUserById(uint(id))
-	if !userUseCase.DoesUserExist(user.ID) {
+	user, err := h.Users.GetUserById(uint(id))
+	if !h.Users.DoesUserExist(user.ID) {
 		context.JSON(http.StatusNotFound, gin.H{"message": "user not found"})
 		return
 	}
@@ -248,55 +315,67 @@ func RetrieveUser(context *gin.Context) {
 
 // EditUser godoc
 // @Summary Edit User Information
-// @Description Updates user details by ID, including full name and role.
+// @Description Updates user details by ID, including full name and role. Admins removing their own admin role have to send confirm, and the last active admin can not be demoted.
 // @Tags User
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
 // @Param updateUser body models.AdminUpdateUser true "User update data"
 // @Success 200 {object} models.UserResponse "User details updated successfully"
-// @Failure 400 {object} map[string]interface{} "Invalid request body or role"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or role, or unconfirmed self-demotion"
+// @Failure 403 {object} map[string]interface{} "Forbidden: insufficient permissions"
 // @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 409 {object} map[string]interface{} "The last active admin can not be demoted"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /user/users/{id} [put]
 // @Security BearerAuth
-func EditUser(context *gin.Context) {
+func (h Handler) EditUser(context *gin.Context) {
 	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
 		return
 	}
-	db := database.GetDb()
-	repo := repository.NewUserRepository(db)
-	userUseCase := usecase.NewUserUseCase(repo)
-	if !userUseCase.DoesUserExist(uint(id)) {
+	if !h.Users.DoesUserExist(uint(id)) {
 		context.JSON(http.StatusNotFound, gin.H{"message": "user not found"})
 		return
 	}
 	var updateData map[string]any
-	role := context.GetString("role")
 	data := new(models.AdminUpdateUser)
 	err = context.BindJSON(data)
 	if err != nil {
 		context.JSONP(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
-	if !validators.IsRoleValid(data.Role) {
-		context.JSONP(http.StatusBadRequest, gin.H{"message": "invalid role"})
+	user, err := h.Users.GetUserById(uint(id))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
 		return
 	}
-	if role == entity.SupportRole && data.Role == entity.AdminRole {
-		context.JSONP(http.StatusBadRequest, gin.H{"message": "invalid role to select"})
+	if data.Role != user.Role {
+		allowed, err := h.Roles.HasPermission(context, context.GetString("role"), entity.UsersUpdateRolePermission)
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+			return
+		}
+		if !allowed {
+			context.JSON(http.StatusForbidden, gin.H{"message": "you have no permission to perform this action"})
+			return
+		}
+	}
+	if !checkRoleGrantable(context, h.Roles, data.Role) {
+		return
+	}
+	if !checkRoleChange(context, h.Users, user, data.Role, data.Confirm) {
 		return
 	}
 	updateData = map[string]any{"full_name": data.FullName, "role": data.Role}
 
-	err = userUseCase.Update(uint(id), updateData)
+	err = h.Users.Update(context, uint(id), updateData)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
 		return
 	}
-	user, err := userUseCase.GetUserById(uint(id))
+	user, err = h.Users.GetUserById(uint(id))
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
 		return
@@ -315,32 +394,42 @@ func EditUser(context *gin.Context) {
 // @Failure 400 {object} map[string]interface{} "Invalid ID format"
 // @Failure 404 {object} map[string]interface{} "User not found"
 // @Failure 403 {object} map[string]interface{} "Forbidden: insufficient permissions"
+// @Failure 409 {object} map[string]interface{} "The last active admin can not be deleted"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /user/users/{id} [delete]
 // @Security BearerAuth
-func DeleteUser(context *gin.Context) {
+func (h Handler) DeleteUser(context *gin.Context) {
 	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{"message": "invalid endpoint"})
 		return
 	}
-	db := database.GetDb()
-	userRepo := repository.NewUserRepository(db)
-	userUseCase := usecase.NewUserUseCase(userRepo)
-	if !userUseCase.DoesUserExist(uint(id)) {
+	if !h.Users.DoesUserExist(uint(id)) {
 		context.JSON(http.StatusNotFound, gin.H{"message": "user not found"})
 		return
 	}
-	deleteUser, err := userUseCase.GetUserById(uint(id))
+	deleteUser, err := h.Users.GetUserById(uint(id))
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
 		return
 	}
-	userRole := context.GetString("role")
-	if userRole == entity.SupportRole && deleteUser.Role == entity.AdminRole {
+	allowed, err := h.Roles.CanGrant(context, context.GetString("role"), deleteUser.Role)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	if !allowed && err == nil {
 		context.JSON(http.StatusForbidden, gin.H{"message": "you have no permission to perform this action"})
 		return
 	}
-	userUseCase.DeleteById(uint(id))
+	err = h.Users.DeleteById(context, uint(id))
+	if errors.Is(err, usecase.ErrLastAdmin) {
+		context.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
 	context.JSON(http.StatusNoContent, nil)
 }
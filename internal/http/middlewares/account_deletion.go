@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// abortIfDeletionPending stops the request of a user who asked for their
+// account to be deleted and reports whether it did. Logging in again keeps
+// the account, so old tokens must not.
+func abortIfDeletionPending(context *gin.Context, user entity.User) bool {
+	if !user.IsDeletionPending() {
+		return false
+	}
+	response := gin.H{"message": "account is scheduled for deletion, log in again to keep it", "deletion_scheduled_for": user.DeletionScheduledFor}
+	context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+	return true
+}
@@ -0,0 +1,66 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyMiddleware authenticates requests carrying an X-API-Key header and
+// hands every other request to AuthenticateMiddleware. A key grants its
+// scopes, limited to the permissions of its owner's role when it belongs
+// to a user.
+func (m Middleware) APIKeyMiddleware(context *gin.Context) {
+	rawKey := context.GetHeader(entity.APIKeyHeader)
+	if rawKey == "" {
+		m.AuthenticateMiddleware(context)
+		return
+	}
+	apiKey, err := m.APIKeys.Authenticate(context, rawKey)
+	if errors.Is(err, usecase.ErrInvalidAPIKey) {
+		context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		context.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	remaining, allowed, err := m.APIKeys.Allow(context, apiKey)
+	if err != nil {
+		context.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+		return
+	}
+	context.Header("X-RateLimit-Limit", strconv.Itoa(apiKey.RateLimit))
+	context.Header("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+	if !allowed {
+		context.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"message": "rate limit exceeded"})
+		return
+	}
+
+	permissions := apiKey.ScopeNames()
+	if apiKey.UserID != nil {
+		user, err := m.Users.GetUserById(*apiKey.UserID)
+		if err != nil {
+			context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "invalid user"})
+			return
+		}
+		if abortIfSuspended(context, user) || abortIfDeletionPending(context, user) {
+			return
+		}
+		permissions, err = m.rolePermissions(context, user.Role, permissions)
+		if err != nil {
+			context.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+			return
+		}
+		context.Set("userId", user.ID)
+		context.Set("mobileNumber", user.MobileNumber)
+		context.Set("role", user.Role)
+	}
+	context.Set("apiKeyId", apiKey.ID)
+	context.Set("permissions", permissions)
+	context.Next()
+}
@@ -1,17 +1,16 @@
 package middlewares
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
-	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
-	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
-	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
 	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-func AuthenticateMiddleware(context *gin.Context) {
+func (m Middleware) AuthenticateMiddleware(context *gin.Context) {
 	authHeader := context.Request.Header.Get("Authorization")
 	if authHeader == "" {
 		context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "no token have been provided"})
@@ -22,21 +21,53 @@ func AuthenticateMiddleware(context *gin.Context) {
 		return
 	}
 	token := strings.Split(authHeader, " ")[1]
-	claims, err := utils.ValidateToken(token)
+	claims, err := utils.ValidateToken(m.SecretKey, token)
 	if err != nil {
 		context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "invalid token"})
 		return
 	}
-	db := database.GetDb()
-	userRepo := repository.NewUserRepository(db)
-	userUseCase := usecase.NewUserUseCase(userRepo)
+	if clientId, ok := claims["client_id"].(string); ok {
+		m.authenticateOAuthToken(context, clientId, claims)
+		return
+	}
 	id := uint(claims["userId"].(float64))
-	if !userUseCase.DoesUserExist(id) {
+	user, err := m.Users.GetUserById(id)
+	if err != nil {
 		context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "invalid user"})
 		return
 	}
+	// tokens issued before a mobile number change are no longer valid
+	if claims["mobileNumber"] != user.MobileNumber {
+		context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "invalid token"})
+		return
+	}
+	if abortIfSuspended(context, user) || abortIfDeletionPending(context, user) {
+		return
+	}
+	_, impersonated := claims["impersonationId"]
+	if impersonated && !m.authenticateImpersonation(context, claims, id) {
+		return
+	}
+	// the stored role wins so role changes apply without waiting for the
+	// token to expire
+	pending, _ := claims["twoFactorPending"].(bool)
+	if claims["role"] != user.Role && !pending {
+		role, err := m.Roles.ByName(context, user.Role)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			context.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+			return
+		}
+		// the token was issued without the second factor the new role needs
+		pending = role.RequiresTwoFactor()
+	}
 	context.Set("userId", id)
-	context.Set("mobileNumber", claims["mobileNumber"].(string))
-	context.Set("role", claims["role"].(string))
+	context.Set("mobileNumber", user.MobileNumber)
+	context.Set("role", user.Role)
+	if pending {
+		context.Set("twoFactorPending", true)
+	}
 	context.Next()
+	if impersonated {
+		m.recordImpersonatedRequest(context)
+	}
 }
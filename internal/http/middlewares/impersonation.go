@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// ForbidImpersonation blocks actions staff must never take on a customer's
+// behalf, such as deleting the account or managing credentials.
+func ForbidImpersonation(context *gin.Context) {
+	if context.GetUint("impersonationId") != 0 {
+		context.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "this action is not allowed while impersonating"})
+		return
+	}
+	context.Next()
+}
+
+// authenticateImpersonation checks that the session behind an impersonation
+// token is still running and marks the request with it.
+func (m Middleware) authenticateImpersonation(context *gin.Context, claims map[string]any, subjectId uint) bool {
+	impersonationId, _ := claims["impersonationId"].(float64)
+	impersonation, err := m.Impersonations.Active(context, uint(impersonationId))
+	if err != nil || impersonation.SubjectID != subjectId {
+		context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "invalid token"})
+		return false
+	}
+	context.Set("impersonationId", impersonation.ID)
+	context.Set("actorId", impersonation.ActorID)
+	context.Header("X-Impersonated-By", fmt.Sprint(impersonation.ActorID))
+	return true
+}
+
+// recordImpersonatedRequest writes the request that just ran to the log and
+// the audit table, attributed to the staff member.
+func (m Middleware) recordImpersonatedRequest(context *gin.Context) {
+	impersonationId, actorId := context.GetUint("impersonationId"), context.GetUint("actorId")
+	details := fmt.Sprintf("%v %v %v", context.Request.Method, context.Request.URL.Path, context.Writer.Status())
+	log.Printf("[impersonation:%v] user %v as user %v: %v", impersonationId, actorId, context.GetUint("userId"), details)
+	err := m.Audit.Record(context, actorId, entity.ImpersonatedRequestAction, "impersonation", impersonationId, details)
+	if err != nil {
+		log.Printf("could not audit impersonated request %v: %v", impersonationId, err)
+	}
+}
@@ -0,0 +1,16 @@
+package middlewares
+
+import "github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+
+// Middleware authenticates and authorizes requests. Its use cases are built
+// once when the application starts and shared by every request.
+type Middleware struct {
+	Users          usecase.UserUseCase
+	Roles          usecase.RoleUseCase
+	APIKeys        usecase.APIKeyUseCase
+	OAuth          usecase.OAuthUseCase
+	Impersonations usecase.ImpersonationUseCase
+	Audit          usecase.AuditLogUseCase
+	// SecretKey verifies the access tokens
+	SecretKey string
+}
@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// request IDs handed in by a proxy are kept when they look sane, so one ID
+// can be followed across services
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID tags every request with an ID, echoed back in the X-Request-ID
+// header and stored as "requestId" for the audit log.
+func RequestID(context *gin.Context) {
+	requestId := context.GetHeader(RequestIDHeader)
+	if !requestIDPattern.MatchString(requestId) {
+		requestId = newRequestID()
+	}
+	context.Set("requestId", requestId)
+	context.Header(RequestIDHeader, requestId)
+	context.Next()
+}
+
+func newRequestID() string {
+	buffer := make([]byte, 16)
+	rand.Read(buffer)
+	return hex.EncodeToString(buffer)
+}
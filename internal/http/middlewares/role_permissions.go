@@ -1,17 +1,65 @@
 package middlewares
 
 import (
+	"errors"
 	"net/http"
+	"slices"
 
-	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-func SupportOrAdminMiddleware(context *gin.Context) {
-	role := context.GetString("role")
-	if !(role == entity.SupportRole || role == entity.AdminRole) {
-		context.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "forbidden"})
-		return
+// RequirePermission only lets the request through when the role of the
+// authenticated user, or the API key used, holds every given permission. It
+// has to run after AuthenticateMiddleware or APIKeyMiddleware. Impersonation
+// sessions are always refused.
+func (m Middleware) RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if context.GetBool("twoFactorPending") {
+			context.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "two-factor enrollment required"})
+			return
+		}
+		// staff impersonating staff must not borrow the target's permissions
+		if context.GetUint("impersonationId") != 0 {
+			context.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "this action is not allowed while impersonating"})
+			return
+		}
+		if granted, ok := context.Get("permissions"); ok {
+			for _, permission := range permissions {
+				if !slices.Contains(granted.([]string), permission) {
+					context.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "forbidden"})
+					return
+				}
+			}
+			context.Next()
+			return
+		}
+		role, err := m.Roles.ByName(context, context.GetString("role"))
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			context.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "something went wrong!"})
+			return
+		}
+		for _, permission := range permissions {
+			if !role.HasPermission(permission) {
+				context.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "forbidden"})
+				return
+			}
+		}
+		context.Next()
 	}
-	context.Next()
+}
+
+// rolePermissions returns the requested permissions that roleName holds.
+func (m Middleware) rolePermissions(context *gin.Context, roleName string, requested []string) ([]string, error) {
+	role, err := m.Roles.ByName(context, roleName)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	var granted []string
+	for _, permission := range requested {
+		if role.HasPermission(permission) {
+			granted = append(granted, permission)
+		}
+	}
+	return granted, nil
 }
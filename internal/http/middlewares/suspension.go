@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/models"
+	"github.com/gin-gonic/gin"
+)
+
+// abortIfSuspended stops the request of a suspended user and reports
+// whether it did.
+func abortIfSuspended(context *gin.Context, user entity.User) bool {
+	if !user.IsSuspended(time.Now()) {
+		return false
+	}
+	context.AbortWithStatusJSON(http.StatusForbidden, models.NewSuspendedAccountResponse(user))
+	return true
+}
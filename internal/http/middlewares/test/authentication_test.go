@@ -7,8 +7,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
-	"github.com/TheAmirhosssein/room-reservation-api/internal/http/middlewares"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
 	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
@@ -18,10 +18,11 @@ import (
 
 func TestAuthentication(t *testing.T) {
 	database.InitiateTestDB()
-	db := database.GetDb()
+	db := database.TestDb()
 
+	m := app.TestContainer().Middleware()
 	server := gin.Default()
-	server.GET("/", middlewares.AuthenticateMiddleware, func(ctx *gin.Context) {
+	server.GET("/", m.AuthenticateMiddleware, func(ctx *gin.Context) {
 		mobileNumber := ctx.GetString("mobileNumber")
 		userId := ctx.GetUint("userId")
 		ctx.JSON(http.StatusOK, gin.H{"id": userId, "mobile_number": mobileNumber})
@@ -53,8 +54,8 @@ func TestAuthentication(t *testing.T) {
 	assert.Equal(t, w.Code, http.StatusUnauthorized)
 	assert.Equal(t, string(response), expectedResponse)
 
-	mobileNumber := "09001110011"
-	token, err := utils.GenerateAccessToken(1, mobileNumber, entity.UserRole)
+	mobileNumber := "+989001110011"
+	token, err := utils.GenerateAccessToken(app.TestSecretKey, 1, mobileNumber, entity.UserRole)
 	assert.NoError(t, err)
 
 	req, _ = http.NewRequest("GET", "/", nil)
@@ -79,4 +80,15 @@ func TestAuthentication(t *testing.T) {
 	expectedResponse = fmt.Sprintf(`{"id":%v,"mobile_number":"%v"}`, user.ID, user.MobileNumber)
 	assert.Equal(t, w.Code, http.StatusOK)
 	assert.Equal(t, expectedResponse, string(response))
+
+	staleToken, err := utils.GenerateAccessToken(app.TestSecretKey, user.ID, "+989001110012", entity.UserRole)
+	assert.NoError(t, err)
+	req, _ = http.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", staleToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	response, _ = io.ReadAll(w.Body)
+	expectedResponse = `{"message":"invalid token"}`
+	assert.Equal(t, w.Code, http.StatusUnauthorized)
+	assert.Equal(t, expectedResponse, string(response))
 }
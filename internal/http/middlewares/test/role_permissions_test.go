@@ -1,13 +1,14 @@
 package middlewares_test
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
-	"github.com/TheAmirhosssein/room-reservation-api/internal/http/middlewares"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
 	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
@@ -15,23 +16,27 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestSupportOrAdminRoleMiddleware(t *testing.T) {
+func TestRequirePermissionMiddleware(t *testing.T) {
 	database.InitiateTestDB()
-	db := database.GetDb()
+	db := database.TestDb()
 
+	m := app.TestContainer().Middleware()
 	server := gin.Default()
-	server.GET("/", middlewares.AuthenticateMiddleware, middlewares.SupportOrAdminMiddleware, func(ctx *gin.Context) {
+	server.GET("/", m.AuthenticateMiddleware, m.RequirePermission(entity.UsersReadPermission), func(ctx *gin.Context) {
 		mobileNumber := ctx.GetString("mobileNumber")
 		userId := ctx.GetUint("userId")
 		ctx.JSON(http.StatusOK, gin.H{"id": userId, "mobile_number": mobileNumber})
 	})
+	server.GET("/roles", m.AuthenticateMiddleware, m.RequirePermission(entity.UsersReadPermission, entity.RolesWritePermission), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, nil)
+	})
 
 	userRepo := repository.NewUserRepository(db)
 
 	user := entity.NewUser("user", "09002520066", entity.UserRole)
 	err := userRepo.Save(&user)
 	assert.NoError(t, err)
-	userToken, err := utils.GenerateAccessToken(user.ID, user.MobileNumber, user.Role)
+	userToken, err := utils.GenerateAccessToken(app.TestSecretKey, user.ID, user.MobileNumber, user.Role)
 	assert.NoError(t, err)
 
 	req, _ := http.NewRequest("GET", "/", nil)
@@ -43,10 +48,10 @@ func TestSupportOrAdminRoleMiddleware(t *testing.T) {
 	adminUser := entity.NewUser("admin", "09002520023", entity.AdminRole)
 	err = userRepo.Save(&adminUser)
 	assert.NoError(t, err)
-	adminToken, err := utils.GenerateAccessToken(adminUser.ID, adminUser.MobileNumber, entity.AdminRole)
+	adminToken, err := utils.GenerateAccessToken(app.TestSecretKey, adminUser.ID, adminUser.MobileNumber, entity.AdminRole)
 	assert.NoError(t, err)
 
-	req, _ = http.NewRequest("GET", "/", nil)
+	req, _ = http.NewRequest("GET", "/roles", nil)
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", adminToken))
 	w = httptest.NewRecorder()
 	server.ServeHTTP(w, req)
@@ -54,10 +59,10 @@ func TestSupportOrAdminRoleMiddleware(t *testing.T) {
 
 	// support user
 
-	supportUser := entity.NewUser("support", "09002520021", entity.AdminRole)
+	supportUser := entity.NewUser("support", "09002520021", entity.SupportRole)
 	err = userRepo.Save(&supportUser)
 	assert.NoError(t, err)
-	supportToken, err := utils.GenerateAccessToken(supportUser.ID, supportUser.MobileNumber, supportUser.Role)
+	supportToken, err := utils.GenerateAccessToken(app.TestSecretKey, supportUser.ID, supportUser.MobileNumber, supportUser.Role)
 	assert.NoError(t, err)
 
 	req, _ = http.NewRequest("GET", "/", nil)
@@ -65,4 +70,28 @@ func TestSupportOrAdminRoleMiddleware(t *testing.T) {
 	w = httptest.NewRecorder()
 	server.ServeHTTP(w, req)
 	assert.Equal(t, w.Code, http.StatusOK)
+
+	req, _ = http.NewRequest("GET", "/roles", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", supportToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, w.Code, http.StatusForbidden)
+
+	// custom role
+
+	roleRepo := repository.NewRoleRepository(db)
+	auditor := entity.NewRole("Auditor", []string{entity.UsersReadPermission})
+	err = roleRepo.Save(context.TODO(), &auditor)
+	assert.NoError(t, err)
+	auditorUser := entity.NewUser("auditor", "09002520024", auditor.Name)
+	err = userRepo.Save(&auditorUser)
+	assert.NoError(t, err)
+	auditorToken, err := utils.GenerateAccessToken(app.TestSecretKey, auditorUser.ID, auditorUser.MobileNumber, auditorUser.Role)
+	assert.NoError(t, err)
+
+	req, _ = http.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", auditorToken))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	assert.Equal(t, w.Code, http.StatusOK)
 }
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+)
+
+type (
+	ConfirmAccountDeletion struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	AccountDeletionResponse struct {
+		Message      string    `json:"message"`
+		ScheduledFor time.Time `json:"scheduled_for"`
+	}
+
+	TwoFactorExport struct {
+		Enabled     bool       `json:"enabled"`
+		ConfirmedAt *time.Time `json:"confirmed_at"`
+	}
+
+	// AccountExportResponse leaves out secrets such as the two-factor seed
+	// and API key hashes.
+	AccountExportResponse struct {
+		ExportedAt     time.Time               `json:"exported_at"`
+		User           UserResponse            `json:"user"`
+		LoginEvents    []LoginEventResponse    `json:"login_events"`
+		Suspensions    []SuspensionResponse    `json:"suspensions"`
+		APIKeys        []APIKeyResponse        `json:"api_keys"`
+		TwoFactor      TwoFactorExport         `json:"two_factor"`
+		Impersonations []ImpersonationResponse `json:"impersonations"`
+		AuditLogs      []AuditLogResponse      `json:"audit_logs"`
+	}
+
+	ExportFile struct {
+		Name    string
+		Content any
+	}
+)
+
+func NewAccountExportResponse(export entity.AccountExport, exportedAt time.Time) AccountExportResponse {
+	response := AccountExportResponse{
+		ExportedAt:     exportedAt,
+		User:           NewUserResponse(export.User),
+		LoginEvents:    NewLoginEventListResponse(export.LoginEvents),
+		Suspensions:    NewSuspensionListResponse(export.Suspensions),
+		APIKeys:        []APIKeyResponse{},
+		Impersonations: []ImpersonationResponse{},
+		AuditLogs:      NewAuditLogListResponse(export.AuditLogs),
+	}
+	if export.TwoFactor != nil {
+		response.TwoFactor = TwoFactorExport{Enabled: export.TwoFactor.IsConfirmed(), ConfirmedAt: export.TwoFactor.ConfirmedAt}
+	}
+	response.APIKeys = append(response.APIKeys, NewAPIKeyListResponse(export.APIKeys)...)
+	for _, impersonation := range export.Impersonations {
+		response.Impersonations = append(response.Impersonations, NewImpersonationResponse(impersonation))
+	}
+	return response
+}
+
+// Files splits the export into the files of the downloadable archive.
+func (export AccountExportResponse) Files() []ExportFile {
+	return []ExportFile{
+		{"user.json", export.User},
+		{"login_events.json", export.LoginEvents},
+		{"suspensions.json", export.Suspensions},
+		{"api_keys.json", export.APIKeys},
+		{"two_factor.json", export.TwoFactor},
+		{"impersonations.json", export.Impersonations},
+		{"audit_logs.json", export.AuditLogs},
+	}
+}
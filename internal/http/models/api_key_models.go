@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+)
+
+type (
+	IssueAPIKey struct {
+		Name         string     `json:"name" binding:"required"`
+		UserId       *uint      `json:"user_id"`
+		Organization string     `json:"organization"`
+		Scopes       []string   `json:"scopes" binding:"required"`
+		RateLimit    int        `json:"rate_limit"`
+		ExpiresAt    *time.Time `json:"expires_at"`
+	}
+	APIKeyResponse struct {
+		Id           uint       `json:"id"`
+		Name         string     `json:"name"`
+		Prefix       string     `json:"prefix"`
+		UserId       *uint      `json:"user_id"`
+		Organization string     `json:"organization"`
+		Scopes       []string   `json:"scopes"`
+		RateLimit    int        `json:"rate_limit"`
+		CreatedAt    time.Time  `json:"created_at"`
+		ExpiresAt    *time.Time `json:"expires_at"`
+		LastUsedAt   *time.Time `json:"last_used_at"`
+		RevokedAt    *time.Time `json:"revoked_at"`
+	}
+	IssuedAPIKeyResponse struct {
+		APIKey APIKeyResponse `json:"api_key"`
+		Key    string         `json:"key"`
+	}
+)
+
+func NewAPIKeyResponse(apiKey entity.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		Id:           apiKey.ID,
+		Name:         apiKey.Name,
+		Prefix:       apiKey.Prefix,
+		UserId:       apiKey.UserID,
+		Organization: apiKey.Organization,
+		Scopes:       apiKey.ScopeNames(),
+		RateLimit:    apiKey.RateLimit,
+		CreatedAt:    apiKey.CreatedAt,
+		ExpiresAt:    apiKey.ExpiresAt,
+		LastUsedAt:   apiKey.LastUsedAt,
+		RevokedAt:    apiKey.RevokedAt,
+	}
+}
+
+func NewAPIKeyListResponse(apiKeys []entity.APIKey) []APIKeyResponse {
+	var finalResponse []APIKeyResponse
+	for _, apiKey := range apiKeys {
+		finalResponse = append(finalResponse, NewAPIKeyResponse(apiKey))
+	}
+	return finalResponse
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+)
+
+type AuditLogResponse struct {
+	Id         uint            `json:"id"`
+	ActorId    uint            `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetId   uint            `json:"target_id"`
+	Details    string          `json:"details"`
+	Changes    json.RawMessage `json:"changes" swaggertype:"object"`
+	IP         string          `json:"ip"`
+	RequestId  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+func NewAuditLogResponse(auditLog entity.AuditLog) AuditLogResponse {
+	changes := json.RawMessage("{}")
+	if auditLog.Changes != "" {
+		changes = json.RawMessage(auditLog.Changes)
+	}
+	return AuditLogResponse{
+		Id:         auditLog.ID,
+		ActorId:    auditLog.ActorID,
+		Action:     auditLog.Action,
+		TargetType: auditLog.TargetType,
+		TargetId:   auditLog.TargetID,
+		Details:    auditLog.Details,
+		Changes:    changes,
+		IP:         auditLog.IP,
+		RequestId:  auditLog.RequestID,
+		CreatedAt:  auditLog.CreatedAt,
+	}
+}
+
+func NewAuditLogListResponse(auditLogs []entity.AuditLog) []AuditLogResponse {
+	finalResponse := []AuditLogResponse{}
+	for _, auditLog := range auditLogs {
+		finalResponse = append(finalResponse, NewAuditLogResponse(auditLog))
+	}
+	return finalResponse
+}
@@ -5,21 +5,30 @@ import "github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 type (
 	City struct {
 		Title string `json:"title" binding:"required"`
+		Coordinates
 	}
 	CityResponse struct {
-		Id         uint   `json:"id"`
-		Title      string `json:"title"`
-		StateId    uint   `json:"state_id"`
-		StateTitle string `json:"state_title"`
+		Id           uint               `json:"id"`
+		Title        string             `json:"title"`
+		StateId      uint               `json:"state_id"`
+		StateTitle   string             `json:"state_title"`
+		Code         string             `json:"code,omitempty"`
+		Path         []PathNodeResponse `json:"path"`
+		Translations map[string]string  `json:"translations,omitempty"`
+		LocationResponse
 	}
 )
 
 func NewCityResponse(city entity.City) CityResponse {
 	return CityResponse{
-		Id:         city.ID,
-		Title:      city.Title,
-		StateId:    city.StateID,
-		StateTitle: city.State.Title,
+		Id:               city.ID,
+		Title:            city.Title,
+		StateId:          city.StateID,
+		StateTitle:       city.State.Title,
+		Code:             city.Code,
+		Path:             NewPathResponse(city.Path()),
+		Translations:     city.TranslationTitles(),
+		LocationResponse: NewLocationResponse(city.Location),
 	}
 }
 
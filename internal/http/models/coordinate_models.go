@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/geo"
+)
+
+type (
+	// Coordinates are the optional location fields of state and city
+	// bodies. Latitude and longitude are given together.
+	Coordinates struct {
+		Latitude  *float64        `json:"latitude" example:"35.6892"`
+		Longitude *float64        `json:"longitude" example:"51.389"`
+		Boundary  json.RawMessage `json:"boundary,omitempty" swaggertype:"object"`
+	}
+	LocationResponse struct {
+		Latitude  *float64        `json:"latitude,omitempty"`
+		Longitude *float64        `json:"longitude,omitempty"`
+		Boundary  json.RawMessage `json:"boundary,omitempty" swaggertype:"object"`
+	}
+	NearbyCityResponse struct {
+		CityResponse
+		Distance float64 `json:"distance_km"`
+	}
+)
+
+// Location validates the coordinates and returns them as a location with a
+// compact boundary.
+func (coordinates Coordinates) Location() (entity.Location, error) {
+	location := entity.Location{Latitude: coordinates.Latitude, Longitude: coordinates.Longitude}
+	if len(coordinates.Boundary) > 0 && string(coordinates.Boundary) != "null" {
+		boundary, err := geo.NormalizeBoundary(coordinates.Boundary)
+		if err != nil {
+			return entity.Location{}, err
+		}
+		location.Boundary = boundary
+	}
+	return location, location.Validate()
+}
+
+// LocationUpdates returns the columns an update sets for the fields of the
+// body that are given, leaving the others as they are.
+func (coordinates Coordinates) LocationUpdates() (map[string]any, error) {
+	location, err := coordinates.Location()
+	if err != nil {
+		return nil, err
+	}
+	updates := map[string]any{}
+	if location.HasCoordinates() {
+		updates["latitude"] = *location.Latitude
+		updates["longitude"] = *location.Longitude
+	}
+	if location.Boundary != "" {
+		updates["boundary"] = location.Boundary
+	}
+	return updates, nil
+}
+
+func NewLocationResponse(location entity.Location) LocationResponse {
+	response := LocationResponse{Latitude: location.Latitude, Longitude: location.Longitude}
+	if location.Boundary != "" {
+		response.Boundary = json.RawMessage(location.Boundary)
+	}
+	return response
+}
+
+func NewNearbyCityListResponse(cities []entity.NearbyCity) []NearbyCityResponse {
+	finalResponse := []NearbyCityResponse{}
+	for _, city := range cities {
+		finalResponse = append(finalResponse, NearbyCityResponse{CityResponse: NewCityResponse(city.City), Distance: city.Distance})
+	}
+	return finalResponse
+}
@@ -0,0 +1,41 @@
+package models
+
+import "github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+
+// DatabaseDiagnosticsResponse reports durations in milliseconds.
+type DatabaseDiagnosticsResponse struct {
+	PingLatencyMs      int64 `json:"ping_latency_ms"`
+	MaxOpenConnections int   `json:"max_open_connections"`
+	MaxIdleConnections int   `json:"max_idle_connections"`
+	ConnMaxLifetimeMs  int64 `json:"conn_max_lifetime_ms"`
+	ConnMaxIdleTimeMs  int64 `json:"conn_max_idle_time_ms"`
+	StatementTimeoutMs int64 `json:"statement_timeout_ms"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
+
+func NewDatabaseDiagnosticsResponse(diagnostics entity.DatabaseDiagnostics) DatabaseDiagnosticsResponse {
+	stats := diagnostics.Stats
+	return DatabaseDiagnosticsResponse{
+		PingLatencyMs:      diagnostics.PingLatency.Milliseconds(),
+		MaxOpenConnections: stats.MaxOpenConnections,
+		MaxIdleConnections: diagnostics.MaxIdleConns,
+		ConnMaxLifetimeMs:  diagnostics.ConnMaxLifetime.Milliseconds(),
+		ConnMaxIdleTimeMs:  diagnostics.ConnMaxIdleTime.Milliseconds(),
+		StatementTimeoutMs: diagnostics.StatementTimeout.Milliseconds(),
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+}
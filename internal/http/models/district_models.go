@@ -0,0 +1,71 @@
+package models
+
+import "github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+
+type (
+	District struct {
+		Title string `json:"title" binding:"required"`
+	}
+	DistrictResponse struct {
+		Id     uint               `json:"id"`
+		Title  string             `json:"title"`
+		CityId uint               `json:"city_id"`
+		Path   []PathNodeResponse `json:"path"`
+	}
+	Neighborhood struct {
+		Title string `json:"title" binding:"required"`
+	}
+	NeighborhoodResponse struct {
+		Id         uint               `json:"id"`
+		Title      string             `json:"title"`
+		DistrictId uint               `json:"district_id"`
+		Path       []PathNodeResponse `json:"path"`
+	}
+	PathNodeResponse struct {
+		Type  string `json:"type" example:"state"`
+		Id    uint   `json:"id"`
+		Title string `json:"title"`
+	}
+)
+
+func NewPathResponse(path []entity.PathNode) []PathNodeResponse {
+	response := make([]PathNodeResponse, 0, len(path))
+	for _, node := range path {
+		response = append(response, PathNodeResponse{Type: node.Type, Id: node.ID, Title: node.Title})
+	}
+	return response
+}
+
+func NewDistrictResponse(district entity.District) DistrictResponse {
+	return DistrictResponse{
+		Id:     district.ID,
+		Title:  district.Title,
+		CityId: district.CityID,
+		Path:   NewPathResponse(district.Path()),
+	}
+}
+
+func NewDistrictListResponse(districts []entity.District) []DistrictResponse {
+	finalResponse := []DistrictResponse{}
+	for _, district := range districts {
+		finalResponse = append(finalResponse, NewDistrictResponse(district))
+	}
+	return finalResponse
+}
+
+func NewNeighborhoodResponse(neighborhood entity.Neighborhood) NeighborhoodResponse {
+	return NeighborhoodResponse{
+		Id:         neighborhood.ID,
+		Title:      neighborhood.Title,
+		DistrictId: neighborhood.DistrictID,
+		Path:       NewPathResponse(neighborhood.Path()),
+	}
+}
+
+func NewNeighborhoodListResponse(neighborhoods []entity.Neighborhood) []NeighborhoodResponse {
+	finalResponse := []NeighborhoodResponse{}
+	for _, neighborhood := range neighborhoods {
+		finalResponse = append(finalResponse, NewNeighborhoodResponse(neighborhood))
+	}
+	return finalResponse
+}
@@ -0,0 +1,79 @@
+package models
+
+import "github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+
+type (
+	GeoDiffResponse struct {
+		Version       string                `json:"version"`
+		InSync        bool                  `json:"in_sync"`
+		MatchedStates int                   `json:"matched_states"`
+		MatchedCities int                   `json:"matched_cities"`
+		MissingStates []DatasetEntry        `json:"missing_states"`
+		ChangedStates []GeoChangeResponse   `json:"changed_states"`
+		UnknownStates []StateResponse       `json:"unknown_states"`
+		MissingCities []DatasetEntry        `json:"missing_cities"`
+		ChangedCities []GeoChangeResponse   `json:"changed_cities"`
+		UnknownCities []UnknownCityResponse `json:"unknown_cities"`
+	}
+	DatasetEntry struct {
+		Code         string `json:"code"`
+		Title        string `json:"title"`
+		EnglishTitle string `json:"english_title"`
+		StateCode    string `json:"state_code,omitempty"`
+	}
+	GeoChangeResponse struct {
+		Id           uint   `json:"id"`
+		Code         string `json:"code"`
+		Title        string `json:"title"`
+		DatasetTitle string `json:"dataset_title"`
+		Unlinked     bool   `json:"unlinked"`
+	}
+	UnknownCityResponse struct {
+		Id      uint   `json:"id"`
+		Title   string `json:"title"`
+		StateId uint   `json:"state_id"`
+	}
+)
+
+func NewGeoDiffResponse(diff entity.GeoDiff) GeoDiffResponse {
+	response := GeoDiffResponse{
+		Version:       diff.Version,
+		InSync:        diff.InSync(),
+		MatchedStates: diff.MatchedStates,
+		MatchedCities: diff.MatchedCities,
+		MissingStates: []DatasetEntry{},
+		ChangedStates: newGeoChangeResponses(diff.ChangedStates),
+		UnknownStates: []StateResponse{},
+		MissingCities: []DatasetEntry{},
+		ChangedCities: newGeoChangeResponses(diff.ChangedCities),
+		UnknownCities: []UnknownCityResponse{},
+	}
+	for _, state := range diff.MissingStates {
+		response.MissingStates = append(response.MissingStates, DatasetEntry{Code: state.Code, Title: state.Title, EnglishTitle: state.EnglishTitle})
+	}
+	for _, state := range diff.UnknownStates {
+		response.UnknownStates = append(response.UnknownStates, NewStateResponse(state))
+	}
+	for _, missing := range diff.MissingCities {
+		city := missing.City
+		response.MissingCities = append(response.MissingCities, DatasetEntry{Code: city.Code, Title: city.Title, EnglishTitle: city.EnglishTitle, StateCode: missing.StateCode})
+	}
+	for _, city := range diff.UnknownCities {
+		response.UnknownCities = append(response.UnknownCities, UnknownCityResponse{Id: city.ID, Title: city.Title, StateId: city.StateID})
+	}
+	return response
+}
+
+func newGeoChangeResponses(changes []entity.GeoChange) []GeoChangeResponse {
+	responses := []GeoChangeResponse{}
+	for _, change := range changes {
+		responses = append(responses, GeoChangeResponse{
+			Id:           change.ID,
+			Code:         change.Code,
+			Title:        change.Title,
+			DatasetTitle: change.DatasetTitle,
+			Unlinked:     change.Unlinked,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+)
+
+type (
+	StartImpersonation struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+
+	ImpersonationResponse struct {
+		Id        uint       `json:"id"`
+		ActorId   uint       `json:"actor_id"`
+		SubjectId uint       `json:"subject_id"`
+		Reason    string     `json:"reason"`
+		StartedAt time.Time  `json:"started_at"`
+		ExpiresAt time.Time  `json:"expires_at"`
+		EndedAt   *time.Time `json:"ended_at"`
+	}
+
+	ImpersonationTokenResponse struct {
+		Token         string                `json:"token"`
+		Impersonation ImpersonationResponse `json:"impersonation"`
+	}
+)
+
+func NewImpersonationResponse(impersonation entity.Impersonation) ImpersonationResponse {
+	return ImpersonationResponse{
+		Id:        impersonation.ID,
+		ActorId:   impersonation.ActorID,
+		SubjectId: impersonation.SubjectID,
+		Reason:    impersonation.Reason,
+		StartedAt: impersonation.CreatedAt,
+		ExpiresAt: impersonation.ExpiresAt,
+		EndedAt:   impersonation.EndedAt,
+	}
+}
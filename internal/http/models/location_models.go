@@ -0,0 +1,197 @@
+package models
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+)
+
+// LocationCSVHeader names the columns of location CSV files. Imports may
+// order them freely and leave out the codes.
+var LocationCSVHeader = []string{"state_code", "state_title", "city_code", "city_title"}
+
+var ErrInvalidLocationFile = errors.New("invalid file")
+
+type (
+	LocationState struct {
+		Code   string         `json:"code"`
+		Title  string         `json:"title"`
+		Cities []LocationCity `json:"cities"`
+	}
+	LocationCity struct {
+		Code  string `json:"code"`
+		Title string `json:"title"`
+	}
+	ImportErrorResponse struct {
+		Location string `json:"location"`
+		Field    string `json:"field"`
+		Message  string `json:"message"`
+	}
+	ImportResultResponse struct {
+		DryRun        bool                  `json:"dry_run"`
+		Rows          int                   `json:"rows"`
+		CreatedStates int                   `json:"created_states"`
+		UpdatedStates int                   `json:"updated_states"`
+		CreatedCities int                   `json:"created_cities"`
+		UpdatedCities int                   `json:"updated_cities"`
+		Errors        []ImportErrorResponse `json:"errors"`
+	}
+	LocationTreeResponse struct {
+		Id     uint               `json:"id"`
+		Title  string             `json:"title"`
+		Code   string             `json:"code,omitempty"`
+		Cities []CityTreeResponse `json:"cities"`
+	}
+	CityTreeResponse struct {
+		Id        uint                   `json:"id"`
+		Title     string                 `json:"title"`
+		Code      string                 `json:"code,omitempty"`
+		Districts []DistrictTreeResponse `json:"districts"`
+	}
+	DistrictTreeResponse struct {
+		Id            uint                       `json:"id"`
+		Title         string                     `json:"title"`
+		Neighborhoods []NeighborhoodTreeResponse `json:"neighborhoods"`
+	}
+	NeighborhoodTreeResponse struct {
+		Id    uint   `json:"id"`
+		Title string `json:"title"`
+	}
+)
+
+func NewLocationState(state entity.State, cities []entity.City) LocationState {
+	response := LocationState{Code: state.Code, Title: state.Title, Cities: []LocationCity{}}
+	for _, city := range cities {
+		response.Cities = append(response.Cities, LocationCity{Code: city.Code, Title: city.Title})
+	}
+	return response
+}
+
+// LocationCSVRecords lays out a state as CSV records, one per city. A state
+// without cities still gets a record.
+func LocationCSVRecords(state entity.State, cities []entity.City) [][]string {
+	if len(cities) == 0 {
+		return [][]string{{state.Code, state.Title, "", ""}}
+	}
+	records := make([][]string, 0, len(cities))
+	for _, city := range cities {
+		records = append(records, []string{state.Code, state.Title, city.Code, city.Title})
+	}
+	return records
+}
+
+func NewImportResultResponse(result entity.ImportResult) ImportResultResponse {
+	response := ImportResultResponse{
+		DryRun:        result.DryRun,
+		Rows:          result.Rows,
+		CreatedStates: result.CreatedStates,
+		UpdatedStates: result.UpdatedStates,
+		CreatedCities: result.CreatedCities,
+		UpdatedCities: result.UpdatedCities,
+		Errors:        []ImportErrorResponse{},
+	}
+	for _, err := range result.Errors {
+		response.Errors = append(response.Errors, ImportErrorResponse{Location: err.Location, Field: err.Field, Message: err.Message})
+	}
+	return response
+}
+
+// ParseLocationsCSV reads a CSV file whose first record is a header naming
+// the columns of LocationCSVHeader. Rows are located by their line.
+func ParseLocationsCSV(reader io.Reader) ([]entity.LocationRow, error) {
+	csvReader := csv.NewReader(reader)
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLocationFile, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		known := false
+		for _, column := range LocationCSVHeader {
+			known = known || name == column
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidLocationFile, name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["state_title"]; !ok {
+		return nil, fmt.Errorf("%w: the state_title column is required", ErrInvalidLocationFile)
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	rows := []entity.LocationRow{}
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidLocationFile, err)
+		}
+		line, _ := csvReader.FieldPos(0)
+		rows = append(rows, entity.LocationRow{
+			Location:   fmt.Sprintf("line %v", line),
+			StateCode:  field(record, "state_code"),
+			StateTitle: field(record, "state_title"),
+			CityCode:   field(record, "city_code"),
+			CityTitle:  field(record, "city_title"),
+		})
+	}
+}
+
+// ParseLocationsJSON reads a JSON array of LocationState, the format the
+// export writes. Rows are located by their path in the array.
+func ParseLocationsJSON(reader io.Reader) ([]entity.LocationRow, error) {
+	states := []LocationState{}
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&states); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLocationFile, err)
+	}
+	rows := []entity.LocationRow{}
+	for i, state := range states {
+		row := entity.LocationRow{
+			Location:   fmt.Sprintf("[%v]", i),
+			StateCode:  strings.TrimSpace(state.Code),
+			StateTitle: strings.TrimSpace(state.Title),
+		}
+		if len(state.Cities) == 0 {
+			rows = append(rows, row)
+		}
+		for j, city := range state.Cities {
+			cityRow := row
+			cityRow.Location = fmt.Sprintf("[%v].cities[%v]", i, j)
+			cityRow.CityCode = strings.TrimSpace(city.Code)
+			cityRow.CityTitle = strings.TrimSpace(city.Title)
+			rows = append(rows, cityRow)
+		}
+	}
+	return rows, nil
+}
+
+func NewLocationTreeResponse(tree entity.LocationTree) LocationTreeResponse {
+	response := LocationTreeResponse{Id: tree.State.ID, Title: tree.State.Title, Code: tree.State.Code, Cities: []CityTreeResponse{}}
+	for _, city := range tree.Cities {
+		cityResponse := CityTreeResponse{Id: city.City.ID, Title: city.City.Title, Code: city.City.Code, Districts: []DistrictTreeResponse{}}
+		for _, district := range city.Districts {
+			districtResponse := DistrictTreeResponse{Id: district.District.ID, Title: district.District.Title, Neighborhoods: []NeighborhoodTreeResponse{}}
+			for _, neighborhood := range district.Neighborhoods {
+				districtResponse.Neighborhoods = append(districtResponse.Neighborhoods, NeighborhoodTreeResponse{Id: neighborhood.ID, Title: neighborhood.Title})
+			}
+			cityResponse.Districts = append(cityResponse.Districts, districtResponse)
+		}
+		response.Cities = append(response.Cities, cityResponse)
+	}
+	return response
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+)
+
+type (
+	ReviewLogin struct {
+		Note string `json:"note" binding:"required"`
+	}
+
+	LoginEventResponse struct {
+		Id        uint      `json:"id"`
+		Type      string    `json:"type"`
+		IP        string    `json:"ip"`
+		UserAgent string    `json:"user_agent"`
+		Country   string    `json:"country"`
+		Anomalies []string  `json:"anomalies"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	// StaffLoginEventResponse adds the review state, which only support and
+	// admins get to see.
+	StaffLoginEventResponse struct {
+		LoginEventResponse
+		UserId       uint       `json:"user_id"`
+		MobileNumber string     `json:"mobile_number"`
+		ReviewedAt   *time.Time `json:"reviewed_at"`
+		ReviewedBy   *uint      `json:"reviewed_by"`
+		ReviewNote   string     `json:"review_note"`
+	}
+)
+
+func NewLoginEventResponse(event entity.LoginEvent) LoginEventResponse {
+	return LoginEventResponse{
+		Id:        event.ID,
+		Type:      event.Type,
+		IP:        event.IP,
+		UserAgent: event.UserAgent,
+		Country:   event.Country,
+		Anomalies: event.AnomalyList(),
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+func NewLoginEventListResponse(events []entity.LoginEvent) []LoginEventResponse {
+	finalResponse := []LoginEventResponse{}
+	for _, event := range events {
+		finalResponse = append(finalResponse, NewLoginEventResponse(event))
+	}
+	return finalResponse
+}
+
+func NewStaffLoginEventResponse(event entity.LoginEvent) StaffLoginEventResponse {
+	return StaffLoginEventResponse{
+		LoginEventResponse: NewLoginEventResponse(event),
+		UserId:             event.UserID,
+		MobileNumber:       event.MobileNumber,
+		ReviewedAt:         event.ReviewedAt,
+		ReviewedBy:         event.ReviewedBy,
+		ReviewNote:         event.ReviewNote,
+	}
+}
+
+func NewStaffLoginEventListResponse(events []entity.LoginEvent) []StaffLoginEventResponse {
+	finalResponse := []StaffLoginEventResponse{}
+	for _, event := range events {
+		finalResponse = append(finalResponse, NewStaffLoginEventResponse(event))
+	}
+	return finalResponse
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+)
+
+type (
+	OAuthAuthorize struct {
+		ClientId            string `json:"client_id" binding:"required"`
+		RedirectURI         string `json:"redirect_uri" binding:"required"`
+		Scope               string `json:"scope"`
+		State               string `json:"state"`
+		CodeChallenge       string `json:"code_challenge" binding:"required"`
+		CodeChallengeMethod string `json:"code_challenge_method" binding:"required"`
+		MobileNumber        string `json:"mobile_number" binding:"required"`
+		Code                string `json:"code" binding:"required"`
+		TOTPCode            string `json:"totp_code"`
+		RecoveryCode        string `json:"recovery_code"`
+	}
+	OAuthAuthorizeInfo struct {
+		ClientName  string `json:"client_name"`
+		RedirectURI string `json:"redirect_uri"`
+		Scope       string `json:"scope"`
+	}
+	OAuthAuthorizeResponse struct {
+		RedirectURI string `json:"redirect_uri"`
+	}
+	OAuthTokenRequest struct {
+		GrantType    string `form:"grant_type" binding:"required"`
+		Code         string `form:"code"`
+		RedirectURI  string `form:"redirect_uri"`
+		CodeVerifier string `form:"code_verifier"`
+		RefreshToken string `form:"refresh_token"`
+		Scope        string `form:"scope"`
+		ClientId     string `form:"client_id"`
+		ClientSecret string `form:"client_secret"`
+	}
+	OAuthTokenResponse struct {
+		AccessToken  string `json:"access_token"`
+		TokenType    string `json:"token_type"`
+		ExpiresIn    int    `json:"expires_in"`
+		RefreshToken string `json:"refresh_token,omitempty"`
+		Scope        string `json:"scope"`
+	}
+	OAuthTokenActionRequest struct {
+		Token         string `form:"token" binding:"required"`
+		TokenTypeHint string `form:"token_type_hint"`
+		ClientId      string `form:"client_id"`
+		ClientSecret  string `form:"client_secret"`
+	}
+	OAuthIntrospectionResponse struct {
+		Active    bool   `json:"active"`
+		TokenType string `json:"token_type,omitempty"`
+		ClientId  string `json:"client_id,omitempty"`
+		UserId    uint   `json:"user_id,omitempty"`
+		Scope     string `json:"scope,omitempty"`
+		Exp       int64  `json:"exp,omitempty"`
+	}
+	OAuthErrorResponse struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	RegisterOAuthClient struct {
+		Name         string   `json:"name" binding:"required"`
+		RedirectURIs []string `json:"redirect_uris"`
+		Scopes       []string `json:"scopes" binding:"required"`
+		Confidential bool     `json:"confidential"`
+	}
+	OAuthClientResponse struct {
+		Id           uint      `json:"id"`
+		Name         string    `json:"name"`
+		ClientId     string    `json:"client_id"`
+		Confidential bool      `json:"confidential"`
+		RedirectURIs []string  `json:"redirect_uris"`
+		Scopes       []string  `json:"scopes"`
+		CreatedAt    time.Time `json:"created_at"`
+	}
+	RegisteredOAuthClientResponse struct {
+		Client       OAuthClientResponse `json:"client"`
+		ClientSecret string              `json:"client_secret,omitempty"`
+	}
+)
+
+func NewOAuthClientResponse(client entity.OAuthClient) OAuthClientResponse {
+	return OAuthClientResponse{
+		Id:           client.ID,
+		Name:         client.Name,
+		ClientId:     client.ClientID,
+		Confidential: client.IsConfidential(),
+		RedirectURIs: client.RedirectURIList(),
+		Scopes:       client.ScopeList(),
+		CreatedAt:    client.CreatedAt,
+	}
+}
+
+func NewOAuthClientListResponse(clients []entity.OAuthClient) []OAuthClientResponse {
+	var finalResponse []OAuthClientResponse
+	for _, client := range clients {
+		finalResponse = append(finalResponse, NewOAuthClientResponse(client))
+	}
+	return finalResponse
+}
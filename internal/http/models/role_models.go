@@ -0,0 +1,40 @@
+package models
+
+import "github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+
+type (
+	Role struct {
+		Name        string   `json:"name" binding:"required"`
+		Permissions []string `json:"permissions"`
+	}
+	RolePermissions struct {
+		Permissions []string `json:"permissions" binding:"required"`
+	}
+	AssignRole struct {
+		Role    string `json:"role" binding:"required"`
+		Confirm bool   `json:"confirm"`
+	}
+	RoleResponse struct {
+		Id               uint     `json:"id"`
+		Name             string   `json:"name"`
+		Permissions      []string `json:"permissions"`
+		RequireTwoFactor bool     `json:"require_two_factor"`
+	}
+)
+
+func NewRoleResponse(role entity.Role) RoleResponse {
+	return RoleResponse{
+		Id:               role.ID,
+		Name:             role.Name,
+		Permissions:      role.PermissionNames(),
+		RequireTwoFactor: role.RequiresTwoFactor(),
+	}
+}
+
+func NewRoleListResponse(roles []entity.Role) []RoleResponse {
+	var finalResponse []RoleResponse
+	for _, role := range roles {
+		finalResponse = append(finalResponse, NewRoleResponse(role))
+	}
+	return finalResponse
+}
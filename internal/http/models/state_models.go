@@ -5,17 +5,29 @@ import "github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 type (
 	State struct {
 		Title string `json:"title" binding:"required"`
+		Coordinates
 	}
 	StateResponse struct {
-		Id    uint   `json:"id"`
-		Title string `json:"title"`
+		Id           uint              `json:"id"`
+		Title        string            `json:"title"`
+		Code         string            `json:"code,omitempty"`
+		Translations map[string]string `json:"translations,omitempty"`
+		LocationResponse
+	}
+	// Translations holds titles keyed by locale. Locales left out have their
+	// translation removed.
+	Translations struct {
+		Translations map[string]string `json:"translations" binding:"required"`
 	}
 )
 
 func NewStateResponse(state entity.State) StateResponse {
 	return StateResponse{
-		Id:    state.ID,
-		Title: state.Title,
+		Id:               state.ID,
+		Title:            state.Title,
+		Code:             state.Code,
+		Translations:     state.TranslationTitles(),
+		LocationResponse: NewLocationResponse(state.Location),
 	}
 }
 
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+)
+
+type (
+	SuspendUser struct {
+		Reason string     `json:"reason" binding:"required"`
+		Notes  string     `json:"notes"`
+		EndsAt *time.Time `json:"ends_at"`
+	}
+
+	UnsuspendUser struct {
+		Notes string `json:"notes"`
+	}
+)
+
+type SuspensionResponse struct {
+	Id        uint       `json:"id"`
+	ActorId   uint       `json:"actor_id"`
+	Reason    string     `json:"reason"`
+	Notes     string     `json:"notes"`
+	CreatedAt time.Time  `json:"created_at"`
+	EndsAt    *time.Time `json:"ends_at"`
+	LiftedAt  *time.Time `json:"lifted_at"`
+	LiftedBy  *uint      `json:"lifted_by"`
+	LiftNotes string     `json:"lift_notes"`
+}
+
+// SuspendedAccountResponse is what a suspended user gets back; internal
+// notes are left out on purpose.
+type SuspendedAccountResponse struct {
+	Message        string     `json:"message"`
+	Reason         string     `json:"reason"`
+	SuspendedUntil *time.Time `json:"suspended_until"`
+}
+
+func NewSuspensionResponse(suspension entity.Suspension) SuspensionResponse {
+	return SuspensionResponse{
+		Id:        suspension.ID,
+		ActorId:   suspension.ActorID,
+		Reason:    suspension.Reason,
+		Notes:     suspension.Notes,
+		CreatedAt: suspension.CreatedAt,
+		EndsAt:    suspension.EndsAt,
+		LiftedAt:  suspension.LiftedAt,
+		LiftedBy:  suspension.LiftedBy,
+		LiftNotes: suspension.LiftNotes,
+	}
+}
+
+func NewSuspensionListResponse(suspensions []entity.Suspension) []SuspensionResponse {
+	finalResponse := []SuspensionResponse{}
+	for _, suspension := range suspensions {
+		finalResponse = append(finalResponse, NewSuspensionResponse(suspension))
+	}
+	return finalResponse
+}
+
+func NewSuspendedAccountResponse(user entity.User) SuspendedAccountResponse {
+	return SuspendedAccountResponse{
+		Message:        "account suspended",
+		Reason:         user.SuspensionReason,
+		SuspendedUntil: user.SuspendedUntil,
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+type (
+	ConfirmTwoFactor struct {
+		Code string `json:"code" binding:"required"`
+	}
+	ResetTwoFactor struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	RoleTwoFactor struct {
+		Required *bool `json:"required" binding:"required"`
+	}
+	TwoFactorEnrollmentResponse struct {
+		Secret          string   `json:"secret"`
+		ProvisioningURI string   `json:"provisioning_uri"`
+		RecoveryCodes   []string `json:"recovery_codes"`
+	}
+)
@@ -14,6 +14,8 @@ type (
 	Token struct {
 		MobileNumber string `json:"mobile_number" binding:"required"`
 		Code         string `json:"code" binding:"required"`
+		TOTPCode     string `json:"totp_code"`
+		RecoveryCode string `json:"recovery_code"`
 	}
 
 	UpdateUser struct {
@@ -23,25 +25,57 @@ type (
 	AdminUpdateUser struct {
 		FullName string `json:"full_name" binding:"required"`
 		Role     string `json:"role" binding:"required"`
+		Confirm  bool   `json:"confirm"`
+	}
+
+	ChangeMobileNumber struct {
+		NewMobileNumber string `json:"new_mobile_number" binding:"required"`
+	}
+
+	ConfirmMobileNumberChange struct {
+		NewMobileNumber string `json:"new_mobile_number" binding:"required"`
+		OldCode         string `json:"old_code" binding:"required"`
+		NewCode         string `json:"new_code" binding:"required"`
+	}
+
+	AdminChangeMobileNumber struct {
+		MobileNumber string `json:"mobile_number" binding:"required"`
+		Reason       string `json:"reason" binding:"required"`
 	}
 )
 
 type UserResponse struct {
-	Id           uint      `json:"id"`
-	MobileNumber string    `json:"mobile_number"`
-	FullName     string    `json:"full_name"`
-	JoinedAt     time.Time `json:"joined_at"`
-	Role         string    `json:"role"`
+	Id               uint       `json:"id"`
+	MobileNumber     string     `json:"mobile_number"`
+	FullName         string     `json:"full_name"`
+	Country          string     `json:"country"`
+	JoinedAt         time.Time  `json:"joined_at"`
+	Role             string     `json:"role"`
+	Status           string     `json:"status"`
+	SuspendedUntil   *time.Time `json:"suspended_until,omitempty"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+	// DeletionScheduledFor is set while the user's account deletion is pending
+	DeletionScheduledFor *time.Time `json:"deletion_scheduled_for,omitempty"`
 }
 
 func NewUserResponse(userEntity entity.User) UserResponse {
-	return UserResponse{
+	response := UserResponse{
 		Id:           userEntity.ID,
 		MobileNumber: userEntity.MobileNumber,
 		FullName:     userEntity.FullName,
+		Country:      userEntity.Country,
 		JoinedAt:     userEntity.CreatedAt,
 		Role:         userEntity.Role,
+		Status:       userEntity.Status(time.Now()),
+	}
+	if response.Status != entity.ActiveStatus {
+		response.SuspendedUntil = userEntity.SuspendedUntil
+		response.SuspensionReason = userEntity.SuspensionReason
+	}
+	if userEntity.IsDeletionPending() {
+		response.DeletionScheduledFor = userEntity.DeletionScheduledFor
 	}
+	return response
 }
 
 func NewUserListResponse(userEntityList []entity.User) []UserResponse {
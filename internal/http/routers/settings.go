@@ -1,26 +1,58 @@
 package routers
 
 import (
-	"github.com/TheAmirhosssein/room-reservation-api/internal/http/handlers"
-	"github.com/TheAmirhosssein/room-reservation-api/internal/http/middlewares"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/gin-gonic/gin"
 )
 
-func SettingsRouters(server *gin.Engine, prefix string) {
-	protectedRoutes := server.Group(prefix)
-	protectedRoutes.Use(middlewares.AuthenticateMiddleware, middlewares.SupportOrAdminMiddleware)
+func SettingsRouters(server *gin.Engine, prefix string, container *app.Container) {
+	h, m := container.Handler(), container.Middleware()
+	statesRoutes := server.Group(prefix)
+	statesRoutes.Use(m.APIKeyMiddleware, m.RequirePermission(entity.SettingsStatesWritePermission))
+
+	citiesRoutes := server.Group(prefix)
+	citiesRoutes.Use(m.APIKeyMiddleware, m.RequirePermission(entity.SettingsCitiesWritePermission))
+
+	districtsRoutes := server.Group(prefix)
+	districtsRoutes.Use(m.APIKeyMiddleware, m.RequirePermission(entity.SettingsDistrictsWritePermission))
 
 	freeRoutes := server.Group(prefix)
 
-	protectedRoutes.POST("states", handlers.CreateState)
-	freeRoutes.GET("states", handlers.StateList)
-	freeRoutes.GET("states/:id", handlers.RetrieveState)
-	protectedRoutes.PUT("states/:id", handlers.UpdateState)
-	protectedRoutes.DELETE("states/:id", handlers.DeleteState)
-
-	protectedRoutes.POST("states/:stateId/city", handlers.CreateCity)
-	freeRoutes.GET("states/:id/city", handlers.CityList)
-	freeRoutes.GET("states/:id/city/:cityId", handlers.RetrieveCity)
-	protectedRoutes.PUT("states/:id/city/:cityId", handlers.UpdateCity)
-	protectedRoutes.DELETE("states/:id/city/:cityId", handlers.DeleteCity)
+	statesRoutes.POST("states", h.CreateState)
+	freeRoutes.GET("states", h.StateList)
+	freeRoutes.GET("states/:id", h.RetrieveState)
+	freeRoutes.GET("states/:id/tree", h.LocationTree)
+	statesRoutes.PUT("states/:id", h.UpdateState)
+	statesRoutes.DELETE("states/:id", h.DeleteState)
+	statesRoutes.PUT("states/:id/translations", h.UpdateStateTranslations)
+	statesRoutes.GET("dataset/diff", h.GeoDataDiff)
+	statesRoutes.POST("locations/import", m.RequirePermission(entity.SettingsCitiesWritePermission), h.ImportLocations)
+	statesRoutes.GET("locations/export", h.ExportLocations)
+
+	citiesRoutes.POST("states/:stateId/city", h.CreateCity)
+	freeRoutes.GET("states/:id/city", h.CityList)
+	freeRoutes.GET("states/:id/city/:cityId", h.RetrieveCity)
+	citiesRoutes.PUT("states/:id/city/:cityId", h.UpdateCity)
+	citiesRoutes.DELETE("states/:id/city/:cityId", h.DeleteCity)
+	citiesRoutes.PUT("states/:id/city/:cityId/translations", h.UpdateCityTranslations)
+	freeRoutes.GET("cities/nearby", h.NearbyCities)
+
+	districtsRoutes.POST("cities/:cityId/districts", h.CreateDistrict)
+	freeRoutes.GET("cities/:cityId/districts", h.DistrictList)
+	freeRoutes.GET("cities/:cityId/districts/:districtId", h.RetrieveDistrict)
+	districtsRoutes.PUT("cities/:cityId/districts/:districtId", h.UpdateDistrict)
+	districtsRoutes.DELETE("cities/:cityId/districts/:districtId", h.DeleteDistrict)
+	districtsRoutes.POST("districts/:districtId/neighborhoods", h.CreateNeighborhood)
+	freeRoutes.GET("districts/:districtId/neighborhoods", h.NeighborhoodList)
+	freeRoutes.GET("districts/:districtId/neighborhoods/:neighborhoodId", h.RetrieveNeighborhood)
+	districtsRoutes.PUT("districts/:districtId/neighborhoods/:neighborhoodId", h.UpdateNeighborhood)
+	districtsRoutes.DELETE("districts/:districtId/neighborhoods/:neighborhoodId", h.DeleteNeighborhood)
+
+	statesRoutes.GET("trash/states", h.TrashedStates)
+	statesRoutes.POST("trash/states/:id/restore", h.RestoreState)
+	statesRoutes.DELETE("trash/states/:id", m.RequirePermission(entity.TrashPurgePermission), h.PurgeState)
+	citiesRoutes.GET("trash/states/:id/cities", h.TrashedCities)
+	citiesRoutes.POST("trash/cities/:cityId/restore", h.RestoreCity)
+	citiesRoutes.DELETE("trash/cities/:cityId", m.RequirePermission(entity.TrashPurgePermission), h.PurgeCity)
 }
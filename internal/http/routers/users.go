@@ -1,23 +1,69 @@
 package routers
 
 import (
-	"github.com/TheAmirhosssein/room-reservation-api/internal/http/handlers"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/http/middlewares"
 	"github.com/gin-gonic/gin"
 )
 
-func UserRouters(server *gin.Engine, prefix string) {
+func UserRouters(server *gin.Engine, prefix string, container *app.Container) {
+	h, m := container.Handler(), container.Middleware()
 	userRouter := server.Group(prefix)
-	userRouter.POST("authenticate", handlers.Authenticate)
-	userRouter.POST("token", handlers.Token)
-	userRouter.GET("me", middlewares.AuthenticateMiddleware, handlers.Me)
-	userRouter.PUT("me", middlewares.AuthenticateMiddleware, handlers.UpdateUser)
-	userRouter.DELETE("me", middlewares.AuthenticateMiddleware, handlers.DeleteAccount)
+	userRouter.POST("authenticate", h.Authenticate)
+	userRouter.POST("token", h.Token)
+	userRouter.GET("me", m.AuthenticateMiddleware, middlewares.RequireScope(entity.ProfileScope), h.Me)
+	userRouter.PUT("me", m.AuthenticateMiddleware, middlewares.RequireScope(entity.ProfileScope), h.UpdateUser)
+	userRouter.DELETE("me", m.AuthenticateMiddleware, middlewares.FirstPartyOnly, middlewares.ForbidImpersonation, h.DeleteAccount)
+	userRouter.POST("me/deletion/confirm", m.AuthenticateMiddleware, middlewares.FirstPartyOnly, middlewares.ForbidImpersonation, h.ConfirmAccountDeletion)
+	userRouter.GET("me/export", m.AuthenticateMiddleware, middlewares.FirstPartyOnly, middlewares.ForbidImpersonation, h.ExportAccount)
+	userRouter.POST("me/mobile", m.AuthenticateMiddleware, middlewares.FirstPartyOnly, middlewares.ForbidImpersonation, h.RequestMobileNumberChange)
+	userRouter.POST("me/mobile/confirm", m.AuthenticateMiddleware, middlewares.FirstPartyOnly, middlewares.ForbidImpersonation, h.ConfirmMobileNumberChange)
+	userRouter.POST("me/2fa", m.AuthenticateMiddleware, middlewares.FirstPartyOnly, middlewares.ForbidImpersonation, h.EnrollTwoFactor)
+	userRouter.POST("me/2fa/confirm", m.AuthenticateMiddleware, middlewares.FirstPartyOnly, middlewares.ForbidImpersonation, h.ConfirmTwoFactor)
+	userRouter.GET("me/logins", m.AuthenticateMiddleware, middlewares.FirstPartyOnly, h.MyLogins)
+	userRouter.DELETE("impersonation", m.AuthenticateMiddleware, h.EndImpersonation)
+	userRouter.GET("oauth/authorize", h.OAuthAuthorizeInfo)
+	userRouter.POST("oauth/authorize", h.OAuthAuthorize)
+	userRouter.POST("oauth/token", h.OAuthToken)
+	userRouter.POST("oauth/introspect", h.OAuthIntrospect)
+	userRouter.POST("oauth/revoke", h.OAuthRevoke)
 
 	adminUser := server.Group(prefix)
-	adminUser.Use(middlewares.AuthenticateMiddleware, middlewares.SupportOrAdminMiddleware)
-	adminUser.GET("users", handlers.AllUsers)
-	adminUser.GET("users/:id", handlers.RetrieveUser)
-	adminUser.PUT("users/:id", handlers.EditUser)
-	adminUser.DELETE("users/:id", handlers.DeleteUser)
+	adminUser.Use(m.APIKeyMiddleware)
+	adminUser.GET("users", m.RequirePermission(entity.UsersReadPermission), h.AllUsers)
+	adminUser.GET("users/:id", m.RequirePermission(entity.UsersReadPermission), h.RetrieveUser)
+	adminUser.PUT("users/:id", m.RequirePermission(entity.UsersUpdatePermission), h.EditUser)
+	adminUser.DELETE("users/:id", m.RequirePermission(entity.UsersDeletePermission), h.DeleteUser)
+	adminUser.PUT("users/:id/mobile", m.RequirePermission(entity.UsersChangeMobilePermission), h.AdminChangeMobileNumber)
+	adminUser.PUT("users/:id/role", m.RequirePermission(entity.UsersUpdateRolePermission), h.AssignRole)
+	adminUser.POST("users/:id/2fa/reset", m.RequirePermission(entity.UsersResetTwoFactorPermission), h.ResetTwoFactor)
+	adminUser.POST("users/:id/suspend", m.RequirePermission(entity.UsersSuspendPermission), h.SuspendUser)
+	adminUser.POST("users/:id/unsuspend", m.RequirePermission(entity.UsersSuspendPermission), h.UnsuspendUser)
+	adminUser.GET("users/:id/suspensions", m.RequirePermission(entity.UsersSuspendPermission), h.SuspensionHistory)
+	adminUser.GET("users/:id/logins", m.RequirePermission(entity.LoginsReadPermission), h.UserLogins)
+	adminUser.GET("logins/suspicious", m.RequirePermission(entity.LoginsReviewPermission), h.SuspiciousLogins)
+	adminUser.POST("logins/:id/review", m.RequirePermission(entity.LoginsReviewPermission), h.ReviewLogin)
+	adminUser.GET("audit-logs", m.RequirePermission(entity.AuditLogsReadPermission), h.AuditLogs)
+	adminUser.GET("diagnostics/database", m.RequirePermission(entity.DiagnosticsReadPermission), h.DatabaseDiagnostics)
+	adminUser.GET("trash/users", m.RequirePermission(entity.UsersDeletePermission), h.TrashedUsers)
+	adminUser.POST("trash/users/:id/restore", m.RequirePermission(entity.UsersDeletePermission), h.RestoreUser)
+	adminUser.DELETE("trash/users/:id", m.RequirePermission(entity.TrashPurgePermission), h.PurgeUser)
+
+	adminUser.GET("roles", m.RequirePermission(entity.RolesReadPermission), h.RoleList)
+	adminUser.POST("roles", m.RequirePermission(entity.RolesWritePermission), h.CreateRole)
+	adminUser.PUT("roles/:id", m.RequirePermission(entity.RolesWritePermission), h.UpdateRole)
+	adminUser.DELETE("roles/:id", m.RequirePermission(entity.RolesWritePermission), h.DeleteRole)
+	adminUser.PUT("roles/:id/two-factor", m.RequirePermission(entity.RolesWritePermission), h.UpdateRoleTwoFactor)
+
+	// partner credentials and impersonation are for first-party logins only
+	apiKeys := server.Group(prefix)
+	apiKeys.Use(m.AuthenticateMiddleware, middlewares.FirstPartyOnly, middlewares.ForbidImpersonation)
+	apiKeys.GET("api-keys", m.RequirePermission(entity.APIKeysReadPermission), h.APIKeyList)
+	apiKeys.POST("api-keys", m.RequirePermission(entity.APIKeysWritePermission), h.IssueAPIKey)
+	apiKeys.DELETE("api-keys/:id", m.RequirePermission(entity.APIKeysWritePermission), h.RevokeAPIKey)
+	apiKeys.GET("oauth/clients", m.RequirePermission(entity.OAuthClientsReadPermission), h.OAuthClientList)
+	apiKeys.POST("oauth/clients", m.RequirePermission(entity.OAuthClientsWritePermission), h.RegisterOAuthClient)
+	apiKeys.DELETE("oauth/clients/:id", m.RequirePermission(entity.OAuthClientsWritePermission), h.DeleteOAuthClient)
+	apiKeys.POST("users/:id/impersonate", m.RequirePermission(entity.UsersImpersonatePermission), h.StartImpersonation)
 }
@@ -2,47 +2,83 @@ package database
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/TheAmirhosssein/room-reservation-api/config"
-	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func initDB(host, user, password, name string) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%v user=%v password=%v dbname=%v", host, user, password, name)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// Connect opens the connection pool the whole application shares.
+func Connect(conf config.DB) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(DSN(conf)), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return nil, err
 	}
-	return db, err
+	return db, ConfigurePool(db, conf)
 }
 
-func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(&entity.User{}, &entity.State{}, &entity.City{})
+// DSN builds the connection string for conf. The statement timeout and
+// search path are sent as run-time parameters of every connection.
+func DSN(conf config.DB) string {
+	settings := []struct{ key, value string }{
+		{"host", conf.Host},
+		{"port", strconv.Itoa(conf.Port)},
+		{"user", conf.Username},
+		{"password", conf.Password},
+		{"dbname", conf.DB},
+		{"sslmode", conf.SSLMode},
+		{"TimeZone", conf.TimeZone},
+		{"search_path", conf.SearchPath},
+		{"statement_timeout", strconv.FormatInt(conf.StatementTimeout.Milliseconds(), 10)},
+	}
+	parts := make([]string, 0, len(settings))
+	for _, setting := range settings {
+		if setting.value == "" {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%v=%v", setting.key, quoteDSNValue(setting.value)))
+	}
+	return strings.Join(parts, " ")
+}
+
+func quoteDSNValue(value string) string {
+	if !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	return "'" + value + "'"
 }
 
-func StartDB() error {
-	db := GetDb()
-	err := Migrate(db)
+// ConfigurePool applies the connection limits of conf to the pool behind db.
+func ConfigurePool(db *gorm.DB, conf config.DB) error {
+	sqlDB, err := db.DB()
 	if err != nil {
 		return err
 	}
-	return err
+	sqlDB.SetMaxOpenConns(conf.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(conf.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(conf.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(conf.ConnMaxIdleTime)
+	return nil
 }
 
-func GetDb() *gorm.DB {
-	if config.InTestMode() {
-		return TestDb()
+// Migrate applies every pending migration and seeds the built-in roles. The
+// tests use it to build their databases from scratch.
+func Migrate(db *gorm.DB) error {
+	if _, err := MigrateUp(db); err != nil {
+		return err
 	}
+	return SeedRoles(db)
+}
 
-	conf, err := config.NewConfig()
+// StartDB prepares db for serving. It refuses a schema that is behind the
+// migrations of this binary instead of changing it.
+func StartDB(db *gorm.DB) error {
+	err := CheckSchema(db)
 	if err != nil {
-		panic(err.Error())
-	}
-	db, err := initDB(conf.DB.Host, conf.DB.Username, conf.DB.Username, conf.DB.DB)
-	if err != nil {
-		panic(err.Error())
+		return err
 	}
-	return db
+	return SeedRoles(db)
 }
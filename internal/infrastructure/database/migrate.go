@@ -0,0 +1,349 @@
+package database
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MigrationsDir is where new migrations are created, relative to the
+// repository root. The files are embedded in the binary when it is built.
+const MigrationsDir = "internal/infrastructure/database/migrations"
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var (
+	ErrSchemaBehind       = errors.New("database schema is behind")
+	ErrUnsupportedDialect = errors.New("migrations do not support this database")
+	ErrInvalidMigration   = errors.New("invalid migration name")
+)
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is one versioned change to the schema. Its SQL files are
+// templates rendered for the dialect they run on, see dialect.
+type Migration struct {
+	Version int
+	Name    string
+	up      string
+	down    string
+}
+
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+type schemaMigration struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// dialect holds the column types and syntax that differ between Postgres
+// and the sqlite database the tests run on. AddColumn skips existing columns
+// on Postgres; sqlite databases are always built from scratch.
+type dialect struct {
+	Postgres   bool
+	PrimaryKey string
+	Integer    string
+	Timestamp  string
+	Boolean    string
+	Float      string
+	AddColumn  string
+}
+
+// dataMigrations are the steps that can not be written in SQL, keyed by the
+// migration they belong to. Each runs after that migration's SQL in the same
+// transaction.
+var dataMigrations = map[string]func(*gorm.DB) error{
+	"0006_normalize_mobile_numbers": NormalizeMobileNumbers,
+	"0007_title_keys":               FillTitleKeys,
+}
+
+func dialectOf(db *gorm.DB) (dialect, error) {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return dialect{Postgres: true, PrimaryKey: "bigserial PRIMARY KEY", Integer: "bigint", Timestamp: "timestamptz", Boolean: "boolean", Float: "double precision", AddColumn: "ADD COLUMN IF NOT EXISTS"}, nil
+	case "sqlite":
+		return dialect{PrimaryKey: "integer PRIMARY KEY AUTOINCREMENT", Integer: "integer", Timestamp: "datetime", Boolean: "numeric", Float: "real", AddColumn: "ADD COLUMN"}, nil
+	}
+	return dialect{}, fmt.Errorf("%w: %v", ErrUnsupportedDialect, db.Dialector.Name())
+}
+
+func (d dialect) render(name, sql string) (string, error) {
+	tmpl, err := template.New(name).Parse(sql)
+	if err != nil {
+		return "", err
+	}
+	out := bytes.Buffer{}
+	if err := tmpl.Execute(&out, d); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// Migrations returns the migrations embedded in the binary, oldest first.
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMigration, entry.Name())
+		}
+		content, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		version, _ := strconv.Atoi(match[1])
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("%w: version %v is used by %v and %v", ErrInvalidMigration, version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.up = string(content)
+		} else {
+			migration.down = string(content)
+		}
+	}
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.up == "" {
+			return nil, fmt.Errorf("%w: %04d_%v has no up file", ErrInvalidMigration, migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%v", m.Version, m.Name)
+}
+
+func ensureMigrationsTable(db *gorm.DB, d dialect) error {
+	sql, err := d.render("schema_migrations", `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version {{.Integer}} PRIMARY KEY,
+    name text,
+    applied_at {{.Timestamp}}
+)`)
+	if err != nil {
+		return err
+	}
+	return db.Exec(sql).Error
+}
+
+func appliedMigrations(db *gorm.DB) (map[int]schemaMigration, error) {
+	d, err := dialectOf(db)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationsTable(db, d); err != nil {
+		return nil, err
+	}
+	rows := []schemaMigration{}
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// MigrationStatuses lists every known migration and when it was applied.
+func MigrationStatuses(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &row.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// PendingMigrations returns the migrations that are not applied yet, oldest
+// first.
+func PendingMigrations(db *gorm.DB) ([]Migration, error) {
+	statuses, err := MigrationStatuses(db)
+	if err != nil {
+		return nil, err
+	}
+	pending := []Migration{}
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending = append(pending, status.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// MigrateUp applies every pending migration, each in its own transaction,
+// and returns the ones it applied.
+func MigrateUp(db *gorm.DB) ([]Migration, error) {
+	d, err := dialectOf(db)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := PendingMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+	applied := []Migration{}
+	for _, migration := range pending {
+		sql, err := d.render(migration.String(), migration.up)
+		if err != nil {
+			return applied, err
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if hasStatements(sql) {
+				if err := tx.Exec(sql).Error; err != nil {
+					return err
+				}
+			}
+			if step, ok := dataMigrations[migration.String()]; ok {
+				if err := step(tx); err != nil {
+					return err
+				}
+			}
+			row := schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}
+			return tx.Create(&row).Error
+		})
+		if err != nil {
+			return applied, fmt.Errorf("migration %v: %w", migration, err)
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+// hasStatements reports whether sql holds more than comments, which data
+// migrations' files are made of.
+func hasStatements(sql string) bool {
+	for _, line := range strings.Split(sql, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return true
+		}
+	}
+	return false
+}
+
+// MigrateDown reverts the last steps applied migrations, newest first, and
+// returns the ones it reverted.
+func MigrateDown(db *gorm.DB, steps int) ([]Migration, error) {
+	d, err := dialectOf(db)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := MigrationStatuses(db)
+	if err != nil {
+		return nil, err
+	}
+	reverted := []Migration{}
+	for i := len(statuses) - 1; i >= 0 && len(reverted) < steps; i-- {
+		migration := statuses[i].Migration
+		if statuses[i].AppliedAt == nil {
+			continue
+		}
+		if migration.down == "" {
+			return reverted, fmt.Errorf("%w: %v has no down file", ErrInvalidMigration, migration)
+		}
+		sql, err := d.render(migration.String(), migration.down)
+		if err != nil {
+			return reverted, err
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if hasStatements(sql) {
+				if err := tx.Exec(sql).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Delete(&schemaMigration{}, migration.Version).Error
+		})
+		if err != nil {
+			return reverted, fmt.Errorf("migration %v: %w", migration, err)
+		}
+		reverted = append(reverted, migration)
+	}
+	return reverted, nil
+}
+
+// CheckSchema fails with ErrSchemaBehind when db is missing migrations this
+// binary knows about.
+func CheckSchema(db *gorm.DB) error {
+	pending, err := PendingMigrations(db)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %v pending migrations starting at %v, run `migrate up`", ErrSchemaBehind, len(pending), pending[0])
+	}
+	return nil
+}
+
+// CreateMigration writes empty up and down files for a new migration to dir,
+// numbered after the newest migration in it, and returns their paths.
+func CreateMigration(dir, name string) (string, string, error) {
+	name = strings.Trim(regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", ErrInvalidMigration
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", "", err
+	}
+	version := 0
+	for _, entry := range entries {
+		if match := migrationFileName.FindStringSubmatch(entry.Name()); match != nil {
+			existing, _ := strconv.Atoi(match[1])
+			version = max(version, existing)
+		}
+	}
+	migration := Migration{Version: version + 1, Name: name}
+	up := filepath.Join(dir, migration.String()+".up.sql")
+	down := filepath.Join(dir, migration.String()+".down.sql")
+	if err := os.WriteFile(up, []byte("-- "+migration.String()+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(down, []byte("-- reverts "+migration.String()+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	return up, down, nil
+}
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS impersonations;
+DROP TABLE IF EXISTS login_events;
+DROP TABLE IF EXISTS suspensions;
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS two_factors;
+DROP TABLE IF EXISTS o_auth_clients;
+DROP TABLE IF EXISTS api_key_scopes;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS cities;
+DROP TABLE IF EXISTS states;
+DROP TABLE IF EXISTS users;
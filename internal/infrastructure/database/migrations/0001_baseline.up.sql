@@ -0,0 +1,201 @@
+-- The schema as AutoMigrate left it. Databases created before versioned
+-- migrations already have the users, states and cities tables in their
+-- original shape, so those are created in that shape and the columns added
+-- since are added separately; everything else is guarded.
+
+CREATE TABLE IF NOT EXISTS users (
+    id {{.PrimaryKey}},
+    created_at {{.Timestamp}},
+    updated_at {{.Timestamp}},
+    deleted_at {{.Timestamp}},
+    full_name text,
+    mobile_number text,
+    role text
+);
+ALTER TABLE users {{.AddColumn}} country text;
+ALTER TABLE users {{.AddColumn}} suspended_at {{.Timestamp}};
+ALTER TABLE users {{.AddColumn}} suspended_until {{.Timestamp}};
+ALTER TABLE users {{.AddColumn}} suspension_reason text;
+ALTER TABLE users {{.AddColumn}} deletion_requested_at {{.Timestamp}};
+ALTER TABLE users {{.AddColumn}} deletion_scheduled_for {{.Timestamp}};
+ALTER TABLE users {{.AddColumn}} anonymized_at {{.Timestamp}};
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+{{- if .Postgres}}
+-- mobile_number used to be unique among deleted users too
+ALTER TABLE users DROP CONSTRAINT IF EXISTS uni_users_mobile_number;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_mobile_number_key;
+{{- end}}
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_mobile_number ON users (mobile_number) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS states (
+    id {{.PrimaryKey}},
+    created_at {{.Timestamp}},
+    updated_at {{.Timestamp}},
+    deleted_at {{.Timestamp}},
+    title text
+);
+CREATE INDEX IF NOT EXISTS idx_states_deleted_at ON states (deleted_at);
+
+CREATE TABLE IF NOT EXISTS cities (
+    id {{.PrimaryKey}},
+    created_at {{.Timestamp}},
+    updated_at {{.Timestamp}},
+    deleted_at {{.Timestamp}},
+    title text,
+    state_id {{.Integer}},
+    CONSTRAINT fk_cities_state FOREIGN KEY (state_id) REFERENCES states (id)
+);
+CREATE INDEX IF NOT EXISTS idx_cities_deleted_at ON cities (deleted_at);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id {{.PrimaryKey}},
+    created_at {{.Timestamp}},
+    updated_at {{.Timestamp}},
+    deleted_at {{.Timestamp}},
+    actor_id {{.Integer}},
+    action text,
+    target_type text,
+    target_id {{.Integer}},
+    details text,
+    changes text,
+    ip text,
+    request_id text
+);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_deleted_at ON audit_logs (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs (action);
+CREATE INDEX IF NOT EXISTS idx_audit_target ON audit_logs (target_type, target_id);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id {{.PrimaryKey}},
+    created_at {{.Timestamp}},
+    updated_at {{.Timestamp}},
+    deleted_at {{.Timestamp}},
+    name text,
+    require_two_factor {{.Boolean}},
+    CONSTRAINT uni_roles_name UNIQUE (name)
+);
+CREATE INDEX IF NOT EXISTS idx_roles_deleted_at ON roles (deleted_at);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    id {{.PrimaryKey}},
+    role_id {{.Integer}},
+    permission text,
+    CONSTRAINT fk_roles_permissions FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_role_permission ON role_permissions (role_id, permission);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id {{.PrimaryKey}},
+    created_at {{.Timestamp}},
+    updated_at {{.Timestamp}},
+    deleted_at {{.Timestamp}},
+    name text,
+    prefix text,
+    hashed_key text,
+    user_id {{.Integer}},
+    organization text,
+    rate_limit {{.Integer}},
+    expires_at {{.Timestamp}},
+    last_used_at {{.Timestamp}},
+    revoked_at {{.Timestamp}}
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_deleted_at ON api_keys (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_prefix ON api_keys (prefix);
+
+CREATE TABLE IF NOT EXISTS api_key_scopes (
+    id {{.PrimaryKey}},
+    api_key_id {{.Integer}},
+    scope text,
+    CONSTRAINT fk_api_keys_scopes FOREIGN KEY (api_key_id) REFERENCES api_keys (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_key_scope ON api_key_scopes (api_key_id, scope);
+
+CREATE TABLE IF NOT EXISTS o_auth_clients (
+    id {{.PrimaryKey}},
+    created_at {{.Timestamp}},
+    updated_at {{.Timestamp}},
+    deleted_at {{.Timestamp}},
+    name text,
+    client_id text,
+    hashed_secret text,
+    redirect_uris text,
+    scope text
+);
+CREATE INDEX IF NOT EXISTS idx_o_auth_clients_deleted_at ON o_auth_clients (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_o_auth_clients_client_id ON o_auth_clients (client_id);
+
+CREATE TABLE IF NOT EXISTS two_factors (
+    id {{.PrimaryKey}},
+    created_at {{.Timestamp}},
+    updated_at {{.Timestamp}},
+    deleted_at {{.Timestamp}},
+    user_id {{.Integer}},
+    secret text,
+    confirmed_at {{.Timestamp}},
+    last_used_step {{.Integer}}
+);
+CREATE INDEX IF NOT EXISTS idx_two_factors_deleted_at ON two_factors (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_two_factors_user_id ON two_factors (user_id);
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id {{.PrimaryKey}},
+    two_factor_id {{.Integer}},
+    hashed_code text,
+    used_at {{.Timestamp}},
+    CONSTRAINT fk_two_factors_recovery_codes FOREIGN KEY (two_factor_id) REFERENCES two_factors (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_two_factor_id ON recovery_codes (two_factor_id);
+
+CREATE TABLE IF NOT EXISTS suspensions (
+    id {{.PrimaryKey}},
+    created_at {{.Timestamp}},
+    updated_at {{.Timestamp}},
+    deleted_at {{.Timestamp}},
+    user_id {{.Integer}},
+    actor_id {{.Integer}},
+    reason text,
+    notes text,
+    ends_at {{.Timestamp}},
+    lifted_at {{.Timestamp}},
+    lifted_by {{.Integer}},
+    lift_notes text
+);
+CREATE INDEX IF NOT EXISTS idx_suspensions_deleted_at ON suspensions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_suspensions_user_id ON suspensions (user_id);
+
+CREATE TABLE IF NOT EXISTS login_events (
+    id {{.PrimaryKey}},
+    created_at {{.Timestamp}},
+    updated_at {{.Timestamp}},
+    deleted_at {{.Timestamp}},
+    user_id {{.Integer}},
+    mobile_number text,
+    type text,
+    ip text,
+    user_agent text,
+    device_hash text,
+    country text,
+    anomalies text,
+    reviewed_at {{.Timestamp}},
+    reviewed_by {{.Integer}},
+    review_note text
+);
+CREATE INDEX IF NOT EXISTS idx_login_events_deleted_at ON login_events (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_login_events_user_id ON login_events (user_id);
+CREATE INDEX IF NOT EXISTS idx_login_events_mobile_number ON login_events (mobile_number);
+
+CREATE TABLE IF NOT EXISTS impersonations (
+    id {{.PrimaryKey}},
+    created_at {{.Timestamp}},
+    updated_at {{.Timestamp}},
+    deleted_at {{.Timestamp}},
+    actor_id {{.Integer}},
+    subject_id {{.Integer}},
+    reason text,
+    expires_at {{.Timestamp}},
+    ended_at {{.Timestamp}}
+);
+CREATE INDEX IF NOT EXISTS idx_impersonations_deleted_at ON impersonations (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_impersonations_actor_id ON impersonations (actor_id);
+CREATE INDEX IF NOT EXISTS idx_impersonations_subject_id ON impersonations (subject_id);
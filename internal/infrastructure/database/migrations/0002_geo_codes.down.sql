@@ -0,0 +1,4 @@
+DROP INDEX idx_cities_code;
+DROP INDEX idx_states_code;
+ALTER TABLE cities DROP COLUMN code;
+ALTER TABLE states DROP COLUMN code;
@@ -0,0 +1,5 @@
+-- links states and cities to the bundled geographic dataset
+ALTER TABLE states ADD COLUMN code text DEFAULT '';
+ALTER TABLE cities ADD COLUMN code text DEFAULT '';
+CREATE UNIQUE INDEX idx_states_code ON states (code) WHERE code <> '' AND deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_cities_code ON cities (code) WHERE code <> '' AND deleted_at IS NULL;
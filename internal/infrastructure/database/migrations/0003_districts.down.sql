@@ -0,0 +1,2 @@
+DROP TABLE neighborhoods;
+DROP TABLE districts;
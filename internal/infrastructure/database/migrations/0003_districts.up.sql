@@ -0,0 +1,23 @@
+CREATE TABLE districts (
+    id {{.PrimaryKey}},
+    created_at {{.Timestamp}},
+    updated_at {{.Timestamp}},
+    deleted_at {{.Timestamp}},
+    title text,
+    city_id {{.Integer}},
+    CONSTRAINT fk_districts_city FOREIGN KEY (city_id) REFERENCES cities (id)
+);
+CREATE INDEX idx_districts_deleted_at ON districts (deleted_at);
+CREATE INDEX idx_districts_city_id ON districts (city_id);
+
+CREATE TABLE neighborhoods (
+    id {{.PrimaryKey}},
+    created_at {{.Timestamp}},
+    updated_at {{.Timestamp}},
+    deleted_at {{.Timestamp}},
+    title text,
+    district_id {{.Integer}},
+    CONSTRAINT fk_neighborhoods_district FOREIGN KEY (district_id) REFERENCES districts (id)
+);
+CREATE INDEX idx_neighborhoods_deleted_at ON neighborhoods (deleted_at);
+CREATE INDEX idx_neighborhoods_district_id ON neighborhoods (district_id);
@@ -0,0 +1,2 @@
+DROP TABLE city_translations;
+DROP TABLE state_translations;
@@ -0,0 +1,18 @@
+-- titles of states and cities in locales other than the default one
+CREATE TABLE state_translations (
+    id {{.PrimaryKey}},
+    state_id {{.Integer}},
+    locale text,
+    title text,
+    CONSTRAINT fk_states_translations FOREIGN KEY (state_id) REFERENCES states (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_state_translation ON state_translations (state_id, locale);
+
+CREATE TABLE city_translations (
+    id {{.PrimaryKey}},
+    city_id {{.Integer}},
+    locale text,
+    title text,
+    CONSTRAINT fk_cities_translations FOREIGN KEY (city_id) REFERENCES cities (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_city_translation ON city_translations (city_id, locale);
@@ -0,0 +1,7 @@
+DROP INDEX idx_cities_location;
+ALTER TABLE cities DROP COLUMN boundary;
+ALTER TABLE cities DROP COLUMN longitude;
+ALTER TABLE cities DROP COLUMN latitude;
+ALTER TABLE states DROP COLUMN boundary;
+ALTER TABLE states DROP COLUMN longitude;
+ALTER TABLE states DROP COLUMN latitude;
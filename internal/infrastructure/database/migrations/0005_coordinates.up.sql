@@ -0,0 +1,8 @@
+-- places states and cities on the map, the index narrows nearby searches down
+ALTER TABLE states ADD COLUMN latitude {{.Float}};
+ALTER TABLE states ADD COLUMN longitude {{.Float}};
+ALTER TABLE states ADD COLUMN boundary text;
+ALTER TABLE cities ADD COLUMN latitude {{.Float}};
+ALTER TABLE cities ADD COLUMN longitude {{.Float}};
+ALTER TABLE cities ADD COLUMN boundary text;
+CREATE INDEX idx_cities_location ON cities (latitude, longitude);
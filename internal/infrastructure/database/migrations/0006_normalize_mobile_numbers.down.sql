@@ -0,0 +1,2 @@
+-- The original numbers and merged users are not kept, so there is nothing
+-- to revert.
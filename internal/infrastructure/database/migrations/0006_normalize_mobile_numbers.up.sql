@@ -0,0 +1,2 @@
+-- Rewrites stored mobile numbers to E.164 and merges the users that turn out
+-- to share one. The work is done in Go, see NormalizeMobileNumbers.
@@ -0,0 +1,4 @@
+DROP INDEX idx_cities_title_key;
+DROP INDEX idx_states_title_key;
+ALTER TABLE cities DROP COLUMN title_key;
+ALTER TABLE states DROP COLUMN title_key;
@@ -0,0 +1,6 @@
+-- keeps titles unique regardless of case and letter forms, the keys are
+-- filled in by FillTitleKeys
+ALTER TABLE states ADD COLUMN title_key text;
+ALTER TABLE cities ADD COLUMN title_key text;
+CREATE UNIQUE INDEX idx_states_title_key ON states (title_key) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_cities_title_key ON cities (state_id, title_key) WHERE deleted_at IS NULL;
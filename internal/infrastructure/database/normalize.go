@@ -0,0 +1,78 @@
+package database
+
+import (
+	"log"
+	"sort"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/validators"
+	"gorm.io/gorm"
+)
+
+var rolePriority = map[string]int{
+	entity.UserRole:    0,
+	entity.SupportRole: 1,
+	entity.AdminRole:   2,
+}
+
+// NormalizeMobileNumbers rewrites stored mobile numbers to their canonical
+// form and fills in the country derived from them. Users that turn out to
+// share a number are merged into the oldest active account, which keeps the
+// most privileged role and the first non-empty full name; the other rows are
+// removed. It runs once, as migration 0006.
+func NormalizeMobileNumbers(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var users []entity.User
+		if err := tx.Unscoped().Order("id").Find(&users).Error; err != nil {
+			return err
+		}
+
+		groups := map[string][]entity.User{}
+		countries := map[string]string{}
+		for _, user := range users {
+			number, err := validators.ParseMobileNumber(user.MobileNumber)
+			if err != nil {
+				log.Printf("skipping user %v with invalid mobile number %q", user.ID, user.MobileNumber)
+				continue
+			}
+			groups[number.E164()] = append(groups[number.E164()], user)
+			countries[number.E164()] = number.Region
+		}
+
+		for mobileNumber, group := range groups {
+			keeper := group[0]
+			if len(group) == 1 && keeper.MobileNumber == mobileNumber && keeper.Country == countries[mobileNumber] {
+				continue
+			}
+			if err := mergeUsers(tx, mobileNumber, countries[mobileNumber], group); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func mergeUsers(tx *gorm.DB, mobileNumber, country string, group []entity.User) error {
+	sort.SliceStable(group, func(i, j int) bool {
+		return !group[i].DeletedAt.Valid && group[j].DeletedAt.Valid
+	})
+	keeper, duplicates := group[0], group[1:]
+	for _, duplicate := range duplicates {
+		if keeper.FullName == "" {
+			keeper.FullName = duplicate.FullName
+		}
+		if rolePriority[duplicate.Role] > rolePriority[keeper.Role] {
+			keeper.Role = duplicate.Role
+		}
+		log.Printf("merging user %v into user %v (%v)", duplicate.ID, keeper.ID, mobileNumber)
+		if err := tx.Unscoped().Delete(&entity.User{}, duplicate.ID).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Unscoped().Model(&entity.User{}).Where("id = ?", keeper.ID).Updates(map[string]any{
+		"mobile_number": mobileNumber,
+		"country":       country,
+		"full_name":     keeper.FullName,
+		"role":          keeper.Role,
+	}).Error
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+// SeedRoles creates the built-in roles when they are missing. Permission
+// sets edited by admins are kept, except for Admin which always receives
+// every known permission.
+func SeedRoles(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for name, permissions := range entity.DefaultRolePermissions {
+			role := entity.Role{}
+			err := tx.Preload("Permissions").First(&role, "name = ?", name).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				role = entity.NewRole(name, permissions)
+				if err := tx.Create(&role).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if name != entity.AdminRole {
+				continue
+			}
+			for _, permission := range permissions {
+				if role.HasPermission(permission) {
+					continue
+				}
+				rolePermission := entity.RolePermission{RoleID: role.ID, Permission: permission}
+				if err := tx.Create(&rolePermission).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
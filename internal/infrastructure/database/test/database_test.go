@@ -0,0 +1,45 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestDSN(t *testing.T) {
+	conf := config.DB{
+		Host:             "db",
+		Port:             5432,
+		Username:         "postgres",
+		Password:         "it's secret",
+		DB:               "rooms",
+		SSLMode:          "require",
+		TimeZone:         "Asia/Tehran",
+		SearchPath:       "rooms,public",
+		StatementTimeout: 30 * time.Second,
+	}
+	expected := `host=db port=5432 user=postgres password='it\'s secret' dbname=rooms sslmode=require ` +
+		`TimeZone=Asia/Tehran search_path=rooms,public statement_timeout=30000`
+	assert.Equal(t, expected, database.DSN(conf))
+
+	conf.SearchPath, conf.TimeZone = "", ""
+	assert.NotContains(t, database.DSN(conf), "search_path")
+	assert.NotContains(t, database.DSN(conf), "TimeZone")
+}
+
+func TestConfigurePool(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	conf := config.DB{MaxOpenConns: 7, MaxIdleConns: 3, ConnMaxLifetime: time.Minute, ConnMaxIdleTime: time.Second}
+	assert.NoError(t, database.ConfigurePool(db, conf))
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.Equal(t, 7, sqlDB.Stats().MaxOpenConnections)
+}
@@ -0,0 +1,159 @@
+package database_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var models = []any{
+	&entity.User{},
+	&entity.State{},
+	&entity.City{},
+	&entity.AuditLog{},
+	&entity.Role{},
+	&entity.RolePermission{},
+	&entity.APIKey{},
+	&entity.APIKeyScope{},
+	&entity.OAuthClient{},
+	&entity.TwoFactor{},
+	&entity.RecoveryCode{},
+	&entity.Suspension{},
+	&entity.LoginEvent{},
+	&entity.Impersonation{},
+	&entity.District{},
+	&entity.Neighborhood{},
+	&entity.StateTranslation{},
+	&entity.CityTranslation{},
+}
+
+func openDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestMigrateUpAndDown(t *testing.T) {
+	db := openDB(t)
+	assert.ErrorIs(t, database.CheckSchema(db), database.ErrSchemaBehind)
+
+	applied, err := database.MigrateUp(db)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, applied)
+	assert.NoError(t, database.CheckSchema(db))
+	applied, err = database.MigrateUp(db)
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+
+	statuses, err := database.MigrationStatuses(db)
+	assert.NoError(t, err)
+	for _, status := range statuses {
+		assert.NotNil(t, status.AppliedAt, status.String())
+	}
+
+	reverted, err := database.MigrateDown(db, len(statuses))
+	assert.NoError(t, err)
+	assert.Len(t, reverted, len(statuses))
+	assert.False(t, db.Migrator().HasTable(&entity.User{}))
+	pending, err := database.PendingMigrations(db)
+	assert.NoError(t, err)
+	assert.Len(t, pending, len(statuses))
+
+	assert.NoError(t, database.Migrate(db))
+	assert.NoError(t, database.StartDB(db))
+}
+
+func TestMigrationsMatchModels(t *testing.T) {
+	db := openDB(t)
+	assert.NoError(t, database.Migrate(db))
+	for _, model := range models {
+		parsed, err := schema.Parse(model, &sync.Map{}, db.NamingStrategy)
+		assert.NoError(t, err)
+		assert.True(t, db.Migrator().HasTable(model), parsed.Table)
+		for _, field := range parsed.Fields {
+			if field.DBName != "" {
+				assert.True(t, db.Migrator().HasColumn(model, field.DBName), parsed.Table+"."+field.DBName)
+			}
+		}
+		for _, index := range parsed.ParseIndexes() {
+			assert.True(t, db.Migrator().HasIndex(model, index.Name), index.Name)
+		}
+	}
+}
+
+// the schema AutoMigrate created before versioned migrations
+type legacyUser struct {
+	gorm.Model
+	FullName     string
+	MobileNumber string `gorm:"unique"`
+	Role         string
+}
+
+func (legacyUser) TableName() string { return "users" }
+
+type legacyState struct {
+	gorm.Model
+	Title string
+}
+
+func (legacyState) TableName() string { return "states" }
+
+type legacyCity struct {
+	gorm.Model
+	Title   string
+	StateID uint
+	State   legacyState
+}
+
+func (legacyCity) TableName() string { return "cities" }
+
+func TestMigrateUpFromLegacySchema(t *testing.T) {
+	db := openDB(t)
+	assert.NoError(t, db.AutoMigrate(&legacyUser{}, &legacyState{}, &legacyCity{}))
+	assert.NoError(t, db.Create(&legacyUser{FullName: "old", MobileNumber: "09121234567", Role: entity.AdminRole}).Error)
+	assert.NoError(t, db.Create(&legacyUser{MobileNumber: "+98 912 123 4567", Role: entity.UserRole}).Error)
+
+	assert.NoError(t, database.Migrate(db))
+	assert.NoError(t, database.StartDB(db))
+	for _, model := range models {
+		parsed, err := schema.Parse(model, &sync.Map{}, db.NamingStrategy)
+		assert.NoError(t, err)
+		for _, field := range parsed.Fields {
+			if field.DBName != "" {
+				assert.True(t, db.Migrator().HasColumn(model, field.DBName), parsed.Table+"."+field.DBName)
+			}
+		}
+	}
+
+	var users []entity.User
+	assert.NoError(t, db.Find(&users).Error)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "+989121234567", users[0].MobileNumber)
+	assert.Equal(t, "IR", users[0].Country)
+	assert.Equal(t, entity.AdminRole, users[0].Role)
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "0007_existing.up.sql"), nil, 0o644)
+
+	up, down, err := database.CreateMigration(dir, "Add City Coordinates")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "0008_add_city_coordinates.up.sql"), up)
+	assert.Equal(t, filepath.Join(dir, "0008_add_city_coordinates.down.sql"), down)
+	assert.FileExists(t, up)
+	assert.FileExists(t, down)
+
+	_, _, err = database.CreateMigration(dir, "--")
+	assert.ErrorIs(t, err, database.ErrInvalidMigration)
+}
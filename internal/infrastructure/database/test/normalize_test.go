@@ -0,0 +1,55 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestNormalizeMobileNumbers(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+
+	users := []entity.User{
+		entity.NewUser("", "09123456789", entity.UserRole),
+		entity.NewUser("someone", "+989123456789", entity.AdminRole),
+		entity.NewUser("other", "00989123456789", entity.SupportRole),
+		entity.NewUser("single", "09351112233", entity.UserRole),
+		entity.NewUser("invalid", "1234", entity.UserRole),
+	}
+	for i := range users {
+		assert.NoError(t, db.Create(&users[i]).Error)
+	}
+
+	err = database.NormalizeMobileNumbers(db)
+	assert.NoError(t, err)
+
+	var count int64
+	db.Unscoped().Model(&entity.User{}).Count(&count)
+	assert.Equal(t, int64(3), count)
+
+	var merged entity.User
+	db.First(&merged, users[0].ID)
+	assert.Equal(t, "+989123456789", merged.MobileNumber)
+	assert.Equal(t, "someone", merged.FullName)
+	assert.Equal(t, entity.AdminRole, merged.Role)
+
+	var single entity.User
+	db.First(&single, users[3].ID)
+	assert.Equal(t, "+989351112233", single.MobileNumber)
+	assert.Equal(t, "IR", single.Country)
+
+	var invalid entity.User
+	db.First(&invalid, users[4].ID)
+	assert.Equal(t, "1234", invalid.MobileNumber)
+
+	err = database.NormalizeMobileNumbers(db)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,38 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFillTitleKeys(t *testing.T) {
+	db := openDB(t)
+	assert.NoError(t, database.Migrate(db))
+	_, err := database.MigrateDown(db, 1)
+	assert.NoError(t, err)
+
+	// titles that were taken twice before the keys existed
+	assert.NoError(t, db.Exec("INSERT INTO states (id, title) VALUES (1, 'Tehran'), (2, ' TEHRAN'), (3, 'Qom')").Error)
+	assert.NoError(t, db.Exec("INSERT INTO cities (id, title, state_id) VALUES (1, 'كرج', 1), (2, 'کرج', 1), (3, 'کرج', 3)").Error)
+	assert.NoError(t, database.Migrate(db))
+
+	var keys []string
+	assert.NoError(t, db.Table("states").Order("id").Pluck("title_key", &keys).Error)
+	assert.Equal(t, []string{"tehran", "tehran#2", "qom"}, keys)
+	assert.NoError(t, db.Table("cities").Order("id").Pluck("title_key", &keys).Error)
+	assert.Equal(t, []string{"کرج", "کرج#2", "کرج"}, keys)
+
+	ctx := context.TODO()
+	states := repository.NewStateRepository(db)
+	duplicate := entity.NewState("qom ")
+	assert.True(t, repository.IsTitleTaken(states.Save(ctx, &duplicate).Error))
+	state := entity.State{}
+	assert.NoError(t, states.ById(ctx, 2, &state).Error)
+	assert.True(t, repository.IsTitleTaken(states.Update(ctx, &state, map[string]any{"title": "QOM"})))
+	assert.NoError(t, states.Update(ctx, &state, map[string]any{"title": "Tehran 2"}))
+}
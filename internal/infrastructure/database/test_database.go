@@ -8,7 +8,7 @@ import (
 var testDb *gorm.DB
 
 func InitiateTestDB() {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type titledRow struct {
+	ID        uint
+	Title     string
+	StateID   uint
+	DeletedAt gorm.DeletedAt
+}
+
+// FillTitleKeys stores the folded title of every state and city. Titles that
+// were already taken, by another state or by another city of the same state,
+// are reported and get a key of their own, suffixed with their id, so they
+// keep working until they are renamed. It runs once, as migration 0007.
+func FillTitleKeys(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := fillTitleKeys(tx, "states", "id, title, deleted_at"); err != nil {
+			return err
+		}
+		return fillTitleKeys(tx, "cities", "id, title, state_id, deleted_at")
+	})
+}
+
+func fillTitleKeys(tx *gorm.DB, table, columns string) error {
+	var rows []titledRow
+	if err := tx.Table(table).Select(columns).Order("id").Find(&rows).Error; err != nil {
+		return err
+	}
+	owners := map[string]uint{}
+	for _, row := range rows {
+		key := utils.FoldTitle(row.Title)
+		if !row.DeletedAt.Valid {
+			scoped := fmt.Sprintf("%v/%v", row.StateID, key)
+			if owner, taken := owners[scoped]; taken {
+				log.Printf("%v %v has the same title as %v %v, rename it", table, row.ID, table, owner)
+				key = fmt.Sprintf("%v#%v", key, row.ID)
+			} else {
+				owners[scoped] = row.ID
+			}
+		}
+		if err := tx.Table(table).Where("id = ?", row.ID).Update("title_key", key).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
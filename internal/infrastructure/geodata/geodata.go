@@ -0,0 +1,28 @@
+// Package geodata bundles the provinces and counties of Iran. Provinces are
+// coded by ISO 3166-2:IR, counties by their province code followed by a
+// number that stays the same across dataset versions.
+package geodata
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sync"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+)
+
+//go:embed iran.json
+var iranJSON []byte
+
+var iran = sync.OnceValue(func() entity.GeoDataset {
+	dataset := entity.GeoDataset{}
+	if err := json.Unmarshal(iranJSON, &dataset); err != nil {
+		panic("geodata: invalid iran.json: " + err.Error())
+	}
+	return dataset
+})
+
+// Iran returns the bundled dataset. Callers must not modify it.
+func Iran() entity.GeoDataset {
+	return iran()
+}
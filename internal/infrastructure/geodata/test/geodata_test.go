@@ -0,0 +1,36 @@
+package geodata_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/geodata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIran(t *testing.T) {
+	dataset := geodata.Iran()
+	assert.NotEmpty(t, dataset.Version)
+	assert.Len(t, dataset.States, 31)
+
+	codes := map[string]bool{}
+	for _, state := range dataset.States {
+		assert.Regexp(t, `^IR-\d{2}$`, state.Code)
+		assert.NotEmpty(t, state.Title, state.Code)
+		assert.NotEmpty(t, state.EnglishTitle, state.Code)
+		assert.NotEmpty(t, state.Cities, state.Code)
+		assert.False(t, codes[state.Code], state.Code)
+		codes[state.Code] = true
+
+		titles := map[string]bool{}
+		for _, city := range state.Cities {
+			assert.True(t, strings.HasPrefix(city.Code, state.Code+"-"), city.Code)
+			assert.NotEmpty(t, city.Title, city.Code)
+			assert.NotEmpty(t, city.EnglishTitle, city.Code)
+			assert.False(t, codes[city.Code], city.Code)
+			assert.False(t, titles[city.Title], city.Title)
+			codes[city.Code] = true
+			titles[city.Title] = true
+		}
+	}
+}
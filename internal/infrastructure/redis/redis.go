@@ -5,22 +5,11 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-func Client() *redis.Client {
-	conf, err := config.NewConfig()
+// Connect creates the client the whole application shares.
+func Connect(conf config.Redis) (*redis.Client, error) {
+	opt, err := redis.ParseURL(conf.Url)
 	if err != nil {
-		panic(err.Error())
+		return nil, err
 	}
-	opt, err := redis.ParseURL(conf.Redis.Url)
-	if err != nil {
-		panic(err)
-	}
-
-	return redis.NewClient(opt)
-}
-
-func GetClient() *redis.Client {
-	if config.InTestMode() {
-		return TestClient()
-	}
-	return Client()
+	return redis.NewClient(opt), nil
 }
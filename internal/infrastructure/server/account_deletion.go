@@ -0,0 +1,25 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+)
+
+// anonymizeDeletedAccounts anonymizes accounts whose deletion grace period is
+// over, once at startup and then every sweep interval.
+func anonymizeDeletedAccounts(accountUseCase usecase.AccountUseCase, conf config.AccountDeletion) {
+	for {
+		anonymized, err := accountUseCase.AnonymizeDue(context.Background(), time.Now())
+		if err != nil {
+			log.Printf("anonymizing deleted accounts failed: %v", err)
+		}
+		if anonymized > 0 {
+			log.Printf("anonymized %v deleted accounts", anonymized)
+		}
+		time.Sleep(conf.SweepInterval)
+	}
+}
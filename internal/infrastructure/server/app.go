@@ -3,8 +3,9 @@ package server
 import (
 	"fmt"
 
-	"github.com/TheAmirhosssein/room-reservation-api/config"
 	"github.com/TheAmirhosssein/room-reservation-api/docs"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/app"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/http/middlewares"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/http/routers"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -12,10 +13,15 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func Run(conf *config.Config) {
+func Run(container *app.Container) {
+	conf := container.Config
+	go purgeAuditLogs(container.AuditLogs, conf.Audit)
+	go anonymizeDeletedAccounts(container.Accounts, conf.AccountDeletion)
+
 	server := gin.Default()
-	routers.UserRouters(server, "/api/v1/user")
-	routers.SettingsRouters(server, "/api/v1/settings")
+	server.Use(middlewares.RequestID)
+	routers.UserRouters(server, "/api/v1/user", container)
+	routers.SettingsRouters(server, "/api/v1/settings", container)
 
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
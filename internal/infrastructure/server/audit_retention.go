@@ -0,0 +1,24 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+)
+
+// purgeAuditLogs drops audit log entries older than the configured retention,
+// once at startup and then every purge interval.
+func purgeAuditLogs(auditUseCase usecase.AuditLogUseCase, conf config.Audit) {
+	for {
+		purged, err := auditUseCase.Purge(context.Background(), conf.Retention)
+		if err != nil {
+			log.Printf("purging audit logs failed: %v", err)
+		} else if purged > 0 {
+			log.Printf("purged %v audit logs older than %v", purged, conf.Retention)
+		}
+		time.Sleep(conf.PurgeInterval)
+	}
+}
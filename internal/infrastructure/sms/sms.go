@@ -0,0 +1,109 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+)
+
+type Sender interface {
+	Send(ctx context.Context, to, message string) error
+}
+
+// Router picks the sender registered for the country of the recipient and
+// falls back to the default sender for every other country.
+type Router struct {
+	mu       sync.RWMutex
+	senders  map[string]Sender
+	fallback Sender
+}
+
+func NewRouter(fallback Sender) *Router {
+	return &Router{senders: map[string]Sender{}, fallback: fallback}
+}
+
+func (router *Router) Register(country string, sender Sender) {
+	router.mu.Lock()
+	defer router.mu.Unlock()
+	router.senders[country] = sender
+}
+
+func (router *Router) SenderFor(country string) Sender {
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+	if sender, ok := router.senders[country]; ok {
+		return sender
+	}
+	return router.fallback
+}
+
+func (router *Router) Send(ctx context.Context, country, to, message string) error {
+	return router.SenderFor(country).Send(ctx, to, message)
+}
+
+// ConsoleSender writes messages to the log instead of delivering them. The
+// log line holds the whole message, codes included, so it is only chosen
+// when SMS_PROVIDER is explicitly set to console.
+type ConsoleSender struct {
+	Name string
+}
+
+func (sender ConsoleSender) Send(ctx context.Context, to, message string) error {
+	log.Printf("[sms:%v] to %v: %v", sender.Name, to, message)
+	return nil
+}
+
+// HTTPSender posts messages as JSON to the provider's endpoint.
+type HTTPSender struct {
+	URL    string
+	APIKey string
+	Client *http.Client
+}
+
+func (sender HTTPSender) Send(ctx context.Context, to, message string) error {
+	body, err := json.Marshal(map[string]string{"to": to, "message": message})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sender.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+sender.APIKey)
+	res, err := sender.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("sms provider responded with %v", res.Status)
+	}
+	return nil
+}
+
+// Connect builds the router for the configured provider. There is no
+// default, so a deployment that forgot to configure one fails to start
+// instead of never delivering a code.
+func Connect(conf config.SMS) (*Router, error) {
+	switch conf.Provider {
+	case "http":
+		if conf.URL == "" {
+			return nil, errors.New("SMS_URL is required by the http provider")
+		}
+		return NewRouter(HTTPSender{URL: conf.URL, APIKey: conf.APIKey, Client: &http.Client{Timeout: conf.Timeout}}), nil
+	case "console":
+		router := NewRouter(ConsoleSender{Name: "international"})
+		router.Register("IR", ConsoleSender{Name: "local"})
+		return router, nil
+	default:
+		return nil, fmt.Errorf("SMS_PROVIDER must be http or console, got %q", conf.Provider)
+	}
+}
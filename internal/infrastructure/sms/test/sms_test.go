@@ -0,0 +1,74 @@
+package sms_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/sms"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSender struct {
+	messages []string
+}
+
+func (sender *recordingSender) Send(ctx context.Context, to, message string) error {
+	sender.messages = append(sender.messages, to+":"+message)
+	return nil
+}
+
+func TestRouter(t *testing.T) {
+	fallback := &recordingSender{}
+	local := &recordingSender{}
+	router := sms.NewRouter(fallback)
+	router.Register("IR", local)
+
+	assert.NoError(t, router.Send(context.TODO(), "IR", "+989123456789", "hi"))
+	assert.NoError(t, router.Send(context.TODO(), "GB", "+447911123456", "hello"))
+
+	assert.Equal(t, []string{"+989123456789:hi"}, local.messages)
+	assert.Equal(t, []string{"+447911123456:hello"}, fallback.messages)
+}
+
+func TestHTTPSender(t *testing.T) {
+	var received map[string]string
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if received["to"] == "+447911123456" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	router, err := sms.Connect(config.SMS{Provider: "http", URL: server.URL, APIKey: "key", Timeout: time.Second})
+	assert.NoError(t, err)
+	assert.NoError(t, router.Send(context.TODO(), "IR", "+989123456789", "your login code is 482913"))
+	assert.Equal(t, map[string]string{"to": "+989123456789", "message": "your login code is 482913"}, received)
+	assert.Equal(t, "Bearer key", authorization)
+	assert.Error(t, router.Send(context.TODO(), "GB", "+447911123456", "hello"))
+}
+
+func TestConnect(t *testing.T) {
+	_, err := sms.Connect(config.SMS{})
+	assert.Error(t, err)
+	_, err = sms.Connect(config.SMS{Provider: "http"})
+	assert.Error(t, err)
+
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+	router, err := sms.Connect(config.SMS{Provider: "console"})
+	assert.NoError(t, err)
+	assert.NoError(t, router.Send(context.TODO(), "IR", "+989123456789", "your login code is 482913"))
+	assert.Contains(t, out.String(), "[sms:local] to +989123456789: your login code is 482913")
+}
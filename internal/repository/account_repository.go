@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type AccountRepository interface {
+	ScheduleDeletion(context.Context, *entity.User, time.Time, time.Time) error
+	CancelDeletion(context.Context, *entity.User) error
+	DueForAnonymization(context.Context, time.Time) ([]entity.User, error)
+	Anonymize(context.Context, *entity.User, time.Time) error
+	Export(context.Context, uint) (entity.AccountExport, error)
+}
+
+type accountRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountRepository(db *gorm.DB) AccountRepository {
+	return accountRepository{db: db}
+}
+
+func (repo accountRepository) ScheduleDeletion(ctx context.Context, user *entity.User, requestedAt, scheduledFor time.Time) error {
+	newInfo := map[string]any{"deletion_requested_at": requestedAt, "deletion_scheduled_for": scheduledFor}
+	if err := repo.db.WithContext(ctx).Model(user).Updates(newInfo).Error; err != nil {
+		return err
+	}
+	user.DeletionRequestedAt, user.DeletionScheduledFor = &requestedAt, &scheduledFor
+	return nil
+}
+
+func (repo accountRepository) CancelDeletion(ctx context.Context, user *entity.User) error {
+	newInfo := map[string]any{"deletion_requested_at": nil, "deletion_scheduled_for": nil}
+	if err := repo.db.WithContext(ctx).Model(user).Updates(newInfo).Error; err != nil {
+		return err
+	}
+	user.DeletionRequestedAt, user.DeletionScheduledFor = nil, nil
+	return nil
+}
+
+func (repo accountRepository) DueForAnonymization(ctx context.Context, now time.Time) ([]entity.User, error) {
+	var users []entity.User
+	err := repo.db.WithContext(ctx).
+		Where("deletion_scheduled_for <= ? AND anonymized_at IS NULL", now).
+		Order("deletion_scheduled_for").
+		Find(&users).Error
+	return users, err
+}
+
+// Anonymize strips the user of everything that identifies them, drops their
+// credentials and deletes the account. Rows that only reference the user's
+// ID, like suspensions and audit logs, are kept.
+func (repo accountRepository) Anonymize(ctx context.Context, user *entity.User, now time.Time) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteCredentials(tx, user.ID); err != nil {
+			return err
+		}
+		if err := scrubLoginEvents(tx, user.ID); err != nil {
+			return err
+		}
+		if err := redactAuditLogs(tx, user.ID); err != nil {
+			return err
+		}
+		userInfo := map[string]any{
+			"full_name":         "",
+			"mobile_number":     entity.AnonymizedMobileNumber(user.ID),
+			"country":           "",
+			"suspension_reason": "",
+			"anonymized_at":     now,
+			"deleted_at":        now,
+		}
+		return tx.Model(user).Updates(userInfo).Error
+	})
+}
+
+// scrubLoginEvents clears what the user's login history says about the
+// number, network and device they logged in from.
+func scrubLoginEvents(tx *gorm.DB, userId uint) error {
+	loginEventInfo := map[string]any{"mobile_number": "", "ip": "", "user_agent": "", "device_hash": ""}
+	return tx.Model(&entity.LoginEvent{}).Where("user_id = ?", userId).Updates(loginEventInfo).Error
+}
+
+// redactAuditLogs scrubs the personal data the user's audit history holds.
+// Audit logs are otherwise immutable, so it bypasses the entity's guard.
+func redactAuditLogs(tx *gorm.DB, userId uint) error {
+	var auditLogs []entity.AuditLog
+	if err := tx.Where("target_type = ? AND target_id = ?", "user", userId).Find(&auditLogs).Error; err != nil {
+		return err
+	}
+	for _, auditLog := range auditLogs {
+		redacted, err := auditLog.Redact(entity.PersonalUserFields)
+		if err != nil {
+			return err
+		}
+		err = tx.Session(&gorm.Session{SkipHooks: true}).
+			Model(&entity.AuditLog{}).
+			Where("id = ?", auditLog.ID).
+			Updates(map[string]any{"details": redacted.Details, "changes": redacted.Changes}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo accountRepository) Export(ctx context.Context, userId uint) (entity.AccountExport, error) {
+	export := entity.AccountExport{}
+	db := repo.db.WithContext(ctx)
+	if err := db.First(&export.User, "id = ?", userId).Error; err != nil {
+		return export, err
+	}
+	if err := db.Where("user_id = ?", userId).Order("id").Find(&export.LoginEvents).Error; err != nil {
+		return export, err
+	}
+	if err := db.Where("user_id = ?", userId).Order("id").Find(&export.Suspensions).Error; err != nil {
+		return export, err
+	}
+	if err := db.Preload("Scopes").Where("user_id = ?", userId).Order("id").Find(&export.APIKeys).Error; err != nil {
+		return export, err
+	}
+	if err := db.Where("subject_id = ?", userId).Order("id").Find(&export.Impersonations).Error; err != nil {
+		return export, err
+	}
+	err := db.Where("target_type = ? AND target_id = ?", "user", userId).Order("id").Find(&export.AuditLogs).Error
+	if err != nil {
+		return export, err
+	}
+	twoFactor := entity.TwoFactor{}
+	err = db.First(&twoFactor, "user_id = ?", userId).Error
+	if err == nil {
+		export.TwoFactor = &twoFactor
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return export, err
+	}
+	return export, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	Save(context.Context, *entity.APIKey) error
+	List(context.Context) ([]entity.APIKey, error)
+	ById(context.Context, uint, *entity.APIKey) *gorm.DB
+	ByPrefix(context.Context, string, *entity.APIKey) *gorm.DB
+	Revoke(context.Context, *entity.APIKey, time.Time) error
+	TouchLastUsed(context.Context, *entity.APIKey, time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return apiKeyRepository{db: db}
+}
+
+func (repo apiKeyRepository) Save(ctx context.Context, apiKey *entity.APIKey) error {
+	return repo.db.WithContext(ctx).Create(apiKey).Error
+}
+
+func (repo apiKeyRepository) List(ctx context.Context) ([]entity.APIKey, error) {
+	var apiKeys []entity.APIKey
+	err := repo.db.WithContext(ctx).Preload("Scopes").Order("id").Find(&apiKeys).Error
+	return apiKeys, err
+}
+
+func (repo apiKeyRepository) ById(ctx context.Context, id uint, apiKey *entity.APIKey) *gorm.DB {
+	return repo.db.WithContext(ctx).Preload("Scopes").First(apiKey, id)
+}
+
+func (repo apiKeyRepository) ByPrefix(ctx context.Context, prefix string, apiKey *entity.APIKey) *gorm.DB {
+	return repo.db.WithContext(ctx).Preload("Scopes").First(apiKey, "prefix = ?", prefix)
+}
+
+func (repo apiKeyRepository) Revoke(ctx context.Context, apiKey *entity.APIKey, revokedAt time.Time) error {
+	apiKey.RevokedAt = &revokedAt
+	return repo.db.WithContext(ctx).Model(apiKey).Update("revoked_at", revokedAt).Error
+}
+
+func (repo apiKeyRepository) TouchLastUsed(ctx context.Context, apiKey *entity.APIKey, usedAt time.Time) error {
+	apiKey.LastUsedAt = &usedAt
+	return repo.db.WithContext(ctx).Model(apiKey).Update("last_used_at", usedAt).Error
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+// AuditLogFilter narrows down List. Zero values match everything.
+type AuditLogFilter struct {
+	ActorID    uint
+	Action     string
+	TargetType string
+	TargetID   uint
+	From       time.Time
+	To         time.Time
+}
+
+type AuditLogRepository interface {
+	Save(context.Context, *entity.AuditLog) error
+	ByTarget(context.Context, string, uint) ([]entity.AuditLog, error)
+	List(context.Context, AuditLogFilter, int, int) ([]entity.AuditLog, int, error)
+	PurgeBefore(context.Context, time.Time) (int, error)
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return auditLogRepository{db: db}
+}
+
+func (repo auditLogRepository) Save(ctx context.Context, auditLog *entity.AuditLog) error {
+	return repo.db.WithContext(ctx).Create(auditLog).Error
+}
+
+func (repo auditLogRepository) ByTarget(ctx context.Context, targetType string, targetId uint) ([]entity.AuditLog, error) {
+	var auditLogs []entity.AuditLog
+	err := repo.db.WithContext(ctx).
+		Where("target_type = ? AND target_id = ?", targetType, targetId).
+		Order("id").
+		Find(&auditLogs).Error
+	return auditLogs, err
+}
+
+func (repo auditLogRepository) List(ctx context.Context, filter AuditLogFilter, limit, offset int) ([]entity.AuditLog, int, error) {
+	query := repo.db.WithContext(ctx).Model(&entity.AuditLog{})
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	var auditLogs []entity.AuditLog
+	err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&auditLogs).Error
+	return auditLogs, int(count), err
+}
+
+// PurgeBefore hard deletes entries older than before. It is the only way
+// audit logs ever leave the table, so it bypasses the entity's delete guard.
+func (repo auditLogRepository) PurgeBefore(ctx context.Context, before time.Time) (int, error) {
+	result := repo.db.WithContext(ctx).
+		Session(&gorm.Session{SkipHooks: true}).
+		Unscoped().
+		Where("created_at < ?", before).
+		Delete(&entity.AuditLog{})
+	return int(result.RowsAffected), result.Error
+}
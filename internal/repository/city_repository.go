@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/geo"
 	"gorm.io/gorm"
 )
 
@@ -15,6 +16,14 @@ type CityRepository interface {
 	ById(context.Context, uint, *entity.City) *gorm.DB
 	Update(context.Context, *entity.City, map[string]any) error
 	Delete(context.Context, *entity.City) *gorm.DB
+	Trashed(context.Context, uint, int, int) ([]entity.City, int, error)
+	TrashedById(context.Context, uint, *entity.City) *gorm.DB
+	Restore(context.Context, *entity.City) error
+	Purge(context.Context, *entity.City) error
+	ByStates(context.Context, []uint) ([]entity.City, error)
+	CountDistricts(context.Context, uint) (int, error)
+	ReplaceTranslations(context.Context, *entity.City, map[string]string) error
+	InBox(context.Context, geo.Box) ([]entity.City, error)
 }
 
 type cityRepository struct {
@@ -29,11 +38,13 @@ func (repo cityRepository) Save(ctx context.Context, city *entity.City) *gorm.DB
 	return repo.db.WithContext(ctx).Save(city)
 }
 
+// List matches title against the cities' titles in every locale.
 func (repo cityRepository) List(ctx context.Context, title string, stateId int) ([]entity.City, *gorm.DB) {
 	var cities []entity.City
-	query := repo.db.WithContext(ctx).Preload("State").Model(&entity.City{}).Find(&cities)
+	query := repo.db.WithContext(ctx).Preload("State.Translations").Preload("Translations").Model(&entity.City{}).Find(&cities)
 	if stateId != 0 {
-		query.Where("title LIKE ? AND state_id = ?", "%"+title+"%", stateId).Find(&cities)
+		translated := repo.db.Model(&entity.CityTranslation{}).Select("city_id").Where("title LIKE ?", "%"+title+"%")
+		query.Where("(title LIKE ? OR id IN (?)) AND state_id = ?", "%"+title+"%", translated, stateId).Find(&cities)
 	}
 	return cities, query
 }
@@ -51,13 +62,92 @@ func (repo cityRepository) Count(ctx context.Context) (int, error) {
 }
 
 func (repo cityRepository) ById(ctx context.Context, id uint, city *entity.City) *gorm.DB {
-	return repo.db.WithContext(ctx).Preload("State").First(&city, "ID = ?", id)
+	return repo.db.WithContext(ctx).Preload("State.Translations").Preload("Translations").First(&city, "ID = ?", id)
 }
 
 func (repo cityRepository) Update(ctx context.Context, city *entity.City, newInfo map[string]any) error {
-	return repo.db.WithContext(ctx).Model(&city).Updates(newInfo).Error
+	return repo.db.WithContext(ctx).Model(&city).Updates(entity.WithTitleKey(newInfo)).Error
 }
 
 func (repo cityRepository) Delete(ctx context.Context, city *entity.City) *gorm.DB {
 	return repo.db.WithContext(ctx).Delete(city)
 }
+
+// Trashed lists the state's soft-deleted cities, most recently deleted first.
+func (repo cityRepository) Trashed(ctx context.Context, stateId uint, limit, offset int) ([]entity.City, int, error) {
+	query := repo.db.WithContext(ctx).Unscoped().Model(&entity.City{}).
+		Where("deleted_at IS NOT NULL AND state_id = ?", stateId)
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	var cities []entity.City
+	err := query.Order("deleted_at DESC").Limit(limit).Offset(offset).Find(&cities).Error
+	return cities, int(count), err
+}
+
+// TrashedById loads a deleted city, preloading its State only while the
+// state itself is not deleted.
+func (repo cityRepository) TrashedById(ctx context.Context, id uint, city *entity.City) *gorm.DB {
+	return repo.db.WithContext(ctx).Unscoped().Preload("State", "deleted_at IS NULL").Where("deleted_at IS NOT NULL").First(&city, "ID = ?", id)
+}
+
+func (repo cityRepository) Restore(ctx context.Context, city *entity.City) error {
+	return repo.db.WithContext(ctx).Unscoped().Model(city).Update("deleted_at", nil).Error
+}
+
+// Purge permanently removes the city along with its deleted districts and
+// their neighborhoods.
+func (repo cityRepository) Purge(ctx context.Context, city *entity.City) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		districts := tx.Unscoped().Model(&entity.District{}).Select("id").Where("city_id = ?", city.ID)
+		if err := tx.Unscoped().Where("district_id IN (?)", districts).Delete(&entity.Neighborhood{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("city_id = ?", city.ID).Delete(&entity.District{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(city).Error
+	})
+}
+
+// ByStates returns the cities of the given states, ordered by state and id.
+func (repo cityRepository) ByStates(ctx context.Context, stateIds []uint) ([]entity.City, error) {
+	var cities []entity.City
+	err := repo.db.WithContext(ctx).Where("state_id IN ?", stateIds).Order("state_id, id").Find(&cities).Error
+	return cities, err
+}
+
+// CountDistricts counts the city's districts that are not deleted.
+func (repo cityRepository) CountDistricts(ctx context.Context, id uint) (int, error) {
+	var count int64
+	err := repo.db.WithContext(ctx).Model(&entity.District{}).Where("city_id = ?", id).Count(&count).Error
+	return int(count), err
+}
+
+// ReplaceTranslations sets the city's translations to titles, removing the
+// ones left out.
+func (repo cityRepository) ReplaceTranslations(ctx context.Context, city *entity.City, titles map[string]string) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("city_id = ?", city.ID).Delete(&entity.CityTranslation{}).Error; err != nil {
+			return err
+		}
+		city.SetTranslations(titles)
+		if len(city.Translations) == 0 {
+			return nil
+		}
+		return tx.Create(&city.Translations).Error
+	})
+}
+
+// InBox returns the cities with coordinates inside box.
+func (repo cityRepository) InBox(ctx context.Context, box geo.Box) ([]entity.City, error) {
+	var cities []entity.City
+	query := repo.db.WithContext(ctx).Preload("State.Translations").Preload("Translations").
+		Where("latitude BETWEEN ? AND ? AND longitude IS NOT NULL", box.MinLat, box.MaxLat)
+	if !box.AllLongitudes {
+		query = query.Where("longitude BETWEEN ? AND ?", box.MinLng, box.MaxLng)
+	}
+	err := query.Find(&cities).Error
+	return cities, err
+}
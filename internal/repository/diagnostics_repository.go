@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+type DiagnosticsRepository interface {
+	Ping(context.Context) error
+	PoolStats() (sql.DBStats, error)
+}
+
+type diagnosticsRepository struct {
+	db *gorm.DB
+}
+
+func NewDiagnosticsRepository(db *gorm.DB) DiagnosticsRepository {
+	return diagnosticsRepository{db: db}
+}
+
+func (repo diagnosticsRepository) Ping(ctx context.Context) error {
+	sqlDB, err := repo.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+func (repo diagnosticsRepository) PoolStats() (sql.DBStats, error) {
+	sqlDB, err := repo.db.DB()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	return sqlDB.Stats(), nil
+}
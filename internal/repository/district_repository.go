@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type DistrictRepository interface {
+	Save(context.Context, *entity.District) *gorm.DB
+	List(context.Context, uint, string) *gorm.DB
+	Paginate(int, int, *gorm.DB) ([]entity.District, error)
+	Count(*gorm.DB) (int, error)
+	ById(context.Context, uint, *entity.District) *gorm.DB
+	Update(context.Context, *entity.District, map[string]any) error
+	Delete(context.Context, *entity.District) *gorm.DB
+	ByCities(context.Context, []uint) ([]entity.District, error)
+}
+
+type districtRepository struct {
+	db *gorm.DB
+}
+
+func NewDistrictRepository(db *gorm.DB) DistrictRepository {
+	return districtRepository{db: db}
+}
+
+func (repo districtRepository) Save(ctx context.Context, district *entity.District) *gorm.DB {
+	return repo.db.WithContext(ctx).Save(district)
+}
+
+// List returns the query for the city's districts whose title contains title.
+func (repo districtRepository) List(ctx context.Context, cityId uint, title string) *gorm.DB {
+	return repo.db.WithContext(ctx).Model(&entity.District{}).Preload("City.State").
+		Where("city_id = ? AND title LIKE ?", cityId, "%"+title+"%")
+}
+
+func (repo districtRepository) Paginate(limit, offset int, query *gorm.DB) ([]entity.District, error) {
+	var districts []entity.District
+	err := query.Order("id").Limit(limit).Offset(offset).Find(&districts).Error
+	return districts, err
+}
+
+func (repo districtRepository) Count(query *gorm.DB) (int, error) {
+	var count int64
+	err := query.Session(&gorm.Session{}).Count(&count).Error
+	return int(count), err
+}
+
+func (repo districtRepository) ById(ctx context.Context, id uint, district *entity.District) *gorm.DB {
+	return repo.db.WithContext(ctx).Preload("City.State").First(district, "id = ?", id)
+}
+
+func (repo districtRepository) Update(ctx context.Context, district *entity.District, newInfo map[string]any) error {
+	return repo.db.WithContext(ctx).Model(district).Updates(newInfo).Error
+}
+
+func (repo districtRepository) Delete(ctx context.Context, district *entity.District) *gorm.DB {
+	return repo.db.WithContext(ctx).Delete(district)
+}
+
+// ByCities returns the districts of the given cities, ordered by city and id.
+func (repo districtRepository) ByCities(ctx context.Context, cityIds []uint) ([]entity.District, error) {
+	var districts []entity.District
+	err := repo.db.WithContext(ctx).Where("city_id IN ?", cityIds).Order("city_id, id").Find(&districts).Error
+	return districts, err
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// IsTitleTaken reports whether err comes from a write that breaks the unique
+// index on the folded titles of states or cities. The connection has to be
+// opened with TranslateError so the driver's error is reported as
+// gorm.ErrDuplicatedKey.
+func IsTitleTaken(err error) bool {
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type GeoDataRepository interface {
+	States(context.Context) ([]entity.State, error)
+	Cities(context.Context) ([]entity.City, error)
+	Seed(context.Context, []entity.GeoSeed) error
+}
+
+type geoDataRepository struct {
+	db *gorm.DB
+}
+
+func NewGeoDataRepository(db *gorm.DB) GeoDataRepository {
+	return geoDataRepository{db: db}
+}
+
+func (repo geoDataRepository) States(ctx context.Context) ([]entity.State, error) {
+	var states []entity.State
+	err := repo.db.WithContext(ctx).Order("id").Find(&states).Error
+	return states, err
+}
+
+func (repo geoDataRepository) Cities(ctx context.Context) ([]entity.City, error) {
+	var cities []entity.City
+	err := repo.db.WithContext(ctx).Order("id").Find(&cities).Error
+	return cities, err
+}
+
+// Seed writes every state and city of seeds in one transaction. New cities
+// are attached to their state once it has an id.
+func (repo geoDataRepository) Seed(ctx context.Context, seeds []entity.GeoSeed) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, seed := range seeds {
+			if seed.Save {
+				if err := tx.Save(&seed.State).Error; err != nil {
+					return err
+				}
+			}
+			for _, city := range seed.Cities {
+				city.StateID = seed.State.ID
+				if err := tx.Omit("State").Save(&city).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ImpersonationRepository interface {
+	Save(context.Context, *entity.Impersonation) error
+	ById(context.Context, uint, *entity.Impersonation) *gorm.DB
+	End(context.Context, *entity.Impersonation, time.Time) error
+}
+
+type impersonationRepository struct {
+	db *gorm.DB
+}
+
+func NewImpersonationRepository(db *gorm.DB) ImpersonationRepository {
+	return impersonationRepository{db: db}
+}
+
+func (repo impersonationRepository) Save(ctx context.Context, impersonation *entity.Impersonation) error {
+	return repo.db.WithContext(ctx).Create(impersonation).Error
+}
+
+func (repo impersonationRepository) ById(ctx context.Context, id uint, impersonation *entity.Impersonation) *gorm.DB {
+	return repo.db.WithContext(ctx).First(impersonation, "id = ?", id)
+}
+
+func (repo impersonationRepository) End(ctx context.Context, impersonation *entity.Impersonation, endedAt time.Time) error {
+	impersonation.EndedAt = &endedAt
+	return repo.db.WithContext(ctx).Model(impersonation).Update("ended_at", endedAt).Error
+}
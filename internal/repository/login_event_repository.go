@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type LoginEventRepository interface {
+	Save(context.Context, *entity.LoginEvent) error
+	ById(context.Context, uint, *entity.LoginEvent) *gorm.DB
+	ByUserId(context.Context, uint, int, int) ([]entity.LoginEvent, int, error)
+	KnownDevices(context.Context, uint) ([]string, error)
+	KnownCountries(context.Context, uint) ([]string, error)
+	CountFailuresSince(context.Context, string, time.Time) (int, error)
+	Suspicious(context.Context, bool, int, int) ([]entity.LoginEvent, int, error)
+	Review(context.Context, *entity.LoginEvent, uint, string, time.Time) error
+}
+
+type loginEventRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginEventRepository(db *gorm.DB) LoginEventRepository {
+	return loginEventRepository{db: db}
+}
+
+func (repo loginEventRepository) Save(ctx context.Context, event *entity.LoginEvent) error {
+	return repo.db.WithContext(ctx).Create(event).Error
+}
+
+func (repo loginEventRepository) ById(ctx context.Context, id uint, event *entity.LoginEvent) *gorm.DB {
+	return repo.db.WithContext(ctx).First(event, "id = ?", id)
+}
+
+func (repo loginEventRepository) ByUserId(ctx context.Context, userId uint, limit, offset int) ([]entity.LoginEvent, int, error) {
+	query := repo.db.WithContext(ctx).Model(&entity.LoginEvent{}).Where("user_id = ?", userId)
+	return paginateLoginEvents(query, limit, offset)
+}
+
+// KnownDevices returns the device hashes the user has logged in from.
+func (repo loginEventRepository) KnownDevices(ctx context.Context, userId uint) ([]string, error) {
+	var devices []string
+	err := repo.db.WithContext(ctx).Model(&entity.LoginEvent{}).
+		Where("user_id = ? AND type = ?", userId, entity.LoginSucceededEvent).
+		Distinct().Pluck("device_hash", &devices).Error
+	return devices, err
+}
+
+// KnownCountries returns the countries the user has logged in from.
+func (repo loginEventRepository) KnownCountries(ctx context.Context, userId uint) ([]string, error) {
+	var countries []string
+	err := repo.db.WithContext(ctx).Model(&entity.LoginEvent{}).
+		Where("user_id = ? AND type = ? AND country <> ''", userId, entity.LoginSucceededEvent).
+		Distinct().Pluck("country", &countries).Error
+	return countries, err
+}
+
+func (repo loginEventRepository) CountFailuresSince(ctx context.Context, mobileNumber string, since time.Time) (int, error) {
+	var count int64
+	err := repo.db.WithContext(ctx).Model(&entity.LoginEvent{}).
+		Where("mobile_number = ? AND type = ? AND created_at >= ?", mobileNumber, entity.LoginFailedEvent, since).
+		Count(&count).Error
+	return int(count), err
+}
+
+func (repo loginEventRepository) Suspicious(ctx context.Context, reviewed bool, limit, offset int) ([]entity.LoginEvent, int, error) {
+	query := repo.db.WithContext(ctx).Model(&entity.LoginEvent{}).Where("anomalies <> ''")
+	if reviewed {
+		query = query.Where("reviewed_at IS NOT NULL")
+	} else {
+		query = query.Where("reviewed_at IS NULL")
+	}
+	return paginateLoginEvents(query, limit, offset)
+}
+
+func (repo loginEventRepository) Review(ctx context.Context, event *entity.LoginEvent, reviewerId uint, note string, reviewedAt time.Time) error {
+	event.ReviewedAt, event.ReviewedBy, event.ReviewNote = &reviewedAt, &reviewerId, note
+	return repo.db.WithContext(ctx).Model(event).Updates(map[string]any{
+		"reviewed_at": reviewedAt,
+		"reviewed_by": reviewerId,
+		"review_note": note,
+	}).Error
+}
+
+func paginateLoginEvents(query *gorm.DB, limit, offset int) ([]entity.LoginEvent, int, error) {
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	var events []entity.LoginEvent
+	err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&events).Error
+	return events, int(count), err
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type NeighborhoodRepository interface {
+	Save(context.Context, *entity.Neighborhood) *gorm.DB
+	List(context.Context, uint, string) *gorm.DB
+	Paginate(int, int, *gorm.DB) ([]entity.Neighborhood, error)
+	Count(*gorm.DB) (int, error)
+	ById(context.Context, uint, *entity.Neighborhood) *gorm.DB
+	Update(context.Context, *entity.Neighborhood, map[string]any) error
+	Delete(context.Context, *entity.Neighborhood) *gorm.DB
+	ByDistricts(context.Context, []uint) ([]entity.Neighborhood, error)
+}
+
+type neighborhoodRepository struct {
+	db *gorm.DB
+}
+
+func NewNeighborhoodRepository(db *gorm.DB) NeighborhoodRepository {
+	return neighborhoodRepository{db: db}
+}
+
+func (repo neighborhoodRepository) Save(ctx context.Context, neighborhood *entity.Neighborhood) *gorm.DB {
+	return repo.db.WithContext(ctx).Save(neighborhood)
+}
+
+// List returns the query for the district's neighborhoods whose title contains title.
+func (repo neighborhoodRepository) List(ctx context.Context, districtId uint, title string) *gorm.DB {
+	return repo.db.WithContext(ctx).Model(&entity.Neighborhood{}).Preload("District.City.State").
+		Where("district_id = ? AND title LIKE ?", districtId, "%"+title+"%")
+}
+
+func (repo neighborhoodRepository) Paginate(limit, offset int, query *gorm.DB) ([]entity.Neighborhood, error) {
+	var neighborhoods []entity.Neighborhood
+	err := query.Order("id").Limit(limit).Offset(offset).Find(&neighborhoods).Error
+	return neighborhoods, err
+}
+
+func (repo neighborhoodRepository) Count(query *gorm.DB) (int, error) {
+	var count int64
+	err := query.Session(&gorm.Session{}).Count(&count).Error
+	return int(count), err
+}
+
+func (repo neighborhoodRepository) ById(ctx context.Context, id uint, neighborhood *entity.Neighborhood) *gorm.DB {
+	return repo.db.WithContext(ctx).Preload("District.City.State").First(neighborhood, "id = ?", id)
+}
+
+func (repo neighborhoodRepository) Update(ctx context.Context, neighborhood *entity.Neighborhood, newInfo map[string]any) error {
+	return repo.db.WithContext(ctx).Model(neighborhood).Updates(newInfo).Error
+}
+
+func (repo neighborhoodRepository) Delete(ctx context.Context, neighborhood *entity.Neighborhood) *gorm.DB {
+	return repo.db.WithContext(ctx).Delete(neighborhood)
+}
+
+// ByDistricts returns the neighborhoods of the given districts, ordered by district and id.
+func (repo neighborhoodRepository) ByDistricts(ctx context.Context, districtIds []uint) ([]entity.Neighborhood, error) {
+	var neighborhoods []entity.Neighborhood
+	err := repo.db.WithContext(ctx).Where("district_id IN ?", districtIds).Order("district_id, id").Find(&neighborhoods).Error
+	return neighborhoods, err
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type OAuthClientRepository interface {
+	Save(context.Context, *entity.OAuthClient) error
+	List(context.Context) ([]entity.OAuthClient, error)
+	ById(context.Context, uint, *entity.OAuthClient) *gorm.DB
+	ByClientId(context.Context, string, *entity.OAuthClient) *gorm.DB
+	Delete(context.Context, *entity.OAuthClient) error
+}
+
+type oauthClientRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthClientRepository(db *gorm.DB) OAuthClientRepository {
+	return oauthClientRepository{db: db}
+}
+
+func (repo oauthClientRepository) Save(ctx context.Context, client *entity.OAuthClient) error {
+	return repo.db.WithContext(ctx).Create(client).Error
+}
+
+func (repo oauthClientRepository) List(ctx context.Context) ([]entity.OAuthClient, error) {
+	var clients []entity.OAuthClient
+	err := repo.db.WithContext(ctx).Order("id").Find(&clients).Error
+	return clients, err
+}
+
+func (repo oauthClientRepository) ById(ctx context.Context, id uint, client *entity.OAuthClient) *gorm.DB {
+	return repo.db.WithContext(ctx).First(client, id)
+}
+
+func (repo oauthClientRepository) ByClientId(ctx context.Context, clientId string, client *entity.OAuthClient) *gorm.DB {
+	return repo.db.WithContext(ctx).First(client, "client_id = ?", clientId)
+}
+
+func (repo oauthClientRepository) Delete(ctx context.Context, client *entity.OAuthClient) error {
+	return repo.db.WithContext(ctx).Delete(client).Error
+}
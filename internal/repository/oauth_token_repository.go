@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+// OAuthTokenRepository keeps the short-lived OAuth2 state in redis.
+// Authorization codes and refresh tokens are stored under their hash and
+// can only be taken once; revoked access tokens are remembered by their jti
+// until they would have expired anyway.
+type OAuthTokenRepository interface {
+	SaveCode(context.Context, entity.OAuthAuthorizationCode, time.Duration) error
+	TakeCode(context.Context, string) (entity.OAuthAuthorizationCode, error)
+	SaveRefreshToken(context.Context, entity.OAuthRefreshToken, time.Duration) error
+	GetRefreshToken(context.Context, string) (entity.OAuthRefreshToken, error)
+	TakeRefreshToken(context.Context, string) (entity.OAuthRefreshToken, error)
+	RevokeAccessToken(context.Context, string, time.Duration) error
+	IsAccessTokenRevoked(context.Context, string) (bool, error)
+}
+
+type oauthTokenRepository struct {
+	client *redis.Client
+}
+
+func NewOAuthTokenRepository(client *redis.Client) OAuthTokenRepository {
+	return oauthTokenRepository{client: client}
+}
+
+func (repo oauthTokenRepository) SaveCode(ctx context.Context, code entity.OAuthAuthorizationCode, ttl time.Duration) error {
+	return repo.save(ctx, codeKey(code.Code), code, ttl)
+}
+
+func (repo oauthTokenRepository) TakeCode(ctx context.Context, code string) (entity.OAuthAuthorizationCode, error) {
+	authorizationCode := entity.OAuthAuthorizationCode{}
+	err := repo.take(ctx, codeKey(code), &authorizationCode)
+	authorizationCode.Code = code
+	return authorizationCode, err
+}
+
+func (repo oauthTokenRepository) SaveRefreshToken(ctx context.Context, token entity.OAuthRefreshToken, ttl time.Duration) error {
+	return repo.save(ctx, refreshTokenKey(token.Token), token, ttl)
+}
+
+func (repo oauthTokenRepository) GetRefreshToken(ctx context.Context, token string) (entity.OAuthRefreshToken, error) {
+	refreshToken := entity.OAuthRefreshToken{}
+	value, err := repo.client.Get(ctx, refreshTokenKey(token)).Bytes()
+	if err != nil {
+		return refreshToken, err
+	}
+	err = json.Unmarshal(value, &refreshToken)
+	refreshToken.Token = token
+	return refreshToken, err
+}
+
+func (repo oauthTokenRepository) TakeRefreshToken(ctx context.Context, token string) (entity.OAuthRefreshToken, error) {
+	refreshToken := entity.OAuthRefreshToken{}
+	err := repo.take(ctx, refreshTokenKey(token), &refreshToken)
+	refreshToken.Token = token
+	return refreshToken, err
+}
+
+func (repo oauthTokenRepository) RevokeAccessToken(ctx context.Context, jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return repo.client.Set(ctx, revokedTokenKey(jti), 1, ttl).Err()
+}
+
+func (repo oauthTokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	count, err := repo.client.Exists(ctx, revokedTokenKey(jti)).Result()
+	return count > 0, err
+}
+
+func (repo oauthTokenRepository) save(ctx context.Context, key string, value any, ttl time.Duration) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return repo.client.Set(ctx, key, encoded, ttl).Err()
+}
+
+func (repo oauthTokenRepository) take(ctx context.Context, key string, value any) error {
+	encoded, err := repo.client.GetDel(ctx, key).Bytes()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, value)
+}
+
+func codeKey(code string) string {
+	return fmt.Sprintf("oauth:code:%v", entity.OAuthTokenHash(code))
+}
+
+func refreshTokenKey(token string) string {
+	return fmt.Sprintf("oauth:refresh:%v", entity.OAuthTokenHash(token))
+}
+
+func revokedTokenKey(jti string) string {
+	return fmt.Sprintf("oauth:revoked:%v", jti)
+}
@@ -2,16 +2,16 @@ package repository
 
 import (
 	"context"
-	"time"
 
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/validators"
 	"github.com/redis/go-redis/v9"
 )
 
 type OTPCodeRepository interface {
 	Save(context.Context, *entity.OTPCode) error
-	GetCode(context.Context, string) (string, error)
-	DeleteCode(context.Context, string) error
+	GetCode(context.Context, string, entity.OTPPurpose) (string, error)
+	DeleteCode(context.Context, string, entity.OTPPurpose) error
 }
 
 type otpCodeRepository struct {
@@ -25,16 +25,31 @@ func NewOTPCodeRepository(client *redis.Client) OTPCodeRepository {
 }
 
 func (otpRepo otpCodeRepository) Save(ctx context.Context, otpCode *entity.OTPCode) error {
-	err := otpRepo.client.Set(ctx, otpCode.MobileNumber, otpCode.Code, time.Minute).Err()
-	return err
+	mobileNumber, err := validators.NormalizeMobileNumber(otpCode.MobileNumber)
+	if err != nil {
+		return err
+	}
+	otpCode.MobileNumber = mobileNumber
+	hashedCode, err := otpCode.Hash()
+	if err != nil {
+		return err
+	}
+	return otpRepo.client.Set(ctx, otpCode.Key(), hashedCode, otpCode.TTL).Err()
 }
 
-func (otpRepo otpCodeRepository) GetCode(ctx context.Context, mobileNumber string) (string, error) {
-	stringCmd := otpRepo.client.Get(ctx, mobileNumber)
+func (otpRepo otpCodeRepository) GetCode(ctx context.Context, mobileNumber string, purpose entity.OTPPurpose) (string, error) {
+	mobileNumber, err := validators.NormalizeMobileNumber(mobileNumber)
+	if err != nil {
+		return "", err
+	}
+	stringCmd := otpRepo.client.Get(ctx, entity.OTPKey(mobileNumber, purpose))
 	return stringCmd.Result()
 }
 
-func (otpRepo otpCodeRepository) DeleteCode(ctx context.Context, mobileNumber string) error {
-	err := otpRepo.client.Del(ctx, mobileNumber)
-	return err.Err()
+func (otpRepo otpCodeRepository) DeleteCode(ctx context.Context, mobileNumber string, purpose entity.OTPPurpose) error {
+	mobileNumber, err := validators.NormalizeMobileNumber(mobileNumber)
+	if err != nil {
+		return err
+	}
+	return otpRepo.client.Del(ctx, entity.OTPKey(mobileNumber, purpose)).Err()
 }
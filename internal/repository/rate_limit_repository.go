@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RateLimitRepository interface {
+	Hit(context.Context, string, time.Duration) (int64, error)
+}
+
+type rateLimitRepository struct {
+	client *redis.Client
+}
+
+func NewRateLimitRepository(client *redis.Client) RateLimitRepository {
+	return rateLimitRepository{client: client}
+}
+
+// Hit counts one more request against key and returns the number of
+// requests made in the current window, which starts with the first hit.
+func (repo rateLimitRepository) Hit(ctx context.Context, key string, window time.Duration) (int64, error) {
+	count, err := repo.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		err = repo.client.Expire(ctx, key, window).Err()
+	}
+	return count, err
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+type RoleCacheRepository interface {
+	Get(context.Context, string, *entity.Role) (bool, error)
+	Set(context.Context, entity.Role, time.Duration) error
+	Invalidate(context.Context, string) error
+	Flush(context.Context) (int, error)
+}
+
+type roleCacheRepository struct {
+	client *redis.Client
+}
+
+func NewRoleCacheRepository(client *redis.Client) RoleCacheRepository {
+	return roleCacheRepository{client: client}
+}
+
+const roleCachePrefix = "role:"
+
+func roleCacheKey(name string) string {
+	return fmt.Sprintf("%v%v", roleCachePrefix, name)
+}
+
+// Get loads a cached role and reports whether it was found.
+func (repo roleCacheRepository) Get(ctx context.Context, name string, role *entity.Role) (bool, error) {
+	value, err := repo.client.Get(ctx, roleCacheKey(name)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(value, role)
+}
+
+func (repo roleCacheRepository) Set(ctx context.Context, role entity.Role, ttl time.Duration) error {
+	value, err := json.Marshal(role)
+	if err != nil {
+		return err
+	}
+	return repo.client.Set(ctx, roleCacheKey(role.Name), value, ttl).Err()
+}
+
+func (repo roleCacheRepository) Invalidate(ctx context.Context, name string) error {
+	return repo.client.Del(ctx, roleCacheKey(name)).Err()
+}
+
+// Flush removes every cached role and returns how many there were. The keys
+// are scanned for instead of flushing the database, which holds OTP codes,
+// rate limits and OAuth revocations as well.
+func (repo roleCacheRepository) Flush(ctx context.Context) (int, error) {
+	removed := 0
+	iter := repo.client.Scan(ctx, 0, roleCachePrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		if err := repo.client.Del(ctx, iter.Val()).Err(); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, iter.Err()
+}
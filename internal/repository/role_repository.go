@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	Save(context.Context, *entity.Role) error
+	List(context.Context) ([]entity.Role, error)
+	ById(context.Context, uint, *entity.Role) *gorm.DB
+	ByName(context.Context, string, *entity.Role) *gorm.DB
+	ReplacePermissions(context.Context, *entity.Role, []string) error
+	Update(context.Context, *entity.Role, map[string]any) error
+	Delete(context.Context, *entity.Role) error
+	CountUsers(context.Context, string) (int, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return roleRepository{db: db}
+}
+
+func (repo roleRepository) Save(ctx context.Context, role *entity.Role) error {
+	return repo.db.WithContext(ctx).Save(role).Error
+}
+
+func (repo roleRepository) List(ctx context.Context) ([]entity.Role, error) {
+	var roles []entity.Role
+	err := repo.db.WithContext(ctx).Preload("Permissions").Order("id").Find(&roles).Error
+	return roles, err
+}
+
+func (repo roleRepository) ById(ctx context.Context, id uint, role *entity.Role) *gorm.DB {
+	return repo.db.WithContext(ctx).Preload("Permissions").First(role, "id = ?", id)
+}
+
+func (repo roleRepository) ByName(ctx context.Context, name string, role *entity.Role) *gorm.DB {
+	return repo.db.WithContext(ctx).Preload("Permissions").First(role, "name = ?", name)
+}
+
+func (repo roleRepository) ReplacePermissions(ctx context.Context, role *entity.Role, permissions []string) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", role.ID).Delete(&entity.RolePermission{}).Error; err != nil {
+			return err
+		}
+		role.SetPermissions(permissions)
+		if len(role.Permissions) == 0 {
+			return nil
+		}
+		return tx.Create(&role.Permissions).Error
+	})
+}
+
+func (repo roleRepository) Delete(ctx context.Context, role *entity.Role) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", role.ID).Delete(&entity.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(role).Error
+	})
+}
+
+func (repo roleRepository) CountUsers(ctx context.Context, name string) (int, error) {
+	var count int64
+	err := repo.db.WithContext(ctx).Model(&entity.User{}).Where("role = ?", name).Count(&count).Error
+	return int(count), err
+}
+
+func (repo roleRepository) Update(ctx context.Context, role *entity.Role, values map[string]any) error {
+	return repo.db.WithContext(ctx).Model(role).Updates(values).Error
+}
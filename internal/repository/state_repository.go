@@ -15,6 +15,16 @@ type StateRepository interface {
 	ById(context.Context, uint, *entity.State) *gorm.DB
 	Update(context.Context, *entity.State, map[string]any) error
 	Delete(context.Context, *entity.State) *gorm.DB
+	Trashed(context.Context, int, int) ([]entity.State, int, error)
+	TrashedById(context.Context, uint, *entity.State) *gorm.DB
+	Restore(context.Context, *entity.State) error
+	Purge(context.Context, *entity.State) error
+	CountCities(context.Context, uint) (int, error)
+	After(context.Context, uint, int) ([]entity.State, error)
+	ReplaceTranslations(context.Context, *entity.State, map[string]string) error
+	CountActiveCities(context.Context, uint) (int, error)
+	DeleteCascade(context.Context, *entity.State) error
+	DeleteReassign(context.Context, *entity.State, uint) error
 }
 
 type stateRepository struct {
@@ -29,10 +39,12 @@ func (repo stateRepository) Save(ctx context.Context, state *entity.State) *gorm
 	return repo.db.WithContext(ctx).Save(state)
 }
 
+// StateList matches title against the states' titles in every locale.
 func (repo stateRepository) StateList(ctx context.Context, title string) ([]entity.State, *gorm.DB) {
 	var states []entity.State
-	query := repo.db.WithContext(ctx).Model(&entity.State{}).
-		Where("title LIKE ?", "%"+title+"%").
+	translated := repo.db.Model(&entity.StateTranslation{}).Select("state_id").Where("title LIKE ?", "%"+title+"%")
+	query := repo.db.WithContext(ctx).Model(&entity.State{}).Preload("Translations").
+		Where("title LIKE ? OR id IN (?)", "%"+title+"%", translated).
 		Find(&states)
 	return states, query
 }
@@ -50,13 +62,106 @@ func (repo stateRepository) Count(ctx context.Context) (int, error) {
 }
 
 func (repo stateRepository) ById(ctx context.Context, id uint, state *entity.State) *gorm.DB {
-	return repo.db.WithContext(ctx).First(&state, "ID = ?", id)
+	return repo.db.WithContext(ctx).Preload("Translations").First(&state, "ID = ?", id)
 }
 
 func (repo stateRepository) Update(ctx context.Context, state *entity.State, newInfo map[string]any) error {
-	return repo.db.WithContext(ctx).Model(&state).Updates(newInfo).Error
+	return repo.db.WithContext(ctx).Model(&state).Updates(entity.WithTitleKey(newInfo)).Error
 }
 
 func (repo stateRepository) Delete(ctx context.Context, state *entity.State) *gorm.DB {
 	return repo.db.WithContext(ctx).Delete(state)
 }
+
+// Trashed lists soft-deleted states, most recently deleted first.
+func (repo stateRepository) Trashed(ctx context.Context, limit, offset int) ([]entity.State, int, error) {
+	query := repo.db.WithContext(ctx).Unscoped().Model(&entity.State{}).Where("deleted_at IS NOT NULL")
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	var states []entity.State
+	err := query.Order("deleted_at DESC").Limit(limit).Offset(offset).Find(&states).Error
+	return states, int(count), err
+}
+
+func (repo stateRepository) TrashedById(ctx context.Context, id uint, state *entity.State) *gorm.DB {
+	return repo.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(&state, "ID = ?", id)
+}
+
+func (repo stateRepository) Restore(ctx context.Context, state *entity.State) error {
+	return repo.db.WithContext(ctx).Unscoped().Model(state).Update("deleted_at", nil).Error
+}
+
+func (repo stateRepository) Purge(ctx context.Context, state *entity.State) error {
+	return repo.db.WithContext(ctx).Unscoped().Delete(state).Error
+}
+
+// CountCities counts the cities referencing the state, trashed ones included.
+func (repo stateRepository) CountCities(ctx context.Context, id uint) (int, error) {
+	var count int64
+	err := repo.db.WithContext(ctx).Unscoped().Model(&entity.City{}).Where("state_id = ?", id).Count(&count).Error
+	return int(count), err
+}
+
+// After returns up to limit states with an id greater than id, in id order,
+// so all states can be walked in batches.
+func (repo stateRepository) After(ctx context.Context, id uint, limit int) ([]entity.State, error) {
+	var states []entity.State
+	err := repo.db.WithContext(ctx).Where("id > ?", id).Order("id").Limit(limit).Find(&states).Error
+	return states, err
+}
+
+// ReplaceTranslations sets the state's translations to titles, removing the
+// ones left out.
+func (repo stateRepository) ReplaceTranslations(ctx context.Context, state *entity.State, titles map[string]string) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("state_id = ?", state.ID).Delete(&entity.StateTranslation{}).Error; err != nil {
+			return err
+		}
+		state.SetTranslations(titles)
+		if len(state.Translations) == 0 {
+			return nil
+		}
+		return tx.Create(&state.Translations).Error
+	})
+}
+
+// CountActiveCities counts the state's cities that are not deleted.
+func (repo stateRepository) CountActiveCities(ctx context.Context, id uint) (int, error) {
+	var count int64
+	err := repo.db.WithContext(ctx).Model(&entity.City{}).Where("state_id = ?", id).Count(&count).Error
+	return int(count), err
+}
+
+// DeleteCascade deletes the state with its cities and their districts and
+// neighborhoods in one transaction.
+func (repo stateRepository) DeleteCascade(ctx context.Context, state *entity.State) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		cities := tx.Model(&entity.City{}).Select("id").Where("state_id = ?", state.ID)
+		districts := tx.Model(&entity.District{}).Select("id").Where("city_id IN (?)", cities)
+		if err := tx.Where("district_id IN (?)", districts).Delete(&entity.Neighborhood{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("city_id IN (?)", cities).Delete(&entity.District{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("state_id = ?", state.ID).Delete(&entity.City{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(state).Error
+	})
+}
+
+// DeleteReassign moves the state's live cities to the state with targetId
+// and deletes the state in one transaction. Trashed cities stay with the
+// deleted state, so restoring them can't clash with the target's titles.
+func (repo stateRepository) DeleteReassign(ctx context.Context, state *entity.State, targetId uint) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.City{}).Where("state_id = ?", state.ID).Update("state_id", targetId).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(state).Error
+	})
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type SuspensionRepository interface {
+	Suspend(context.Context, *entity.User, *entity.Suspension) error
+	Lift(context.Context, *entity.User, uint, string, time.Time) error
+	ByUserId(context.Context, uint) ([]entity.Suspension, error)
+}
+
+type suspensionRepository struct {
+	db *gorm.DB
+}
+
+func NewSuspensionRepository(db *gorm.DB) SuspensionRepository {
+	return suspensionRepository{db: db}
+}
+
+// Suspend records the suspension and copies it onto the user, lifting any
+// suspension that was still open.
+func (repo suspensionRepository) Suspend(ctx context.Context, user *entity.User, suspension *entity.Suspension) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := liftSuspensions(tx, user.ID, suspension.ActorID, "superseded", suspension.CreatedAt); err != nil {
+			return err
+		}
+		if err := tx.Create(suspension).Error; err != nil {
+			return err
+		}
+		user.SuspendedAt = &suspension.CreatedAt
+		user.SuspendedUntil = suspension.EndsAt
+		user.SuspensionReason = suspension.Reason
+		return tx.Model(user).Select("suspended_at", "suspended_until", "suspension_reason").Updates(user).Error
+	})
+}
+
+func (repo suspensionRepository) Lift(ctx context.Context, user *entity.User, actorId uint, notes string, liftedAt time.Time) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := liftSuspensions(tx, user.ID, actorId, notes, liftedAt); err != nil {
+			return err
+		}
+		user.SuspendedAt, user.SuspendedUntil, user.SuspensionReason = nil, nil, ""
+		return tx.Model(user).Select("suspended_at", "suspended_until", "suspension_reason").Updates(user).Error
+	})
+}
+
+func (repo suspensionRepository) ByUserId(ctx context.Context, userId uint) ([]entity.Suspension, error) {
+	var suspensions []entity.Suspension
+	err := repo.db.WithContext(ctx).Where("user_id = ?", userId).Order("id DESC").Find(&suspensions).Error
+	return suspensions, err
+}
+
+func liftSuspensions(tx *gorm.DB, userId, actorId uint, notes string, liftedAt time.Time) error {
+	return tx.Model(&entity.Suspension{}).
+		Where("user_id = ? AND lifted_at IS NULL", userId).
+		Updates(map[string]any{"lifted_at": liftedAt, "lifted_by": actorId, "lift_notes": notes}).Error
+}
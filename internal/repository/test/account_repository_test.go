@@ -0,0 +1,70 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAccountRepository_Anonymize(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	repo := repository.NewAccountRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	ctx := context.TODO()
+
+	user := entity.NewUser("Jane Doe", "09120000001", entity.UserRole)
+	assert.NoError(t, userRepo.Save(&user))
+	event := entity.NewLoginEvent(user.ID, user.MobileNumber, entity.LoginSucceededEvent, "2.180.0.1", "phone")
+	assert.NoError(t, repository.NewLoginEventRepository(db).Save(ctx, &event))
+	apiKey, _, err := entity.NewAPIKey("partner", &user.ID, "", []string{"states.read"}, 0, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, repository.NewAPIKeyRepository(db).Save(ctx, &apiKey))
+
+	now := time.Now()
+	assert.NoError(t, repo.ScheduleDeletion(ctx, &user, now, now.Add(time.Hour)))
+	due, err := repo.DueForAnonymization(ctx, now)
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+	due, err = repo.DueForAnonymization(ctx, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+
+	export, err := repo.Export(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane Doe", export.User.FullName)
+	assert.Len(t, export.LoginEvents, 1)
+	assert.Len(t, export.APIKeys, 1)
+	assert.Len(t, export.APIKeys[0].Scopes, 1)
+	assert.Nil(t, export.TwoFactor)
+
+	assert.NoError(t, repo.Anonymize(ctx, &due[0], now.Add(time.Hour)))
+	anonymized := entity.User{}
+	assert.NoError(t, db.Unscoped().First(&anonymized, user.ID).Error)
+	assert.Empty(t, anonymized.FullName)
+	assert.Equal(t, entity.AnonymizedMobileNumber(user.ID), anonymized.MobileNumber)
+	assert.NotNil(t, anonymized.AnonymizedAt)
+	assert.True(t, anonymized.DeletedAt.Valid)
+
+	scrubbed := entity.LoginEvent{}
+	assert.NoError(t, db.First(&scrubbed, event.ID).Error)
+	assert.Empty(t, scrubbed.MobileNumber)
+	assert.Empty(t, scrubbed.IP)
+	var count int64
+	db.Model(&entity.APIKey{}).Unscoped().Where("user_id = ?", user.ID).Count(&count)
+	assert.Zero(t, count)
+
+	due, err = repo.DueForAnonymization(ctx, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+}
@@ -0,0 +1,91 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAuditLogRepository_SaveAndByTarget(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	repo := repository.NewAuditLogRepository(db)
+
+	auditLog := entity.NewAuditLog(1, entity.ChangeMobileNumberAction, "user", 2, "details")
+	err = repo.Save(context.TODO(), &auditLog)
+	assert.NoError(t, err)
+	otherLog := entity.NewAuditLog(1, entity.ChangeMobileNumberAction, "user", 3, "details")
+	repo.Save(context.TODO(), &otherLog)
+
+	auditLogs, err := repo.ByTarget(context.TODO(), "user", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(auditLogs))
+	assert.Equal(t, auditLog.ID, auditLogs[0].ID)
+}
+
+func TestAuditLogRepository_List(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	repo := repository.NewAuditLogRepository(db)
+
+	for _, auditLog := range []entity.AuditLog{
+		entity.NewAuditLog(1, entity.UpdateUserAction, "user", 2, ""),
+		entity.NewAuditLog(1, entity.DeleteStateAction, "state", 5, ""),
+		entity.NewAuditLog(4, entity.UpdateUserAction, "user", 3, ""),
+	} {
+		assert.NoError(t, repo.Save(context.TODO(), &auditLog))
+	}
+
+	auditLogs, count, err := repo.List(context.TODO(), repository.AuditLogFilter{ActorID: 1}, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, entity.DeleteStateAction, auditLogs[0].Action)
+
+	_, count, _ = repo.List(context.TODO(), repository.AuditLogFilter{Action: entity.UpdateUserAction, TargetID: 3}, 10, 0)
+	assert.Equal(t, 1, count)
+
+	auditLogs, count, _ = repo.List(context.TODO(), repository.AuditLogFilter{TargetType: "user"}, 1, 1)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 1, len(auditLogs))
+
+	_, count, _ = repo.List(context.TODO(), repository.AuditLogFilter{From: time.Now().Add(time.Hour)}, 10, 0)
+	assert.Equal(t, 0, count)
+}
+
+func TestAuditLogRepository_Immutable(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	repo := repository.NewAuditLogRepository(db)
+
+	old := entity.NewAuditLog(1, entity.UpdateUserAction, "user", 2, "")
+	repo.Save(context.TODO(), &old)
+	db.Session(&gorm.Session{SkipHooks: true}).Model(&old).Update("created_at", time.Now().Add(-48*time.Hour))
+	recent := entity.NewAuditLog(1, entity.UpdateUserAction, "user", 2, "")
+	repo.Save(context.TODO(), &recent)
+
+	assert.ErrorIs(t, db.Model(&recent).Update("action", "tampered").Error, entity.ErrAuditLogImmutable)
+	assert.ErrorIs(t, db.Delete(&recent).Error, entity.ErrAuditLogImmutable)
+
+	purged, err := repo.PurgeBefore(context.TODO(), time.Now().Add(-24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	auditLogs, _ := repo.ByTarget(context.TODO(), "user", 2)
+	assert.Equal(t, 1, len(auditLogs))
+	assert.Equal(t, recent.ID, auditLogs[0].ID)
+}
@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestLoginEventRepository(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	repo := repository.NewLoginEventRepository(db)
+	ctx := context.TODO()
+	mobileNumber := "+989120000001"
+
+	success := entity.NewLoginEvent(1, mobileNumber, entity.LoginSucceededEvent, "2.180.0.1", "phone")
+	success.Country = "IR"
+	assert.NoError(t, repo.Save(ctx, &success))
+	failure := entity.NewLoginEvent(1, mobileNumber, entity.LoginFailedEvent, "8.8.8.8", "laptop")
+	failure.Country = "US"
+	failure.AddAnomaly(entity.FailureBurstAnomaly)
+	assert.NoError(t, repo.Save(ctx, &failure))
+	other := entity.NewLoginEvent(2, "+989120000002", entity.LoginSucceededEvent, "", "laptop")
+	assert.NoError(t, repo.Save(ctx, &other))
+
+	devices, err := repo.KnownDevices(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{success.DeviceHash}, devices)
+	countries, err := repo.KnownCountries(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"IR"}, countries)
+	failures, err := repo.CountFailuresSince(ctx, mobileNumber, time.Now().Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, failures)
+	failures, _ = repo.CountFailuresSince(ctx, mobileNumber, time.Now().Add(time.Minute))
+	assert.Zero(t, failures)
+
+	events, count, err := repo.ByUserId(ctx, 1, 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Len(t, events, 1)
+	assert.Equal(t, failure.ID, events[0].ID)
+
+	queue, count, err := repo.Suspicious(ctx, false, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, failure.ID, queue[0].ID)
+	assert.NoError(t, repo.Review(ctx, &queue[0], 9, "known traveller", time.Now()))
+	_, count, _ = repo.Suspicious(ctx, false, 10, 0)
+	assert.Zero(t, count)
+	reviewed, count, _ := repo.Suspicious(ctx, true, 10, 0)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "known traveller", reviewed[0].ReviewNote)
+}
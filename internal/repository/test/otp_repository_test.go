@@ -27,14 +27,19 @@ func TestOTPCodeRepository_Save(t *testing.T) {
 	mobileNumber := "09000000000"
 	otpRepo := repository.NewOTPCodeRepository(rdb)
 
-	otpCode := entity.NewOtpCode(mobileNumber)
+	otpCode, err := entity.NewOtpCode(mobileNumber, entity.LoginPurpose, 6, time.Minute)
+	assert.NoError(t, err)
 
 	err = otpRepo.Save(ctx, &otpCode)
 	assert.NoError(t, err, "Save should not return an error")
 
-	savedCode, err := mr.Get(otpCode.MobileNumber)
+	assert.Equal(t, "+989000000000", otpCode.MobileNumber, "mobile number must be normalized")
+	assert.False(t, mr.Exists(mobileNumber), "code must not be keyed by the raw mobile number")
+	savedCode, err := mr.Get(otpCode.Key())
 	assert.NoError(t, err, "Save should not return an error")
-	assert.Equal(t, otpCode.Code, savedCode, "The saved code should match the input")
+	assert.NotEqual(t, otpCode.Code, savedCode, "The plain code must not be stored")
+	assert.True(t, entity.MatchOTPCode(savedCode, otpCode.Code), "The saved hash should match the input")
+	assert.Equal(t, time.Minute, mr.TTL(otpCode.Key()))
 }
 
 func TestOTPCodeRepository_Get(t *testing.T) {
@@ -52,14 +57,18 @@ func TestOTPCodeRepository_Get(t *testing.T) {
 	mobileNumber := "09000000000"
 	otpRepo := repository.NewOTPCodeRepository(rdb)
 
-	otpCode := entity.NewOtpCode(mobileNumber)
+	otpCode, err := entity.NewOtpCode(mobileNumber, entity.LoginPurpose, 6, time.Minute)
+	assert.NoError(t, err)
 
 	err = otpRepo.Save(ctx, &otpCode)
 	assert.NoError(t, err, "Save should not return an error")
 
-	savedCode, err := mr.Get(otpCode.MobileNumber)
+	savedCode, err := mr.Get(otpCode.Key())
 	assert.NoError(t, err, "Save should not return an error")
-	code, err := otpRepo.GetCode(ctx, mobileNumber)
+	code, err := otpRepo.GetCode(ctx, mobileNumber, entity.LoginPurpose)
 	assert.NoError(t, err, "Get Code should not return an error")
 	assert.Equal(t, code, savedCode, "The saved code should match the input")
+
+	_, err = otpRepo.GetCode(ctx, mobileNumber, entity.ChangeMobilePurpose)
+	assert.ErrorIs(t, err, redis.Nil, "codes must not be shared between purposes")
 }
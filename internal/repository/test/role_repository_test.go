@@ -0,0 +1,77 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRoleRepository_DefaultRoles(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	repo := repository.NewRoleRepository(db)
+
+	roles, err := repo.List(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, len(entity.DefaultRolePermissions), len(roles))
+
+	admin := entity.Role{}
+	err = repo.ByName(context.TODO(), entity.AdminRole, &admin).Error
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, entity.AllPermissions, admin.PermissionNames())
+}
+
+func TestRoleRepository_ReplacePermissions(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	repo := repository.NewRoleRepository(db)
+
+	role := entity.NewRole("Editor", []string{entity.UsersReadPermission})
+	err = repo.Save(context.TODO(), &role)
+	assert.NoError(t, err)
+
+	err = repo.ReplacePermissions(context.TODO(), &role, []string{entity.SettingsStatesWritePermission, entity.SettingsCitiesWritePermission})
+	assert.NoError(t, err)
+
+	savedRole := entity.Role{}
+	repo.ById(context.TODO(), role.ID, &savedRole)
+	assert.ElementsMatch(t, []string{entity.SettingsStatesWritePermission, entity.SettingsCitiesWritePermission}, savedRole.PermissionNames())
+}
+
+func TestRoleRepository_DeleteAndCountUsers(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	repo := repository.NewRoleRepository(db)
+	userRepo := repository.NewUserRepository(db)
+
+	role := entity.NewRole("Editor", []string{entity.UsersReadPermission})
+	repo.Save(context.TODO(), &role)
+	user := entity.NewUser("something", "09900302020", role.Name)
+	userRepo.Save(&user)
+
+	count, err := repo.CountUsers(context.TODO(), role.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	err = repo.Delete(context.TODO(), &role)
+	assert.NoError(t, err)
+	var permissionCount int64
+	db.Model(&entity.RolePermission{}).Where("role_id = ?", role.ID).Count(&permissionCount)
+	assert.Zero(t, permissionCount)
+	assert.Error(t, repo.ById(context.TODO(), role.ID, &entity.Role{}).Error)
+}
@@ -0,0 +1,70 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSuspensionRepository(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	userRepo := repository.NewUserRepository(db)
+	repo := repository.NewSuspensionRepository(db)
+
+	active := entity.NewUser("active", "09120000001", entity.UserRole)
+	suspended := entity.NewUser("suspended", "09120000002", entity.UserRole)
+	banned := entity.NewUser("banned", "09120000003", entity.UserRole)
+	for _, user := range []*entity.User{&active, &suspended, &banned} {
+		assert.NoError(t, userRepo.Save(user))
+	}
+
+	endsAt := time.Now().Add(time.Hour)
+	suspension := entity.NewSuspension(suspended.ID, 1, entity.SpamSuspensionReason, "", &endsAt)
+	suspension.CreatedAt = time.Now()
+	assert.NoError(t, repo.Suspend(context.TODO(), &suspended, &suspension))
+	ban := entity.NewSuspension(banned.ID, 1, entity.FraudSuspensionReason, "stolen cards", nil)
+	ban.CreatedAt = time.Now()
+	assert.NoError(t, repo.Suspend(context.TODO(), &banned, &ban))
+
+	for status, name := range map[string]string{
+		entity.ActiveStatus:    "active",
+		entity.SuspendedStatus: "suspended",
+		entity.BannedStatus:    "banned",
+	} {
+		users, query := userRepo.UserList("", "", status)
+		assert.NoError(t, query.Error)
+		assert.Len(t, users, 1)
+		assert.Equal(t, name, users[0].FullName)
+	}
+	users, _ := userRepo.UserList("", "", "")
+	assert.Len(t, users, 3)
+
+	ban = entity.NewSuspension(suspended.ID, 2, entity.AbuseSuspensionReason, "", nil)
+	ban.CreatedAt = time.Now()
+	assert.NoError(t, repo.Suspend(context.TODO(), &suspended, &ban))
+	history, err := repo.ByUserId(context.TODO(), suspended.ID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Nil(t, history[0].LiftedAt)
+	assert.NotNil(t, history[1].LiftedAt)
+
+	assert.NoError(t, repo.Lift(context.TODO(), &suspended, 2, "appeal accepted", time.Now()))
+	reloaded := entity.User{}
+	userRepo.ById(suspended.ID, &reloaded)
+	assert.Nil(t, reloaded.SuspendedAt)
+	assert.Empty(t, reloaded.SuspensionReason)
+	history, _ = repo.ByUserId(context.TODO(), suspended.ID)
+	assert.NotNil(t, history[0].LiftedAt)
+	assert.Equal(t, "appeal accepted", history[0].LiftNotes)
+}
@@ -0,0 +1,41 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestTwoFactorRepository_SaveReplacesEnrollment(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	repo := repository.NewTwoFactorRepository(db)
+
+	first, _, _ := entity.NewTwoFactor(1)
+	assert.NoError(t, repo.Save(context.TODO(), &first))
+	second, _, _ := entity.NewTwoFactor(1)
+	assert.NoError(t, repo.Save(context.TODO(), &second))
+
+	saved := entity.TwoFactor{}
+	err = repo.ByUserId(context.TODO(), 1, &saved).Error
+	assert.NoError(t, err)
+	assert.Equal(t, second.Secret, saved.Secret)
+	assert.Len(t, saved.RecoveryCodes, 10)
+	var recoveryCodeCount int64
+	db.Model(&entity.RecoveryCode{}).Count(&recoveryCodeCount)
+	assert.Equal(t, int64(10), recoveryCodeCount)
+
+	assert.NoError(t, repo.DeleteByUserId(context.TODO(), 1))
+	assert.Error(t, repo.ByUserId(context.TODO(), 1, &entity.TwoFactor{}).Error)
+	db.Model(&entity.RecoveryCode{}).Count(&recoveryCodeCount)
+	assert.Zero(t, recoveryCodeCount)
+}
@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
@@ -21,6 +22,10 @@ func TestUserRepository_Save(t *testing.T) {
 	user := entity.NewUser("something", "09000000000", entity.UserRole)
 	err = repo.Save(&user)
 	assert.NoErrorf(t, err, "can not save user, error: %v", err)
+	assert.Equal(t, "+989000000000", user.MobileNumber)
+
+	invalidUser := entity.NewUser("something", "1234", entity.UserRole)
+	assert.Error(t, repo.Save(&invalidUser))
 
 	var savedUser entity.User
 	result := db.First(&savedUser, user.ID)
@@ -46,8 +51,14 @@ func TestUserRepository_ByMobileNumber(t *testing.T) {
 	assert.NoError(t, err, "failed to retrieve user: %v", result.Error)
 	assert.Equal(t, user.ID, savedUser.ID)
 
+	var sameUser entity.User
+	result = repo.ByMobileNumber("+989000000000", &sameUser)
+	assert.NoError(t, result.Error)
+	assert.Equal(t, user.ID, sameUser.ID)
+
 	var wrongUser entity.User
-	repo.ByMobileNumber("wrong", &wrongUser)
+	result = repo.ByMobileNumber("wrong", &wrongUser)
+	assert.Error(t, result.Error)
 	assert.Zero(t, wrongUser.ID)
 }
 
@@ -122,19 +133,19 @@ func TestUserRepository_AllUser(t *testing.T) {
 	var count int64
 	db.Model(&entity.User{}).Count(&count)
 
-	users, query := repo.UserList("", "")
+	users, query := repo.UserList("", "", "")
 	assert.NoError(t, query.Error)
 	assert.Equal(t, int(count), len(users))
 
-	users, query = repo.UserList("09900302023", "some")
+	users, query = repo.UserList("09900302023", "some", "")
 	assert.NoError(t, query.Error)
 	assert.Equal(t, len(users), 1)
 
-	users, query = repo.UserList("09900302023", "")
+	users, query = repo.UserList("09900302023", "", "")
 	assert.NoError(t, query.Error)
 	assert.Equal(t, len(users), 1)
 
-	users, query = repo.UserList("", "something else")
+	users, query = repo.UserList("", "something else", "")
 	assert.NoError(t, query.Error)
 	assert.Equal(t, len(users), 1)
 }
@@ -155,7 +166,7 @@ func TestUserRepository_PaginateUser(t *testing.T) {
 	var count int64
 	db.Model(&entity.User{}).Count(&count)
 
-	_, query := repo.UserList("", "")
+	_, query := repo.UserList("", "", "")
 	assert.NoError(t, query.Error)
 
 	users, err := repo.PaginateUsers(10, 0, query)
@@ -180,3 +191,38 @@ func TestUserRepository_Count(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, count, 1)
 }
+
+func TestUserRepository_Trash(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	repo := repository.NewUserRepository(db)
+	user := entity.NewUser("something", "09000000000", entity.UserRole)
+	repo.Save(&user)
+	twoFactor, _, _ := entity.NewTwoFactor(user.ID)
+	db.Create(&twoFactor)
+	assert.NoError(t, repo.Delete(&user).Error)
+
+	// the number is free again once its owner is deleted
+	registered := entity.NewUser("", "09000000000", entity.UserRole)
+	assert.NoError(t, repo.Save(&registered))
+
+	users, count, err := repo.Trashed(10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, user.ID, users[0].ID)
+	assert.Error(t, repo.TrashedById(registered.ID, &entity.User{}).Error)
+
+	trashed := entity.User{}
+	assert.NoError(t, repo.TrashedById(user.ID, &trashed).Error)
+	assert.Error(t, repo.Restore(&trashed))
+
+	assert.NoError(t, repo.Purge(context.TODO(), &trashed))
+	assert.Error(t, db.Unscoped().First(&entity.User{}, user.ID).Error)
+	var recoveryCodes int64
+	db.Model(&entity.RecoveryCode{}).Count(&recoveryCodes)
+	assert.Equal(t, int64(0), recoveryCodes)
+	assert.Error(t, db.Unscoped().First(&entity.TwoFactor{}, twoFactor.ID).Error)
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type TwoFactorRepository interface {
+	Save(context.Context, *entity.TwoFactor) error
+	ByUserId(context.Context, uint, *entity.TwoFactor) *gorm.DB
+	Confirm(context.Context, *entity.TwoFactor, int64, time.Time) error
+	UpdateLastUsedStep(context.Context, *entity.TwoFactor, int64) error
+	UseRecoveryCode(context.Context, *entity.RecoveryCode, time.Time) error
+	DeleteByUserId(context.Context, uint) error
+}
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return twoFactorRepository{db: db}
+}
+
+// Save replaces any previous enrollment of the user.
+func (repo twoFactorRepository) Save(ctx context.Context, twoFactor *entity.TwoFactor) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteTwoFactor(tx, twoFactor.UserID); err != nil {
+			return err
+		}
+		return tx.Create(twoFactor).Error
+	})
+}
+
+func (repo twoFactorRepository) ByUserId(ctx context.Context, userId uint, twoFactor *entity.TwoFactor) *gorm.DB {
+	return repo.db.WithContext(ctx).Preload("RecoveryCodes").First(twoFactor, "user_id = ?", userId)
+}
+
+func (repo twoFactorRepository) Confirm(ctx context.Context, twoFactor *entity.TwoFactor, step int64, confirmedAt time.Time) error {
+	twoFactor.ConfirmedAt = &confirmedAt
+	twoFactor.LastUsedStep = step
+	return repo.db.WithContext(ctx).Model(twoFactor).Updates(map[string]any{
+		"confirmed_at":   confirmedAt,
+		"last_used_step": step,
+	}).Error
+}
+
+func (repo twoFactorRepository) UpdateLastUsedStep(ctx context.Context, twoFactor *entity.TwoFactor, step int64) error {
+	twoFactor.LastUsedStep = step
+	return repo.db.WithContext(ctx).Model(twoFactor).Update("last_used_step", step).Error
+}
+
+func (repo twoFactorRepository) UseRecoveryCode(ctx context.Context, recoveryCode *entity.RecoveryCode, usedAt time.Time) error {
+	recoveryCode.UsedAt = &usedAt
+	return repo.db.WithContext(ctx).Model(recoveryCode).Update("used_at", usedAt).Error
+}
+
+func (repo twoFactorRepository) DeleteByUserId(ctx context.Context, userId uint) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return deleteTwoFactor(tx, userId)
+	})
+}
+
+func deleteTwoFactor(tx *gorm.DB, userId uint) error {
+	var ids []uint
+	if err := tx.Unscoped().Model(&entity.TwoFactor{}).Where("user_id = ?", userId).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Where("two_factor_id IN ?", ids).Delete(&entity.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("id IN ?", ids).Delete(&entity.TwoFactor{}).Error
+}
@@ -1,7 +1,12 @@
 package repository
 
 import (
+	"context"
+
+	"time"
+
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/validators"
 	"gorm.io/gorm"
 )
 
@@ -11,9 +16,14 @@ type UserRepository interface {
 	ById(uint, *entity.User) *gorm.DB
 	Update(*entity.User, map[string]any) error
 	Delete(*entity.User) *gorm.DB
-	UserList(string, string) ([]entity.User, *gorm.DB)
+	UserList(string, string, string) ([]entity.User, *gorm.DB)
 	PaginateUsers(int, int, *gorm.DB) ([]entity.User, error)
 	Count() (int, error)
+	CountActiveByRole(string) (int, error)
+	Trashed(int, int) ([]entity.User, int, error)
+	TrashedById(uint, *entity.User) *gorm.DB
+	Restore(*entity.User) error
+	Purge(context.Context, *entity.User) error
 }
 
 type userRepository struct {
@@ -25,11 +35,23 @@ func NewUserRepository(db *gorm.DB) *userRepository {
 }
 
 func (userRepo userRepository) Save(user *entity.User) error {
+	number, err := validators.ParseMobileNumber(user.MobileNumber)
+	if err != nil {
+		return err
+	}
+	user.MobileNumber = number.E164()
+	user.Country = number.Region
 	return userRepo.db.Save(user).Error
 }
 
 func (userRepo userRepository) ByMobileNumber(value string, user *entity.User) *gorm.DB {
-	return userRepo.db.First(&user, "mobile_number = ?", value)
+	mobileNumber, err := validators.NormalizeMobileNumber(value)
+	if err != nil {
+		db := userRepo.db.Session(&gorm.Session{})
+		db.AddError(err)
+		return db
+	}
+	return userRepo.db.First(&user, "mobile_number = ?", mobileNumber)
 }
 
 func (userRepo userRepository) ById(id uint, user *entity.User) *gorm.DB {
@@ -44,14 +66,28 @@ func (userRepo userRepository) Delete(user *entity.User) *gorm.DB {
 	return userRepo.db.Delete(user)
 }
 
-func (userRepo userRepository) UserList(mobileNumber, fullName string) ([]entity.User, *gorm.DB) {
+func (userRepo userRepository) UserList(mobileNumber, fullName, status string) ([]entity.User, *gorm.DB) {
 	var users []entity.User
 	query := userRepo.db.Model(&entity.User{}).
-		Where("mobile_number LIKE ? AND full_name LIKE ?", "%"+mobileNumber+"%", "%"+fullName+"%").
-		Find(&users)
+		Where("mobile_number LIKE ? AND full_name LIKE ?", "%"+validators.MobileNumberSearchTerm(mobileNumber)+"%", "%"+fullName+"%")
+	query = filterByStatus(query, status, time.Now()).Find(&users)
 	return users, query
 }
 
+// filterByStatus mirrors entity.User.Status; an empty status matches every
+// user.
+func filterByStatus(query *gorm.DB, status string, now time.Time) *gorm.DB {
+	switch status {
+	case entity.ActiveStatus:
+		return query.Where("suspended_at IS NULL OR (suspended_until IS NOT NULL AND suspended_until <= ?)", now)
+	case entity.SuspendedStatus:
+		return query.Where("suspended_at IS NOT NULL AND suspended_until > ?", now)
+	case entity.BannedStatus:
+		return query.Where("suspended_at IS NOT NULL AND suspended_until IS NULL")
+	}
+	return query
+}
+
 func (userRepo userRepository) PaginateUsers(limit, offset int, query *gorm.DB) ([]entity.User, error) {
 	var users []entity.User
 	err := query.Limit(limit).Offset(offset).Find(&users).Error
@@ -63,3 +99,65 @@ func (userRepo userRepository) Count() (int, error) {
 	err := userRepo.db.Model(&entity.User{}).Count(&count).Error
 	return int(count), err
 }
+
+// CountActiveByRole counts the users holding role who are not suspended.
+func (userRepo userRepository) CountActiveByRole(role string) (int, error) {
+	var count int64
+	query := userRepo.db.Model(&entity.User{}).Where("role = ?", role)
+	err := filterByStatus(query, entity.ActiveStatus, time.Now()).Count(&count).Error
+	return int(count), err
+}
+
+// Trashed lists soft-deleted users, most recently deleted first.
+func (userRepo userRepository) Trashed(limit, offset int) ([]entity.User, int, error) {
+	query := userRepo.db.Unscoped().Model(&entity.User{}).Where("deleted_at IS NOT NULL")
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []entity.User
+	err := query.Order("deleted_at DESC").Limit(limit).Offset(offset).Find(&users).Error
+	return users, int(count), err
+}
+
+func (userRepo userRepository) TrashedById(id uint, user *entity.User) *gorm.DB {
+	return userRepo.db.Unscoped().Where("deleted_at IS NOT NULL").First(&user, "ID = ?", id)
+}
+
+func (userRepo userRepository) Restore(user *entity.User) error {
+	return userRepo.db.Unscoped().Model(user).Update("deleted_at", nil).Error
+}
+
+// Purge removes the user for good along with the credentials that would
+// otherwise outlive the account. Like Anonymize, it scrubs the personal data
+// the login and audit history keep about the user.
+func (userRepo userRepository) Purge(ctx context.Context, user *entity.User) error {
+	return userRepo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteCredentials(tx, user.ID); err != nil {
+			return err
+		}
+		if err := scrubLoginEvents(tx, user.ID); err != nil {
+			return err
+		}
+		if err := redactAuditLogs(tx, user.ID); err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(user).Error
+	})
+}
+
+// deleteCredentials removes the user's two-factor enrollment and API keys.
+func deleteCredentials(tx *gorm.DB, userId uint) error {
+	twoFactors := tx.Unscoped().Model(&entity.TwoFactor{}).Select("id").Where("user_id = ?", userId)
+	if err := tx.Where("two_factor_id IN (?)", twoFactors).Delete(&entity.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("user_id = ?", userId).Delete(&entity.TwoFactor{}).Error; err != nil {
+		return err
+	}
+	apiKeys := tx.Unscoped().Model(&entity.APIKey{}).Select("id").Where("user_id = ?", userId)
+	if err := tx.Where("api_key_id IN (?)", apiKeys).Delete(&entity.APIKeyScope{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("user_id = ?", userId).Delete(&entity.APIKey{}).Error
+}
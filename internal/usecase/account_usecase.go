@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+)
+
+// AccountUseCase handles users leaving: deleting their account after a grace
+// period, and handing them a copy of their data.
+type AccountUseCase struct {
+	Repo  repository.AccountRepository
+	Users repository.UserRepository
+	Conf  config.AccountDeletion
+	Audit AuditHook
+}
+
+func NewAccountUseCase(repo repository.AccountRepository, users repository.UserRepository, conf config.AccountDeletion) AccountUseCase {
+	return AccountUseCase{Repo: repo, Users: users, Conf: conf}
+}
+
+// WithAudit returns a copy of the use case that reports its mutations to hook.
+func (u AccountUseCase) WithAudit(hook AuditHook) AccountUseCase {
+	u.Audit = hook
+	return u
+}
+
+// CanDelete returns the user if their account may be deleted.
+func (u AccountUseCase) CanDelete(userId uint) (entity.User, error) {
+	userUseCase := NewUserUseCase(u.Users)
+	user, err := userUseCase.GetUserById(userId)
+	if err != nil {
+		return entity.User{}, err
+	}
+	return user, userUseCase.EnsureAdminRemains(user)
+}
+
+// ScheduleDeletion starts the grace period after which the account is
+// anonymized.
+func (u AccountUseCase) ScheduleDeletion(ctx context.Context, userId uint) (entity.User, error) {
+	user, err := u.CanDelete(userId)
+	if err != nil {
+		return entity.User{}, err
+	}
+	before := user
+	now := time.Now()
+	if err = u.Repo.ScheduleDeletion(ctx, &user, now, now.Add(u.Conf.GracePeriod)); err != nil {
+		return entity.User{}, err
+	}
+	return user, audit(ctx, u.Audit, entity.RequestDeletionAction, "user", user.ID, before, user)
+}
+
+// CancelDeletion keeps the account when its deletion is still pending and
+// reports whether there was anything to cancel.
+func (u AccountUseCase) CancelDeletion(ctx context.Context, user *entity.User) (bool, error) {
+	if !user.IsDeletionPending() {
+		return false, nil
+	}
+	before := *user
+	if err := u.Repo.CancelDeletion(ctx, user); err != nil {
+		return false, err
+	}
+	return true, audit(ctx, u.Audit, entity.CancelDeletionAction, "user", user.ID, before, *user)
+}
+
+// AnonymizeDue anonymizes every account whose grace period ended by now and
+// returns how many there were.
+func (u AccountUseCase) AnonymizeDue(ctx context.Context, now time.Time) (int, error) {
+	users, err := u.Repo.DueForAnonymization(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	for i, user := range users {
+		if err = u.Repo.Anonymize(ctx, &user, now); err != nil {
+			return i, err
+		}
+		// the erased values must not live on in the audit log
+		if err = audit(ctx, u.Audit, entity.AnonymizeUserAction, "user", user.ID, nil, nil); err != nil {
+			return i + 1, err
+		}
+	}
+	return len(users), nil
+}
+
+// Export gathers everything stored about the user.
+func (u AccountUseCase) Export(ctx context.Context, userId uint) (entity.AccountExport, error) {
+	return u.Repo.Export(ctx, userId)
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+const apiKeyRateLimitWindow = time.Minute
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+	ErrAPIKeyOwner    = errors.New("an api key belongs to either a user or an organization")
+	ErrAPIKeyNoScopes = errors.New("an api key needs at least one scope")
+	ErrAPIKeyExpiry   = errors.New("expiry date must be in the future")
+)
+
+type APIKeyUseCase struct {
+	Repo      repository.APIKeyRepository
+	RateLimit repository.RateLimitRepository
+}
+
+func NewAPIKeyUseCase(repo repository.APIKeyRepository, rateLimit repository.RateLimitRepository) APIKeyUseCase {
+	return APIKeyUseCase{Repo: repo, RateLimit: rateLimit}
+}
+
+// Issue stores a new key and returns it along with its plain text form.
+func (u APIKeyUseCase) Issue(ctx context.Context, name string, userId *uint, organization string, scopes []string, rateLimit int, expiresAt *time.Time) (entity.APIKey, string, error) {
+	if (userId == nil) == (organization == "") {
+		return entity.APIKey{}, "", ErrAPIKeyOwner
+	}
+	if len(scopes) == 0 {
+		return entity.APIKey{}, "", ErrAPIKeyNoScopes
+	}
+	for _, scope := range scopes {
+		if !entity.IsPermissionValid(scope) {
+			return entity.APIKey{}, "", fmt.Errorf("invalid scope %q", scope)
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return entity.APIKey{}, "", ErrAPIKeyExpiry
+	}
+	apiKey, rawKey, err := entity.NewAPIKey(name, userId, organization, scopes, rateLimit, expiresAt)
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+	err = u.Repo.Save(ctx, &apiKey)
+	return apiKey, rawKey, err
+}
+
+func (u APIKeyUseCase) List(ctx context.Context) ([]entity.APIKey, error) {
+	return u.Repo.List(ctx)
+}
+
+func (u APIKeyUseCase) ById(ctx context.Context, id uint) (entity.APIKey, error) {
+	apiKey := new(entity.APIKey)
+	err := u.Repo.ById(ctx, id, apiKey).Error
+	return *apiKey, err
+}
+
+func (u APIKeyUseCase) Revoke(ctx context.Context, id uint) (entity.APIKey, error) {
+	apiKey, err := u.ById(ctx, id)
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+	if apiKey.RevokedAt != nil {
+		return apiKey, nil
+	}
+	err = u.Repo.Revoke(ctx, &apiKey, time.Now())
+	return apiKey, err
+}
+
+// Authenticate resolves a plain text key to an active key and records its
+// use. Unknown, revoked and expired keys all yield ErrInvalidAPIKey.
+func (u APIKeyUseCase) Authenticate(ctx context.Context, rawKey string) (entity.APIKey, error) {
+	prefix, ok := entity.APIKeyPrefix(rawKey)
+	if !ok {
+		return entity.APIKey{}, ErrInvalidAPIKey
+	}
+	apiKey := entity.APIKey{}
+	err := u.Repo.ByPrefix(ctx, prefix, &apiKey).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.APIKey{}, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+	now := time.Now()
+	if !apiKey.Matches(rawKey) || !apiKey.IsActive(now) {
+		return entity.APIKey{}, ErrInvalidAPIKey
+	}
+	err = u.Repo.TouchLastUsed(ctx, &apiKey, now)
+	return apiKey, err
+}
+
+// Allow counts a request made with apiKey and returns how many requests are
+// left in the current minute, or false once the key's limit is used up.
+func (u APIKeyUseCase) Allow(ctx context.Context, apiKey entity.APIKey) (int64, bool, error) {
+	count, err := u.RateLimit.Hit(ctx, fmt.Sprintf("rate:api_key:%v", apiKey.ID), apiKeyRateLimitWindow)
+	if err != nil {
+		return 0, false, err
+	}
+	remaining := int64(apiKey.RateLimit) - count
+	if remaining < 0 {
+		return 0, false, nil
+	}
+	return remaining, true, nil
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+)
+
+// AuditHook is called by use cases after each mutation they make, so every
+// caller gets audited without handlers having to remember it. before is nil
+// for creations and after is nil for deletions.
+type AuditHook interface {
+	Audit(ctx context.Context, action, targetType string, targetId uint, before, after any) error
+}
+
+// fields every entity carries that say nothing about what changed
+var unauditedFields = []string{"CreatedAt", "UpdatedAt", "DeletedAt"}
+
+type AuditLogUseCase struct {
+	Repo repository.AuditLogRepository
+}
+
+func NewAuditLogUseCase(repo repository.AuditLogRepository) AuditLogUseCase {
+	return AuditLogUseCase{Repo: repo}
+}
+
+func (u AuditLogUseCase) Record(ctx context.Context, actorId uint, action, targetType string, targetId uint, details string) error {
+	auditLog := entity.NewAuditLog(actorId, action, targetType, targetId, details)
+	auditLog.IP, auditLog.RequestID = requestMetadata(ctx)
+	return u.Repo.Save(ctx, &auditLog)
+}
+
+// Audit records a mutation on behalf of whoever ctx says is making the
+// request, keeping only the fields that differ between before and after.
+func (u AuditLogUseCase) Audit(ctx context.Context, action, targetType string, targetId uint, before, after any) error {
+	changes, err := auditChanges(before, after)
+	if err != nil {
+		return err
+	}
+	auditLog := entity.NewAuditLog(auditActor(ctx), action, targetType, targetId, "")
+	auditLog.Changes = changes
+	auditLog.IP, auditLog.RequestID = requestMetadata(ctx)
+	return u.Repo.Save(ctx, &auditLog)
+}
+
+func (u AuditLogUseCase) ByTarget(ctx context.Context, targetType string, targetId uint) ([]entity.AuditLog, error) {
+	return u.Repo.ByTarget(ctx, targetType, targetId)
+}
+
+func (u AuditLogUseCase) List(ctx context.Context, filter repository.AuditLogFilter, pageNumber, pageSize int) ([]entity.AuditLog, int, error) {
+	return u.Repo.List(ctx, filter, pageSize, (pageNumber-1)*pageSize)
+}
+
+// Purge removes entries older than retention and reports how many went.
+func (u AuditLogUseCase) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	return u.Repo.PurgeBefore(ctx, time.Now().Add(-retention))
+}
+
+// auditActor is the user behind the request. Staff impersonating someone are
+// recorded as themselves.
+func auditActor(ctx context.Context) uint {
+	if actorId, ok := ctx.Value("actorId").(uint); ok && actorId != 0 {
+		return actorId
+	}
+	userId, _ := ctx.Value("userId").(uint)
+	return userId
+}
+
+func requestMetadata(ctx context.Context) (ip, requestId string) {
+	if request, ok := ctx.(interface{ ClientIP() string }); ok {
+		ip = request.ClientIP()
+	}
+	requestId, _ = ctx.Value("requestId").(string)
+	return ip, requestId
+}
+
+// auditChanges encodes the fields that differ between before and after as
+// {"Field": {"before": ..., "after": ...}}.
+func auditChanges(before, after any) (string, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return "", err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return "", err
+	}
+	changes := map[string]map[string]any{}
+	for field, value := range beforeFields {
+		if !reflect.DeepEqual(value, afterFields[field]) {
+			changes[field] = map[string]any{"before": value, "after": afterFields[field]}
+		}
+	}
+	for field, value := range afterFields {
+		if _, seen := beforeFields[field]; !seen {
+			changes[field] = map[string]any{"before": nil, "after": value}
+		}
+	}
+	encoded, err := json.Marshal(changes)
+	return string(encoded), err
+}
+
+func auditFields(value any) (map[string]any, error) {
+	fields := map[string]any{}
+	if value == nil {
+		return fields, nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	for _, field := range unauditedFields {
+		delete(fields, field)
+	}
+	// loaded associations are audited on their own
+	for field, fieldValue := range fields {
+		if _, nested := fieldValue.(map[string]any); nested {
+			delete(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+func audit(ctx context.Context, hook AuditHook, action, targetType string, targetId uint, before, after any) error {
+	if hook == nil {
+		return nil
+	}
+	return hook.Audit(ctx, action, targetType, targetId, before, after)
+}
@@ -3,25 +3,51 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/geo"
 	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrStateDeleted     = errors.New("the city's state is deleted, restore it first")
+	ErrCityHasDistricts = errors.New("the city still has districts, delete them first")
+	ErrInvalidRadius    = fmt.Errorf("radius must be greater than 0 and at most %v kilometers", MaxNearbyRadius)
+	ErrCityTitleTaken   = errors.New("the state already has a city with this title")
+)
+
+// MaxNearbyRadius bounds nearby searches, in kilometers.
+const MaxNearbyRadius = 1000.0
+
 type CityUseCase struct {
-	Repo repository.CityRepository
+	Repo  repository.CityRepository
+	Audit AuditHook
 }
 
 func NewCityUseCase(repo repository.CityRepository) CityUseCase {
 	return CityUseCase{Repo: repo}
 }
 
-func (u CityUseCase) Create(context context.Context, title string, state entity.State) (entity.City, error) {
+// WithAudit returns a copy of the use case that reports its mutations to hook.
+func (u CityUseCase) WithAudit(hook AuditHook) CityUseCase {
+	u.Audit = hook
+	return u
+}
+
+func (u CityUseCase) Create(context context.Context, title string, state entity.State, location entity.Location) (entity.City, error) {
+	if err := location.Validate(); err != nil {
+		return entity.City{}, err
+	}
 	city := entity.NewCity(title, state)
-	err := u.Repo.Save(context, &city).Error
-	return city, err
+	city.Location = location
+	if err := titleTaken(u.Repo.Save(context, &city).Error, ErrCityTitleTaken); err != nil {
+		return city, err
+	}
+	return city, audit(context, u.Audit, entity.CreateCityAction, "city", city.ID, nil, city)
 }
 
 func (u CityUseCase) CityList(ctx context.Context, page, size, stateId int, title string) ([]entity.City, error) {
@@ -55,8 +81,15 @@ func (u CityUseCase) Update(ctx context.Context, id uint, newInfo map[string]any
 	if err != nil {
 		return entity.City{}, err
 	}
-	err = u.Repo.Update(ctx, &city, newInfo)
-	return city, err
+	before := city
+	if err = titleTaken(u.Repo.Update(ctx, &city, newInfo), ErrCityTitleTaken); err != nil {
+		return city, err
+	}
+	after, err := u.ById(ctx, id)
+	if err != nil {
+		return city, err
+	}
+	return after, audit(ctx, u.Audit, entity.UpdateCityAction, "city", id, before, after)
 }
 
 func (u CityUseCase) DeleteById(ctx context.Context, id uint) error {
@@ -64,5 +97,104 @@ func (u CityUseCase) DeleteById(ctx context.Context, id uint) error {
 	if err != nil {
 		return err
 	}
-	return u.Repo.Delete(ctx, &city).Error
+	if err = u.Repo.Delete(ctx, &city).Error; err != nil {
+		return err
+	}
+	return audit(ctx, u.Audit, entity.DeleteCityAction, "city", id, city, nil)
+}
+
+func (u CityUseCase) Trashed(ctx context.Context, stateId uint, pageNumber, pageSize int) ([]entity.City, int, error) {
+	return u.Repo.Trashed(ctx, stateId, pageSize, utils.PageToOffset(pageNumber, pageSize))
+}
+
+// TrashedById returns a deleted city. Its State is left empty when the state
+// is deleted as well.
+func (u CityUseCase) TrashedById(ctx context.Context, id uint) (entity.City, error) {
+	city := entity.City{}
+	err := u.Repo.TrashedById(ctx, id, &city).Error
+	return city, err
+}
+
+func (u CityUseCase) Restore(ctx context.Context, id uint) (entity.City, error) {
+	city, err := u.TrashedById(ctx, id)
+	if err != nil {
+		return entity.City{}, err
+	}
+	if city.State.ID == 0 {
+		return entity.City{}, ErrStateDeleted
+	}
+	if err = titleTaken(u.Repo.Restore(ctx, &city), ErrCityTitleTaken); err != nil {
+		return entity.City{}, err
+	}
+	restored, err := u.ById(ctx, id)
+	if err != nil {
+		return entity.City{}, err
+	}
+	return restored, audit(ctx, u.Audit, entity.RestoreCityAction, "city", id, nil, restored)
+}
+
+// Purge permanently removes a city that is already in the trash. Its
+// districts have to go first since they still reference it.
+func (u CityUseCase) Purge(ctx context.Context, id uint) error {
+	city, err := u.TrashedById(ctx, id)
+	if err != nil {
+		return err
+	}
+	count, err := u.Repo.CountDistricts(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCityHasDistricts
+	}
+	if err = u.Repo.Purge(ctx, &city); err != nil {
+		return err
+	}
+	return audit(ctx, u.Audit, entity.PurgeCityAction, "city", id, city, nil)
+}
+
+// UpdateTranslations replaces the city's translations with titles, keyed by
+// locale. The title in the default locale is the city's own title.
+func (u CityUseCase) UpdateTranslations(ctx context.Context, id uint, titles map[string]string) (entity.City, error) {
+	if err := validateTranslations(titles); err != nil {
+		return entity.City{}, err
+	}
+	city, err := u.ById(ctx, id)
+	if err != nil {
+		return entity.City{}, err
+	}
+	before := city.TranslationTitles()
+	if err = u.Repo.ReplaceTranslations(ctx, &city, titles); err != nil {
+		return city, err
+	}
+	return city, audit(ctx, u.Audit, entity.TranslateCityAction, "city", id, before, city.TranslationTitles())
+}
+
+// Nearby returns up to limit cities within radius kilometers of the point,
+// nearest first. The database narrows the cities down to a bounding box
+// and their exact distances are computed here, so the search needs no math
+// functions from the database.
+func (u CityUseCase) Nearby(ctx context.Context, lat, lng, radius float64, limit int) ([]entity.NearbyCity, error) {
+	if err := geo.ValidatePoint(lat, lng); err != nil {
+		return nil, err
+	}
+	if !(radius > 0 && radius <= MaxNearbyRadius) {
+		return nil, ErrInvalidRadius
+	}
+	cities, err := u.Repo.InBox(ctx, geo.BoundingBox(lat, lng, radius))
+	if err != nil {
+		return nil, err
+	}
+	nearby := []entity.NearbyCity{}
+	for _, city := range cities {
+		distance := geo.Haversine(lat, lng, *city.Latitude, *city.Longitude)
+		if distance <= radius {
+			nearby = append(nearby, entity.NearbyCity{City: city, Distance: distance})
+		}
+	}
+	sort.SliceStable(nearby, func(i, j int) bool { return nearby[i].Distance < nearby[j].Distance })
+	if len(nearby) > limit {
+		nearby = nearby[:limit]
+	}
+	return nearby, nil
 }
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+)
+
+var ErrDatabaseUnreachable = errors.New("database is unreachable")
+
+type DiagnosticsUseCase struct {
+	Repo repository.DiagnosticsRepository
+	Conf config.DB
+}
+
+func NewDiagnosticsUseCase(repo repository.DiagnosticsRepository, conf config.DB) DiagnosticsUseCase {
+	return DiagnosticsUseCase{Repo: repo, Conf: conf}
+}
+
+// Database pings the database and reports the state of the connection pool.
+func (u DiagnosticsUseCase) Database(ctx context.Context) (entity.DatabaseDiagnostics, error) {
+	diagnostics := entity.DatabaseDiagnostics{
+		MaxIdleConns:     u.Conf.MaxIdleConns,
+		ConnMaxLifetime:  u.Conf.ConnMaxLifetime,
+		ConnMaxIdleTime:  u.Conf.ConnMaxIdleTime,
+		StatementTimeout: u.Conf.StatementTimeout,
+	}
+	started := time.Now()
+	if err := u.Repo.Ping(ctx); err != nil {
+		return diagnostics, errors.Join(ErrDatabaseUnreachable, err)
+	}
+	diagnostics.PingLatency = time.Since(started)
+	stats, err := u.Repo.PoolStats()
+	if err != nil {
+		return diagnostics, err
+	}
+	diagnostics.Stats = stats
+	return diagnostics, nil
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+)
+
+type DistrictUseCase struct {
+	Repo  repository.DistrictRepository
+	Audit AuditHook
+}
+
+func NewDistrictUseCase(repo repository.DistrictRepository) DistrictUseCase {
+	return DistrictUseCase{Repo: repo}
+}
+
+// WithAudit returns a copy of the use case that reports its mutations to hook.
+func (u DistrictUseCase) WithAudit(hook AuditHook) DistrictUseCase {
+	u.Audit = hook
+	return u
+}
+
+func (u DistrictUseCase) Create(ctx context.Context, title string, city entity.City) (entity.District, error) {
+	district := entity.NewDistrict(title, city)
+	if err := u.Repo.Save(ctx, &district).Error; err != nil {
+		return district, err
+	}
+	return district, audit(ctx, u.Audit, entity.CreateDistrictAction, "district", district.ID, nil, district)
+}
+
+// List returns a page of the city's districts whose title contains title and
+// how many districts match in total.
+func (u DistrictUseCase) List(ctx context.Context, cityId uint, page, size int, title string) ([]entity.District, int, error) {
+	query := u.Repo.List(ctx, cityId, title)
+	count, err := u.Repo.Count(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	districts, err := u.Repo.Paginate(size, utils.PageToOffset(page, size), query)
+	return districts, count, err
+}
+
+func (u DistrictUseCase) ById(ctx context.Context, id uint) (entity.District, error) {
+	district := new(entity.District)
+	err := u.Repo.ById(ctx, id, district).Error
+	return *district, err
+}
+
+func (u DistrictUseCase) Update(ctx context.Context, id uint, newInfo map[string]any) (entity.District, error) {
+	district, err := u.ById(ctx, id)
+	if err != nil {
+		return entity.District{}, err
+	}
+	before := district
+	if err = u.Repo.Update(ctx, &district, newInfo); err != nil {
+		return district, err
+	}
+	after, err := u.ById(ctx, id)
+	if err != nil {
+		return district, err
+	}
+	return after, audit(ctx, u.Audit, entity.UpdateDistrictAction, "district", id, before, after)
+}
+
+func (u DistrictUseCase) DeleteById(ctx context.Context, id uint) error {
+	district, err := u.ById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = u.Repo.Delete(ctx, &district).Error; err != nil {
+		return err
+	}
+	return audit(ctx, u.Audit, entity.DeleteDistrictAction, "district", id, district, nil)
+}
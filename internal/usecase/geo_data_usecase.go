@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+)
+
+type GeoDataUseCase struct {
+	Repo    repository.GeoDataRepository
+	Dataset entity.GeoDataset
+	Audit   AuditHook
+}
+
+func NewGeoDataUseCase(repo repository.GeoDataRepository, dataset entity.GeoDataset) GeoDataUseCase {
+	return GeoDataUseCase{Repo: repo, Dataset: dataset}
+}
+
+// WithAudit returns a copy of the use case that reports its mutations to hook.
+func (u GeoDataUseCase) WithAudit(hook AuditHook) GeoDataUseCase {
+	u.Audit = hook
+	return u
+}
+
+// Diff compares the states and cities in the database with the dataset.
+func (u GeoDataUseCase) Diff(ctx context.Context) (entity.GeoDiff, error) {
+	diff, _, err := u.plan(ctx)
+	return diff, err
+}
+
+// Seed creates the states and cities of the dataset that are missing, with
+// their English titles as translations, and links the ones matching by title
+// to it. Titles edited in the database are kept, so seeding again changes
+// nothing.
+func (u GeoDataUseCase) Seed(ctx context.Context) (entity.GeoSeedResult, error) {
+	_, seeds, err := u.plan(ctx)
+	if err != nil {
+		return entity.GeoSeedResult{}, err
+	}
+	result := entity.GeoSeedResult{Version: u.Dataset.Version}
+	for _, seed := range seeds {
+		if seed.State.ID == 0 {
+			result.CreatedStates++
+		} else if seed.Save {
+			result.LinkedStates++
+		}
+		for _, city := range seed.Cities {
+			if city.ID == 0 {
+				result.CreatedCities++
+			} else {
+				result.LinkedCities++
+			}
+		}
+	}
+	if len(seeds) == 0 {
+		return result, nil
+	}
+	if err = u.Repo.Seed(ctx, seeds); err != nil {
+		return entity.GeoSeedResult{}, err
+	}
+	return result, audit(ctx, u.Audit, entity.SeedGeoDataAction, "geo_dataset", 0, nil, result)
+}
+
+// plan matches the database against the dataset, returning the differences
+// and what seeding has to write.
+func (u GeoDataUseCase) plan(ctx context.Context) (entity.GeoDiff, []entity.GeoSeed, error) {
+	states, err := u.Repo.States(ctx)
+	if err != nil {
+		return entity.GeoDiff{}, nil, err
+	}
+	cities, err := u.Repo.Cities(ctx)
+	if err != nil {
+		return entity.GeoDiff{}, nil, err
+	}
+	diff := entity.GeoDiff{Version: u.Dataset.Version}
+	seeds := []entity.GeoSeed{}
+
+	stateMatcher := newGeoMatcher(len(states))
+	for i := range states {
+		stateMatcher.add(i, states[i].Code, states[i].Title)
+	}
+	citiesByState := map[uint][]int{}
+	for i := range cities {
+		citiesByState[cities[i].StateID] = append(citiesByState[cities[i].StateID], i)
+	}
+	matchedCities := map[int]bool{}
+
+	for _, datasetState := range u.Dataset.States {
+		index, linked := stateMatcher.match(datasetState.Code, datasetState.Title)
+		if index < 0 {
+			diff.MissingStates = append(diff.MissingStates, datasetState)
+			seed := entity.GeoSeed{State: entity.State{Title: datasetState.Title, Code: datasetState.Code}, Save: true}
+			seed.State.SetTranslations(map[string]string{entity.EnglishLocale: datasetState.EnglishTitle})
+			for _, datasetCity := range datasetState.Cities {
+				diff.MissingCities = append(diff.MissingCities, entity.MissingCity{StateCode: datasetState.Code, City: datasetCity})
+				seed.Cities = append(seed.Cities, newDatasetCity(datasetCity))
+			}
+			seeds = append(seeds, seed)
+			continue
+		}
+		diff.MatchedStates++
+		state := states[index]
+		seed := entity.GeoSeed{State: state}
+		if change, changed := geoChange(state.ID, state.Code, state.Title, datasetState.Code, datasetState.Title, linked); changed {
+			diff.ChangedStates = append(diff.ChangedStates, change)
+			if !linked {
+				seed.State.Code = datasetState.Code
+				seed.Save = true
+			}
+		}
+
+		cityMatcher := newGeoMatcher(len(citiesByState[state.ID]))
+		for _, i := range citiesByState[state.ID] {
+			cityMatcher.add(i, cities[i].Code, cities[i].Title)
+		}
+		for _, datasetCity := range datasetState.Cities {
+			index, linked := cityMatcher.match(datasetCity.Code, datasetCity.Title)
+			if index < 0 {
+				diff.MissingCities = append(diff.MissingCities, entity.MissingCity{StateCode: datasetState.Code, City: datasetCity})
+				seed.Cities = append(seed.Cities, newDatasetCity(datasetCity))
+				continue
+			}
+			diff.MatchedCities++
+			matchedCities[index] = true
+			city := cities[index]
+			if change, changed := geoChange(city.ID, city.Code, city.Title, datasetCity.Code, datasetCity.Title, linked); changed {
+				diff.ChangedCities = append(diff.ChangedCities, change)
+				if !linked {
+					city.Code = datasetCity.Code
+					seed.Cities = append(seed.Cities, city)
+				}
+			}
+		}
+		if seed.Save || len(seed.Cities) > 0 {
+			seeds = append(seeds, seed)
+		}
+	}
+
+	for i, state := range states {
+		if !stateMatcher.matched[i] {
+			diff.UnknownStates = append(diff.UnknownStates, state)
+		}
+	}
+	for i, city := range cities {
+		if !matchedCities[i] {
+			diff.UnknownCities = append(diff.UnknownCities, city)
+		}
+	}
+	return diff, seeds, nil
+}
+
+// newDatasetCity is a city created from the dataset, with its English title
+// as a translation.
+func newDatasetCity(datasetCity entity.DatasetCity) entity.City {
+	city := entity.City{Title: datasetCity.Title, Code: datasetCity.Code}
+	city.SetTranslations(map[string]string{entity.EnglishLocale: datasetCity.EnglishTitle})
+	return city
+}
+
+func geoChange(id uint, code, title, datasetCode, datasetTitle string, linked bool) (entity.GeoChange, bool) {
+	change := entity.GeoChange{ID: id, Code: datasetCode, Title: title, DatasetTitle: datasetTitle, Unlinked: !linked}
+	return change, !linked || utils.FoldTitle(title) != utils.FoldTitle(datasetTitle)
+}
+
+// geoMatcher finds the records matching a dataset entry, by code first and
+// then by title among the records without a code.
+type geoMatcher struct {
+	byCode  map[string]int
+	byTitle map[string]int
+	matched map[int]bool
+}
+
+func newGeoMatcher(size int) geoMatcher {
+	return geoMatcher{
+		byCode:  make(map[string]int, size),
+		byTitle: make(map[string]int, size),
+		matched: make(map[int]bool, size),
+	}
+}
+
+func (m geoMatcher) add(index int, code, title string) {
+	if code != "" {
+		m.byCode[code] = index
+		return
+	}
+	if _, ok := m.byTitle[utils.FoldTitle(title)]; !ok {
+		m.byTitle[utils.FoldTitle(title)] = index
+	}
+}
+
+// match returns the index of the record for the entry, or -1, and whether
+// the record is already linked to it by code.
+func (m geoMatcher) match(code, title string) (int, bool) {
+	if index, ok := m.byCode[code]; ok {
+		m.matched[index] = true
+		return index, true
+	}
+	if index, ok := m.byTitle[utils.FoldTitle(title)]; ok && !m.matched[index] {
+		m.matched[index] = true
+		return index, false
+	}
+	return -1, false
+}
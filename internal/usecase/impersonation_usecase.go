@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+)
+
+var (
+	ErrImpersonateSelf    = errors.New("you can not impersonate yourself")
+	ErrImpersonationEnded = errors.New("impersonation session has ended")
+)
+
+type ImpersonationUseCase struct {
+	Repo repository.ImpersonationRepository
+	Conf config.Impersonation
+	// SecretKey signs the impersonation tokens
+	SecretKey string
+}
+
+func NewImpersonationUseCase(repo repository.ImpersonationRepository, conf config.Impersonation, secretKey string) ImpersonationUseCase {
+	return ImpersonationUseCase{Repo: repo, Conf: conf, SecretKey: secretKey}
+}
+
+// Start opens a session and returns it with the token that acts as subject.
+func (u ImpersonationUseCase) Start(ctx context.Context, actorId uint, subject entity.User, reason string) (entity.Impersonation, string, error) {
+	if actorId == subject.ID {
+		return entity.Impersonation{}, "", ErrImpersonateSelf
+	}
+	impersonation := entity.NewImpersonation(actorId, subject.ID, reason, u.Conf.TTL)
+	if err := u.Repo.Save(ctx, &impersonation); err != nil {
+		return entity.Impersonation{}, "", err
+	}
+	token, err := utils.GenerateImpersonationToken(u.SecretKey, impersonation.ID, actorId, subject.ID, subject.MobileNumber, subject.Role, impersonation.ExpiresAt)
+	return impersonation, token, err
+}
+
+// Active returns the session when it is still running.
+func (u ImpersonationUseCase) Active(ctx context.Context, id uint) (entity.Impersonation, error) {
+	impersonation := entity.Impersonation{}
+	if err := u.Repo.ById(ctx, id, &impersonation).Error; err != nil {
+		return entity.Impersonation{}, err
+	}
+	if !impersonation.IsActive(time.Now()) {
+		return entity.Impersonation{}, ErrImpersonationEnded
+	}
+	return impersonation, nil
+}
+
+func (u ImpersonationUseCase) End(ctx context.Context, id uint) (entity.Impersonation, error) {
+	impersonation, err := u.Active(ctx, id)
+	if err != nil {
+		return entity.Impersonation{}, err
+	}
+	err = u.Repo.End(ctx, &impersonation, time.Now())
+	return impersonation, err
+}
@@ -0,0 +1,354 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+)
+
+var (
+	ErrInvalidImport     = errors.New("the file has invalid rows, nothing was imported")
+	ErrInvalidImportMode = errors.New("mode must be title or code")
+)
+
+// LocationUseCase moves states and cities in and out of the application in
+// bulk and reads the whole hierarchy under a state.
+type LocationUseCase struct {
+	States        repository.StateRepository
+	Cities        repository.CityRepository
+	Districts     repository.DistrictRepository
+	Neighborhoods repository.NeighborhoodRepository
+	GeoData       repository.GeoDataRepository
+	Audit         AuditHook
+}
+
+func NewLocationUseCase(states repository.StateRepository, cities repository.CityRepository, districts repository.DistrictRepository, neighborhoods repository.NeighborhoodRepository, geoData repository.GeoDataRepository) LocationUseCase {
+	return LocationUseCase{States: states, Cities: cities, Districts: districts, Neighborhoods: neighborhoods, GeoData: geoData}
+}
+
+// WithAudit returns a copy of the use case that reports its mutations to hook.
+func (u LocationUseCase) WithAudit(hook AuditHook) LocationUseCase {
+	u.Audit = hook
+	return u
+}
+
+// Export calls write with every state and its cities, loading batchSize
+// states at a time.
+func (u LocationUseCase) Export(ctx context.Context, batchSize int, write func(entity.State, []entity.City) error) error {
+	var lastId uint
+	for {
+		states, err := u.States.After(ctx, lastId, batchSize)
+		if err != nil || len(states) == 0 {
+			return err
+		}
+		ids := make([]uint, 0, len(states))
+		for _, state := range states {
+			ids = append(ids, state.ID)
+		}
+		cities, err := u.Cities.ByStates(ctx, ids)
+		if err != nil {
+			return err
+		}
+		citiesByState := map[uint][]entity.City{}
+		for _, city := range cities {
+			citiesByState[city.StateID] = append(citiesByState[city.StateID], city)
+		}
+		for _, state := range states {
+			if err = write(state, citiesByState[state.ID]); err != nil {
+				return err
+			}
+		}
+		lastId = states[len(states)-1].ID
+	}
+}
+
+// Tree returns the state with all of its cities, districts and
+// neighborhoods, loading each level in a single query.
+func (u LocationUseCase) Tree(ctx context.Context, stateId uint) (entity.LocationTree, error) {
+	state := entity.State{}
+	if err := u.States.ById(ctx, stateId, &state).Error; err != nil {
+		return entity.LocationTree{}, err
+	}
+	tree := entity.LocationTree{State: state, Cities: []entity.CityTree{}}
+	cities, err := u.Cities.ByStates(ctx, []uint{stateId})
+	if err != nil || len(cities) == 0 {
+		return tree, err
+	}
+	cityIds := make([]uint, 0, len(cities))
+	for _, city := range cities {
+		cityIds = append(cityIds, city.ID)
+	}
+	districts, err := u.Districts.ByCities(ctx, cityIds)
+	if err != nil {
+		return entity.LocationTree{}, err
+	}
+	districtIds := make([]uint, 0, len(districts))
+	for _, district := range districts {
+		districtIds = append(districtIds, district.ID)
+	}
+	neighborhoods := []entity.Neighborhood{}
+	if len(districtIds) > 0 {
+		if neighborhoods, err = u.Neighborhoods.ByDistricts(ctx, districtIds); err != nil {
+			return entity.LocationTree{}, err
+		}
+	}
+
+	neighborhoodsByDistrict := map[uint][]entity.Neighborhood{}
+	for _, neighborhood := range neighborhoods {
+		neighborhoodsByDistrict[neighborhood.DistrictID] = append(neighborhoodsByDistrict[neighborhood.DistrictID], neighborhood)
+	}
+	districtsByCity := map[uint][]entity.DistrictTree{}
+	for _, district := range districts {
+		districtsByCity[district.CityID] = append(districtsByCity[district.CityID], entity.DistrictTree{
+			District:      district,
+			Neighborhoods: neighborhoodsByDistrict[district.ID],
+		})
+	}
+	for _, city := range cities {
+		tree.Cities = append(tree.Cities, entity.CityTree{City: city, Districts: districtsByCity[city.ID]})
+	}
+	return tree, nil
+}
+
+// importState is a state of the file with the cities listed under it.
+type importState struct {
+	row    entity.LocationRow
+	cities []entity.LocationRow
+}
+
+// Import upserts the states and cities of rows, matching existing ones by
+// title or by code depending on mode, in a single transaction. Every row is
+// validated first; when any is invalid the result lists the errors,
+// ErrInvalidImport is returned and nothing is written. A dry run reports
+// what would change without writing.
+func (u LocationUseCase) Import(ctx context.Context, rows []entity.LocationRow, mode string, dryRun bool) (entity.ImportResult, error) {
+	if mode != entity.ImportByTitle && mode != entity.ImportByCode {
+		return entity.ImportResult{}, ErrInvalidImportMode
+	}
+	result := entity.ImportResult{DryRun: dryRun, Rows: len(rows)}
+	states := groupImportRows(rows, mode, &result)
+	if len(result.Errors) > 0 {
+		return result, ErrInvalidImport
+	}
+	seeds, err := u.planImport(ctx, states, mode, &result)
+	if err != nil {
+		return entity.ImportResult{}, err
+	}
+	if len(result.Errors) > 0 {
+		return result, ErrInvalidImport
+	}
+	if dryRun || len(seeds) == 0 {
+		return result, nil
+	}
+	if err = u.GeoData.Seed(ctx, seeds); err != nil {
+		return entity.ImportResult{}, err
+	}
+	return result, audit(ctx, u.Audit, entity.ImportLocationsAction, "location", 0, nil, result)
+}
+
+func importKey(mode, code, title string) string {
+	if mode == entity.ImportByCode {
+		return code
+	}
+	return utils.FoldTitle(title)
+}
+
+// groupImportRows validates the rows on their own and against each other
+// and groups the cities under their states, in the order of the file.
+func groupImportRows(rows []entity.LocationRow, mode string, result *entity.ImportResult) []*importState {
+	fail := func(row entity.LocationRow, field, message string) {
+		result.Errors = append(result.Errors, entity.ImportError{Location: row.Location, Field: field, Message: message})
+	}
+	states := []*importState{}
+	statesByKey := map[string]*importState{}
+	stateCodes := map[string]string{}
+	cityKeys := map[string]string{}
+	cityCodes := map[string]string{}
+	for _, row := range rows {
+		valid := true
+		if utils.FoldTitle(row.StateTitle) == "" {
+			fail(row, "state_title", "state title is required")
+			valid = false
+		}
+		if mode == entity.ImportByCode && row.StateCode == "" {
+			fail(row, "state_code", "state code is required when importing by code")
+			valid = false
+		}
+		if row.HasCity() && utils.FoldTitle(row.CityTitle) == "" {
+			fail(row, "city_title", "city title is required")
+			valid = false
+		}
+		if row.HasCity() && mode == entity.ImportByCode && row.CityCode == "" {
+			fail(row, "city_code", "city code is required when importing by code")
+			valid = false
+		}
+		if !valid {
+			continue
+		}
+
+		stateKey := importKey(mode, row.StateCode, row.StateTitle)
+		if owner, taken := stateCodes[row.StateCode]; taken && row.StateCode != "" && owner != stateKey {
+			fail(row, "state_code", "state code is used by another state of the file")
+			continue
+		}
+		state, ok := statesByKey[stateKey]
+		if !ok {
+			state = &importState{row: row}
+			statesByKey[stateKey] = state
+			states = append(states, state)
+		} else if mode == entity.ImportByCode && utils.FoldTitle(row.StateTitle) != utils.FoldTitle(state.row.StateTitle) {
+			fail(row, "state_title", fmt.Sprintf("state title differs from %v", state.row.Location))
+			continue
+		} else if mode == entity.ImportByTitle && row.StateCode != state.row.StateCode {
+			if state.row.StateCode != "" && row.StateCode != "" {
+				fail(row, "state_code", fmt.Sprintf("state code differs from %v", state.row.Location))
+				continue
+			}
+			if state.row.StateCode == "" {
+				state.row.StateCode = row.StateCode
+			}
+		}
+		if row.StateCode != "" {
+			stateCodes[row.StateCode] = stateKey
+		}
+		if !row.HasCity() {
+			continue
+		}
+
+		cityKey := stateKey + "\x00" + importKey(mode, row.CityCode, row.CityTitle)
+		if mode == entity.ImportByCode {
+			cityKey = row.CityCode
+		}
+		if location, duplicate := cityKeys[cityKey]; duplicate {
+			fail(row, "city_title", fmt.Sprintf("the city is already listed at %v", location))
+			continue
+		}
+		if location, taken := cityCodes[row.CityCode]; taken && row.CityCode != "" {
+			fail(row, "city_code", fmt.Sprintf("city code is already used at %v", location))
+			continue
+		}
+		cityKeys[cityKey] = row.Location
+		if row.CityCode != "" {
+			cityCodes[row.CityCode] = row.Location
+		}
+		state.cities = append(state.cities, row)
+	}
+	return states
+}
+
+// planImport matches the grouped rows against the database and returns what
+// has to be written. Codes already owned by other records are reported as
+// errors.
+func (u LocationUseCase) planImport(ctx context.Context, states []*importState, mode string, result *entity.ImportResult) ([]entity.GeoSeed, error) {
+	existingStates, err := u.GeoData.States(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existingCities, err := u.GeoData.Cities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fail := func(row entity.LocationRow, field, message string) {
+		result.Errors = append(result.Errors, entity.ImportError{Location: row.Location, Field: field, Message: message})
+	}
+
+	statesByCode := map[string]entity.State{}
+	statesByTitle := map[string]entity.State{}
+	for _, state := range existingStates {
+		if state.Code != "" {
+			statesByCode[state.Code] = state
+		}
+		if _, ok := statesByTitle[utils.FoldTitle(state.Title)]; !ok {
+			statesByTitle[utils.FoldTitle(state.Title)] = state
+		}
+	}
+	citiesByCode := map[string]entity.City{}
+	citiesByTitle := map[uint]map[string]entity.City{}
+	for _, city := range existingCities {
+		if city.Code != "" {
+			citiesByCode[city.Code] = city
+		}
+		if citiesByTitle[city.StateID] == nil {
+			citiesByTitle[city.StateID] = map[string]entity.City{}
+		}
+		if _, ok := citiesByTitle[city.StateID][utils.FoldTitle(city.Title)]; !ok {
+			citiesByTitle[city.StateID][utils.FoldTitle(city.Title)] = city
+		}
+	}
+
+	seeds := []entity.GeoSeed{}
+	for _, imported := range states {
+		row := imported.row
+		seed := entity.GeoSeed{}
+		state, found := statesByTitle[utils.FoldTitle(row.StateTitle)]
+		if mode == entity.ImportByCode {
+			state, found = statesByCode[row.StateCode]
+		}
+		if owner, taken := statesByCode[row.StateCode]; taken && row.StateCode != "" && (!found || owner.ID != state.ID) {
+			fail(row, "state_code", fmt.Sprintf("state code is used by state %v", owner.ID))
+			continue
+		}
+		if owner, taken := statesByTitle[utils.FoldTitle(row.StateTitle)]; taken && mode == entity.ImportByCode && (!found || owner.ID != state.ID) {
+			fail(row, "state_title", fmt.Sprintf("state title is used by state %v", owner.ID))
+			continue
+		}
+		switch {
+		case !found:
+			seed.State = entity.State{Title: row.StateTitle, Code: row.StateCode}
+			seed.Save = true
+			result.CreatedStates++
+		case mode == entity.ImportByCode && state.Title != row.StateTitle,
+			mode == entity.ImportByTitle && row.StateCode != "" && state.Code != row.StateCode:
+			seed.State = state
+			if mode == entity.ImportByCode {
+				seed.State.Title = row.StateTitle
+			} else {
+				seed.State.Code = row.StateCode
+			}
+			seed.Save = true
+			result.UpdatedStates++
+		default:
+			seed.State = state
+		}
+
+		for _, cityRow := range imported.cities {
+			var city entity.City
+			var cityFound bool
+			if mode == entity.ImportByCode {
+				city, cityFound = citiesByCode[cityRow.CityCode]
+			} else if found {
+				city, cityFound = citiesByTitle[state.ID][utils.FoldTitle(cityRow.CityTitle)]
+			}
+			if owner, taken := citiesByCode[cityRow.CityCode]; taken && cityRow.CityCode != "" && (!cityFound || owner.ID != city.ID) {
+				fail(cityRow, "city_code", fmt.Sprintf("city code is used by city %v", owner.ID))
+				continue
+			}
+			if owner, taken := citiesByTitle[state.ID][utils.FoldTitle(cityRow.CityTitle)]; taken && found && mode == entity.ImportByCode && (!cityFound || owner.ID != city.ID) {
+				fail(cityRow, "city_title", fmt.Sprintf("city title is used by city %v", owner.ID))
+				continue
+			}
+			switch {
+			case !cityFound:
+				seed.Cities = append(seed.Cities, entity.City{Title: cityRow.CityTitle, Code: cityRow.CityCode})
+				result.CreatedCities++
+			case mode == entity.ImportByCode && (city.Title != cityRow.CityTitle || !found || city.StateID != state.ID),
+				mode == entity.ImportByTitle && cityRow.CityCode != "" && city.Code != cityRow.CityCode:
+				if mode == entity.ImportByCode {
+					city.Title = cityRow.CityTitle
+				} else {
+					city.Code = cityRow.CityCode
+				}
+				seed.Cities = append(seed.Cities, city)
+				result.UpdatedCities++
+			}
+		}
+		if seed.Save || len(seed.Cities) > 0 {
+			seeds = append(seeds, seed)
+		}
+	}
+	return seeds, nil
+}
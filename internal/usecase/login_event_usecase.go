@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/geoip"
+)
+
+var (
+	ErrLoginNotSuspicious   = errors.New("this login was not flagged")
+	ErrLoginAlreadyReviewed = errors.New("this login has already been reviewed")
+)
+
+type LoginEventUseCase struct {
+	Repo repository.LoginEventRepository
+	Conf config.LoginMonitor
+}
+
+func NewLoginEventUseCase(repo repository.LoginEventRepository, conf config.LoginMonitor) LoginEventUseCase {
+	return LoginEventUseCase{Repo: repo, Conf: conf}
+}
+
+// Record stores the event after resolving its country and flagging
+// anomalies. homeCountry is the country of the user's mobile number, which
+// counts as known even before the first login.
+func (u LoginEventUseCase) Record(ctx context.Context, event *entity.LoginEvent, homeCountry string) error {
+	event.Country, _ = geoip.Country(event.IP)
+	var err error
+	switch event.Type {
+	case entity.LoginSucceededEvent:
+		err = u.checkSuccess(ctx, event, homeCountry)
+	case entity.LoginFailedEvent:
+		err = u.checkFailure(ctx, event)
+	}
+	if err != nil {
+		return err
+	}
+	return u.Repo.Save(ctx, event)
+}
+
+func (u LoginEventUseCase) checkSuccess(ctx context.Context, event *entity.LoginEvent, homeCountry string) error {
+	devices, err := u.Repo.KnownDevices(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+	// the very first device of an account is not news to anyone
+	if len(devices) > 0 && !slices.Contains(devices, event.DeviceHash) {
+		event.AddAnomaly(entity.NewDeviceAnomaly)
+	}
+	if event.Country == "" {
+		return nil
+	}
+	countries, err := u.Repo.KnownCountries(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+	if homeCountry != "" {
+		countries = append(countries, homeCountry)
+	}
+	if len(countries) > 0 && !slices.Contains(countries, event.Country) {
+		event.AddAnomaly(entity.UnusualCountryAnomaly)
+	}
+	return nil
+}
+
+// checkFailure flags the failure that reaches the burst threshold, so a
+// burst raises a single alert rather than one per attempt.
+func (u LoginEventUseCase) checkFailure(ctx context.Context, event *entity.LoginEvent) error {
+	failures, err := u.Repo.CountFailuresSince(ctx, event.MobileNumber, time.Now().Add(-u.Conf.FailureWindow))
+	if err != nil {
+		return err
+	}
+	if failures+1 == u.Conf.FailureBurst {
+		event.AddAnomaly(entity.FailureBurstAnomaly)
+	}
+	return nil
+}
+
+func (u LoginEventUseCase) History(ctx context.Context, userId uint, pageNumber, pageSize int) ([]entity.LoginEvent, int, error) {
+	return u.Repo.ByUserId(ctx, userId, pageSize, (pageNumber-1)*pageSize)
+}
+
+func (u LoginEventUseCase) ReviewQueue(ctx context.Context, reviewed bool, pageNumber, pageSize int) ([]entity.LoginEvent, int, error) {
+	return u.Repo.Suspicious(ctx, reviewed, pageSize, (pageNumber-1)*pageSize)
+}
+
+func (u LoginEventUseCase) Review(ctx context.Context, id, reviewerId uint, note string) (entity.LoginEvent, error) {
+	event := entity.LoginEvent{}
+	if err := u.Repo.ById(ctx, id, &event).Error; err != nil {
+		return entity.LoginEvent{}, err
+	}
+	if !event.IsSuspicious() {
+		return entity.LoginEvent{}, ErrLoginNotSuspicious
+	}
+	if event.ReviewedAt != nil {
+		return entity.LoginEvent{}, ErrLoginAlreadyReviewed
+	}
+	err := u.Repo.Review(ctx, &event, reviewerId, note, time.Now())
+	return event, err
+}
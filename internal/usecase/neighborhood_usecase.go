@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+)
+
+type NeighborhoodUseCase struct {
+	Repo  repository.NeighborhoodRepository
+	Audit AuditHook
+}
+
+func NewNeighborhoodUseCase(repo repository.NeighborhoodRepository) NeighborhoodUseCase {
+	return NeighborhoodUseCase{Repo: repo}
+}
+
+// WithAudit returns a copy of the use case that reports its mutations to hook.
+func (u NeighborhoodUseCase) WithAudit(hook AuditHook) NeighborhoodUseCase {
+	u.Audit = hook
+	return u
+}
+
+func (u NeighborhoodUseCase) Create(ctx context.Context, title string, district entity.District) (entity.Neighborhood, error) {
+	neighborhood := entity.NewNeighborhood(title, district)
+	if err := u.Repo.Save(ctx, &neighborhood).Error; err != nil {
+		return neighborhood, err
+	}
+	return neighborhood, audit(ctx, u.Audit, entity.CreateNeighborhoodAction, "neighborhood", neighborhood.ID, nil, neighborhood)
+}
+
+// List returns a page of the district's neighborhoods whose title contains
+// title and how many neighborhoods match in total.
+func (u NeighborhoodUseCase) List(ctx context.Context, districtId uint, page, size int, title string) ([]entity.Neighborhood, int, error) {
+	query := u.Repo.List(ctx, districtId, title)
+	count, err := u.Repo.Count(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	neighborhoods, err := u.Repo.Paginate(size, utils.PageToOffset(page, size), query)
+	return neighborhoods, count, err
+}
+
+func (u NeighborhoodUseCase) ById(ctx context.Context, id uint) (entity.Neighborhood, error) {
+	neighborhood := new(entity.Neighborhood)
+	err := u.Repo.ById(ctx, id, neighborhood).Error
+	return *neighborhood, err
+}
+
+func (u NeighborhoodUseCase) Update(ctx context.Context, id uint, newInfo map[string]any) (entity.Neighborhood, error) {
+	neighborhood, err := u.ById(ctx, id)
+	if err != nil {
+		return entity.Neighborhood{}, err
+	}
+	before := neighborhood
+	if err = u.Repo.Update(ctx, &neighborhood, newInfo); err != nil {
+		return neighborhood, err
+	}
+	after, err := u.ById(ctx, id)
+	if err != nil {
+		return neighborhood, err
+	}
+	return after, audit(ctx, u.Audit, entity.UpdateNeighborhoodAction, "neighborhood", id, before, after)
+}
+
+func (u NeighborhoodUseCase) DeleteById(ctx context.Context, id uint) error {
+	neighborhood, err := u.ById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = u.Repo.Delete(ctx, &neighborhood).Error; err != nil {
+		return err
+	}
+	return audit(ctx, u.Audit, entity.DeleteNeighborhoodAction, "neighborhood", id, neighborhood, nil)
+}
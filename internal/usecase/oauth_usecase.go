@@ -0,0 +1,356 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// OAuthError carries one of the error codes defined by RFC 6749 so handlers
+// can report it the way OAuth2 clients expect.
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func (err OAuthError) Error() string {
+	return err.Description
+}
+
+var (
+	ErrOAuthInvalidClient = OAuthError{"invalid_client", "client authentication failed"}
+	ErrOAuthInvalidGrant  = OAuthError{"invalid_grant", "the grant is invalid, expired or was issued to another client"}
+	ErrOAuthInvalidScope  = OAuthError{"invalid_scope", "the requested scope is invalid or exceeds the granted scope"}
+	ErrOAuthRedirectURI   = OAuthError{"invalid_request", "redirect_uri is not registered for this client"}
+	ErrOAuthPKCERequired  = OAuthError{"invalid_request", "a S256 code_challenge is required"}
+	ErrOAuthUnauthorized  = OAuthError{"unauthorized_client", "this client can not use the requested grant"}
+	ErrOAuthUnsupported   = OAuthError{"unsupported_grant_type", "the grant type is not supported"}
+)
+
+type OAuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+	Scope        string
+	ExpiresIn    time.Duration
+}
+
+type OAuthIntrospection struct {
+	Active    bool
+	TokenType string
+	ClientId  string
+	UserId    uint
+	Scope     string
+	ExpiresAt int64
+}
+
+type OAuthUseCase struct {
+	Clients repository.OAuthClientRepository
+	Tokens  repository.OAuthTokenRepository
+	Users   repository.UserRepository
+	Conf    config.OAuth
+	// SecretKey signs and verifies the access tokens
+	SecretKey string
+}
+
+func NewOAuthUseCase(clients repository.OAuthClientRepository, tokens repository.OAuthTokenRepository, users repository.UserRepository, conf config.OAuth, secretKey string) OAuthUseCase {
+	return OAuthUseCase{Clients: clients, Tokens: tokens, Users: users, Conf: conf, SecretKey: secretKey}
+}
+
+func (u OAuthUseCase) RegisterClient(ctx context.Context, name string, redirectURIs, scopes []string, confidential bool) (entity.OAuthClient, string, error) {
+	if len(scopes) == 0 {
+		return entity.OAuthClient{}, "", ErrOAuthInvalidScope
+	}
+	for _, scope := range scopes {
+		if !entity.IsScopeValid(scope) {
+			return entity.OAuthClient{}, "", ErrOAuthInvalidScope
+		}
+	}
+	if !confidential && len(redirectURIs) == 0 {
+		return entity.OAuthClient{}, "", errors.New("public clients need at least one redirect uri")
+	}
+	for _, redirectURI := range redirectURIs {
+		parsed, err := url.Parse(redirectURI)
+		if err != nil || parsed.Scheme == "" || parsed.Fragment != "" {
+			return entity.OAuthClient{}, "", errors.New("redirect uris must be absolute and have no fragment")
+		}
+	}
+	client, secret, err := entity.NewOAuthClient(name, redirectURIs, scopes, confidential)
+	if err != nil {
+		return entity.OAuthClient{}, "", err
+	}
+	err = u.Clients.Save(ctx, &client)
+	return client, secret, err
+}
+
+func (u OAuthUseCase) ListClients(ctx context.Context) ([]entity.OAuthClient, error) {
+	return u.Clients.List(ctx)
+}
+
+func (u OAuthUseCase) DeleteClient(ctx context.Context, id uint) error {
+	client := entity.OAuthClient{}
+	if err := u.Clients.ById(ctx, id, &client).Error; err != nil {
+		return err
+	}
+	return u.Clients.Delete(ctx, &client)
+}
+
+func (u OAuthUseCase) Client(ctx context.Context, clientId string) (entity.OAuthClient, error) {
+	client := entity.OAuthClient{}
+	err := u.Clients.ByClientId(ctx, clientId, &client).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return client, ErrOAuthInvalidClient
+	}
+	return client, err
+}
+
+// AuthenticateClient identifies the client calling the token, introspection
+// or revocation endpoint. Public clients only send their client id.
+func (u OAuthUseCase) AuthenticateClient(ctx context.Context, clientId, secret string) (entity.OAuthClient, error) {
+	client, err := u.Client(ctx, clientId)
+	if err != nil {
+		return client, err
+	}
+	if client.IsConfidential() && !client.MatchSecret(secret) {
+		return entity.OAuthClient{}, ErrOAuthInvalidClient
+	}
+	if !client.IsConfidential() && secret != "" {
+		return entity.OAuthClient{}, ErrOAuthInvalidClient
+	}
+	return client, nil
+}
+
+// ValidateAuthorizeRequest checks an authorization request before the user
+// is asked for consent and returns the client and the effective scope.
+func (u OAuthUseCase) ValidateAuthorizeRequest(ctx context.Context, clientId, redirectURI, scope, codeChallenge, codeChallengeMethod string) (entity.OAuthClient, string, error) {
+	client, err := u.Client(ctx, clientId)
+	if err != nil {
+		return client, "", err
+	}
+	if !client.AllowsRedirectURI(redirectURI) {
+		return client, "", ErrOAuthRedirectURI
+	}
+	if codeChallenge == "" || codeChallengeMethod != entity.PKCEMethodS256 {
+		return client, "", ErrOAuthPKCERequired
+	}
+	scope, err = u.grantableScope(client, scope)
+	return client, scope, err
+}
+
+// Authorize records the user's consent and returns the authorization code to
+// send back to the client.
+func (u OAuthUseCase) Authorize(ctx context.Context, clientId string, userId uint, redirectURI, scope, codeChallenge, codeChallengeMethod string) (string, error) {
+	client, scope, err := u.ValidateAuthorizeRequest(ctx, clientId, redirectURI, scope, codeChallenge, codeChallengeMethod)
+	if err != nil {
+		return "", err
+	}
+	code, err := entity.NewOAuthAuthorizationCode(client.ClientID, userId, redirectURI, scope, codeChallenge, codeChallengeMethod)
+	if err != nil {
+		return "", err
+	}
+	return code.Code, u.Tokens.SaveCode(ctx, code, u.Conf.CodeTTL)
+}
+
+func (u OAuthUseCase) ExchangeCode(ctx context.Context, client entity.OAuthClient, code, redirectURI, codeVerifier string) (OAuthTokens, error) {
+	authorizationCode, err := u.Tokens.TakeCode(ctx, code)
+	if errors.Is(err, redis.Nil) {
+		return OAuthTokens{}, ErrOAuthInvalidGrant
+	}
+	if err != nil {
+		return OAuthTokens{}, err
+	}
+	if authorizationCode.ClientID != client.ClientID ||
+		authorizationCode.RedirectURI != redirectURI ||
+		!authorizationCode.VerifyCodeChallenge(codeVerifier) {
+		return OAuthTokens{}, ErrOAuthInvalidGrant
+	}
+	return u.issueUserTokens(ctx, client, authorizationCode.UserID, authorizationCode.Scope)
+}
+
+// ClientCredentials issues a token acting as the client itself. Only
+// confidential clients may use it and no refresh token is issued.
+func (u OAuthUseCase) ClientCredentials(ctx context.Context, client entity.OAuthClient, scope string) (OAuthTokens, error) {
+	if !client.IsConfidential() {
+		return OAuthTokens{}, ErrOAuthUnauthorized
+	}
+	scope, err := u.grantableScope(client, scope)
+	if err != nil {
+		return OAuthTokens{}, err
+	}
+	for _, requested := range entity.SplitScope(scope) {
+		if requested == entity.ProfileScope {
+			return OAuthTokens{}, ErrOAuthInvalidScope
+		}
+	}
+	accessToken, err := u.accessToken(client, scope, entity.User{})
+	return OAuthTokens{AccessToken: accessToken, Scope: scope, ExpiresIn: u.Conf.AccessTokenTTL}, err
+}
+
+// Refresh rotates a refresh token, optionally narrowing its scope.
+func (u OAuthUseCase) Refresh(ctx context.Context, client entity.OAuthClient, token, scope string) (OAuthTokens, error) {
+	refreshToken, err := u.Tokens.GetRefreshToken(ctx, token)
+	if errors.Is(err, redis.Nil) {
+		return OAuthTokens{}, ErrOAuthInvalidGrant
+	}
+	if err != nil {
+		return OAuthTokens{}, err
+	}
+	if refreshToken.ClientID != client.ClientID {
+		return OAuthTokens{}, ErrOAuthInvalidGrant
+	}
+	if scope == "" {
+		scope = refreshToken.Scope
+	}
+	granted := entity.SplitScope(refreshToken.Scope)
+	for _, requested := range entity.SplitScope(scope) {
+		if !containsScope(granted, requested) {
+			return OAuthTokens{}, ErrOAuthInvalidScope
+		}
+	}
+	if _, err := u.Tokens.TakeRefreshToken(ctx, token); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return OAuthTokens{}, ErrOAuthInvalidGrant
+		}
+		return OAuthTokens{}, err
+	}
+	return u.issueUserTokens(ctx, client, refreshToken.UserID, entity.JoinScopes(entity.SplitScope(scope)))
+}
+
+// Introspect describes an access or refresh token as defined by RFC 7662.
+func (u OAuthUseCase) Introspect(ctx context.Context, token string) (OAuthIntrospection, error) {
+	if claims, err := utils.ValidateToken(u.SecretKey, token); err == nil {
+		clientId, isOAuthToken := claims["client_id"].(string)
+		if !isOAuthToken {
+			return OAuthIntrospection{}, nil
+		}
+		active, err := u.IsAccessTokenActive(ctx, claims)
+		if err != nil || !active {
+			return OAuthIntrospection{}, err
+		}
+		introspection := OAuthIntrospection{
+			Active:    true,
+			TokenType: "access_token",
+			ClientId:  clientId,
+			Scope:     claims["scope"].(string),
+			ExpiresAt: int64(claims["exp"].(float64)),
+		}
+		if userId, ok := claims["userId"].(float64); ok {
+			introspection.UserId = uint(userId)
+		}
+		return introspection, nil
+	}
+	refreshToken, err := u.Tokens.GetRefreshToken(ctx, token)
+	if errors.Is(err, redis.Nil) {
+		return OAuthIntrospection{}, nil
+	}
+	if err != nil {
+		return OAuthIntrospection{}, err
+	}
+	return OAuthIntrospection{
+		Active:    true,
+		TokenType: "refresh_token",
+		ClientId:  refreshToken.ClientID,
+		UserId:    refreshToken.UserID,
+		Scope:     refreshToken.Scope,
+	}, nil
+}
+
+// Revoke invalidates an access or refresh token issued to client. Unknown
+// tokens and tokens of other clients are ignored, as RFC 7009 requires.
+func (u OAuthUseCase) Revoke(ctx context.Context, client entity.OAuthClient, token string) error {
+	if claims, err := utils.ValidateToken(u.SecretKey, token); err == nil {
+		if claims["client_id"] != client.ClientID {
+			return nil
+		}
+		jti, _ := claims["jti"].(string)
+		expiresAt := time.Unix(int64(claims["exp"].(float64)), 0)
+		return u.Tokens.RevokeAccessToken(ctx, jti, time.Until(expiresAt))
+	}
+	refreshToken, err := u.Tokens.GetRefreshToken(ctx, token)
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if refreshToken.ClientID != client.ClientID {
+		return nil
+	}
+	_, err = u.Tokens.TakeRefreshToken(ctx, token)
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
+
+// IsAccessTokenActive checks that a validated OAuth access token has not
+// been revoked.
+func (u OAuthUseCase) IsAccessTokenActive(ctx context.Context, claims map[string]any) (bool, error) {
+	jti, _ := claims["jti"].(string)
+	revoked, err := u.Tokens.IsAccessTokenRevoked(ctx, jti)
+	return !revoked, err
+}
+
+func (u OAuthUseCase) issueUserTokens(ctx context.Context, client entity.OAuthClient, userId uint, scope string) (OAuthTokens, error) {
+	user := entity.User{}
+	if err := u.Users.ById(userId, &user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return OAuthTokens{}, ErrOAuthInvalidGrant
+		}
+		return OAuthTokens{}, err
+	}
+	accessToken, err := u.accessToken(client, scope, user)
+	if err != nil {
+		return OAuthTokens{}, err
+	}
+	refreshToken, err := entity.NewOAuthRefreshToken(client.ClientID, user.ID, scope)
+	if err != nil {
+		return OAuthTokens{}, err
+	}
+	err = u.Tokens.SaveRefreshToken(ctx, refreshToken, u.Conf.RefreshTokenTTL)
+	return OAuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken.Token,
+		Scope:        scope,
+		ExpiresIn:    u.Conf.AccessTokenTTL,
+	}, err
+}
+
+func (u OAuthUseCase) accessToken(client entity.OAuthClient, scope string, user entity.User) (string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	return utils.GenerateOAuthAccessToken(u.SecretKey, hex.EncodeToString(jti), client.ClientID, scope, user.ID, user.MobileNumber, user.Role, u.Conf.AccessTokenTTL)
+}
+
+// grantableScope defaults an empty request to everything the client was
+// registered with and rejects scopes beyond that.
+func (u OAuthUseCase) grantableScope(client entity.OAuthClient, scope string) (string, error) {
+	if scope == "" {
+		return client.Scope, nil
+	}
+	scopes := entity.SplitScope(scope)
+	if !client.AllowsScopes(scopes) {
+		return "", ErrOAuthInvalidScope
+	}
+	return entity.JoinScopes(scopes), nil
+}
+
+func containsScope(scopes []string, scope string) bool {
+	for _, candidate := range scopes {
+		if candidate == scope {
+			return true
+		}
+	}
+	return false
+}
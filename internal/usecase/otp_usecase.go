@@ -6,27 +6,32 @@ import (
 
 	"github.com/redis/go-redis/v9"
 
+	"github.com/TheAmirhosssein/room-reservation-api/config"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
 )
 
 type OTPUseCase struct {
 	Repo repository.OTPCodeRepository
+	Conf config.OTP
 }
 
-func NewOTPCase(otpRepo repository.OTPCodeRepository) OTPUseCase {
-	return OTPUseCase{Repo: otpRepo}
+func NewOTPCase(otpRepo repository.OTPCodeRepository, conf config.OTP) OTPUseCase {
+	return OTPUseCase{Repo: otpRepo, Conf: conf}
 }
 
-func (otp OTPUseCase) GenerateCode(ctx context.Context, mobileNumber string) (string, error) {
-	code, err := otp.Repo.GetCode(ctx, mobileNumber)
+func (otp OTPUseCase) GenerateCode(ctx context.Context, mobileNumber string, purpose entity.OTPPurpose) (string, error) {
+	code, err := otp.Repo.GetCode(ctx, mobileNumber, purpose)
 	if err != nil && err != redis.Nil {
 		return "", err
 	}
 	if code != "" {
 		return "", errors.New("please wait a minute to get new code")
 	}
-	otpCode := entity.NewOtpCode(mobileNumber)
+	otpCode, err := entity.NewOtpCode(mobileNumber, purpose, otp.Conf.Length, otp.Conf.TTL)
+	if err != nil {
+		return "", err
+	}
 	err = otp.Repo.Save(ctx, &otpCode)
 	if err != nil {
 		return "", err
@@ -34,8 +39,10 @@ func (otp OTPUseCase) GenerateCode(ctx context.Context, mobileNumber string) (st
 	return otpCode.Code, nil
 }
 
-func (otp OTPUseCase) ValidateCode(ctx context.Context, mobileNumber string, code string) error {
-	savedCode, err := otp.Repo.GetCode(ctx, mobileNumber)
+// CheckCode verifies a code without consuming it, for flows that need more
+// than the code before they can complete.
+func (otp OTPUseCase) CheckCode(ctx context.Context, mobileNumber string, purpose entity.OTPPurpose, code string) error {
+	savedCode, err := otp.Repo.GetCode(ctx, mobileNumber, purpose)
 	if err != nil {
 		if err == redis.Nil {
 			return errors.New("this code is invalid, please get new one")
@@ -43,10 +50,17 @@ func (otp OTPUseCase) ValidateCode(ctx context.Context, mobileNumber string, cod
 			return err
 		}
 	}
-	if code != savedCode {
+	if !entity.MatchOTPCode(savedCode, code) {
 		return errors.New("this code is incorrect")
 	}
-	if err = otp.Repo.DeleteCode(ctx, mobileNumber); err != nil {
+	return nil
+}
+
+func (otp OTPUseCase) ValidateCode(ctx context.Context, mobileNumber string, purpose entity.OTPPurpose, code string) error {
+	if err := otp.CheckCode(ctx, mobileNumber, purpose, code); err != nil {
+		return err
+	}
+	if err := otp.Repo.DeleteCode(ctx, mobileNumber, purpose); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleExists         = errors.New("a role with this name already exists")
+	ErrDefaultRole        = errors.New("default roles can not be removed")
+	ErrRoleInUse          = errors.New("this role is assigned to users")
+	ErrRoleNotGrantable   = errors.New("invalid role to select")
+	ErrPermissionsNotHeld = errors.New("a role can only grant permissions you hold yourself")
+	ErrInvalidPermission  = errors.New("invalid permission")
+	ErrBuiltInRole        = errors.New("the admin role always holds every permission and can not be changed")
+	ErrTwoFactorForced    = errors.New("two-factor authentication is always required for this role")
+)
+
+// roleCacheTTL bounds how long a role can stay stale when it is changed
+// without going through RoleUseCase, e.g. by SeedRoles.
+const roleCacheTTL = time.Minute
+
+type RoleUseCase struct {
+	Repo  repository.RoleRepository
+	Cache repository.RoleCacheRepository
+	Audit AuditHook
+}
+
+func NewRoleUseCase(repo repository.RoleRepository) RoleUseCase {
+	return RoleUseCase{Repo: repo}
+}
+
+// NewCachedRoleUseCase serves role lookups by name from cache, which is what
+// every authenticated request does.
+func NewCachedRoleUseCase(repo repository.RoleRepository, cache repository.RoleCacheRepository) RoleUseCase {
+	return RoleUseCase{Repo: repo, Cache: cache}
+}
+
+// WithAudit returns a copy of the use case that reports its mutations to hook.
+func (u RoleUseCase) WithAudit(hook AuditHook) RoleUseCase {
+	u.Audit = hook
+	return u
+}
+
+// FlushCache drops the cached roles and returns how many there were.
+func (u RoleUseCase) FlushCache(ctx context.Context) (int, error) {
+	if u.Cache == nil {
+		return 0, nil
+	}
+	return u.Cache.Flush(ctx)
+}
+
+func (u RoleUseCase) List(ctx context.Context) ([]entity.Role, error) {
+	return u.Repo.List(ctx)
+}
+
+func (u RoleUseCase) ById(ctx context.Context, id uint) (entity.Role, error) {
+	role := new(entity.Role)
+	err := u.Repo.ById(ctx, id, role).Error
+	return *role, err
+}
+
+func (u RoleUseCase) ByName(ctx context.Context, name string) (entity.Role, error) {
+	role := new(entity.Role)
+	if u.Cache != nil {
+		if found, err := u.Cache.Get(ctx, name, role); found && err == nil {
+			return *role, nil
+		}
+	}
+	err := u.Repo.ByName(ctx, name, role).Error
+	if err == nil && u.Cache != nil {
+		u.Cache.Set(ctx, *role, roleCacheTTL)
+	}
+	return *role, err
+}
+
+func (u RoleUseCase) invalidate(ctx context.Context, name string) error {
+	if u.Cache == nil {
+		return nil
+	}
+	return u.Cache.Invalidate(ctx, name)
+}
+
+func (u RoleUseCase) DoesRoleExist(ctx context.Context, name string) bool {
+	_, err := u.ByName(ctx, name)
+	return !(errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func (u RoleUseCase) HasPermission(ctx context.Context, roleName, permission string) (bool, error) {
+	role, err := u.ByName(ctx, roleName)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return role.HasPermission(permission), nil
+}
+
+// CanGrant reports whether a user holding actorRole may give targetRole to
+// someone else, i.e. targetRole does not grant anything actorRole lacks.
+func (u RoleUseCase) CanGrant(ctx context.Context, actorRole, targetRole string) (bool, error) {
+	actor, err := u.ByName(ctx, actorRole)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	target, err := u.ByName(ctx, targetRole)
+	if err != nil {
+		return false, err
+	}
+	return actor.Covers(target), nil
+}
+
+// CanGrantPermissions reports whether a user holding actorRole may put
+// permissions into a role, i.e. actorRole holds every one of them.
+func (u RoleUseCase) CanGrantPermissions(ctx context.Context, actorRole string, permissions []string) (bool, error) {
+	if err := validatePermissions(permissions); err != nil {
+		return false, err
+	}
+	actor, err := u.ByName(ctx, actorRole)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return actor.Covers(entity.NewRole("", permissions)), nil
+}
+
+func (u RoleUseCase) Create(ctx context.Context, name string, permissions []string) (entity.Role, error) {
+	if err := validatePermissions(permissions); err != nil {
+		return entity.Role{}, err
+	}
+	if u.DoesRoleExist(ctx, name) {
+		return entity.Role{}, ErrRoleExists
+	}
+	role := entity.NewRole(name, permissions)
+	if err := u.Repo.Save(ctx, &role); err != nil {
+		return role, err
+	}
+	return role, audit(ctx, u.Audit, entity.CreateRoleAction, "role", role.ID, nil, auditedRole(role))
+}
+
+// UpdatePermissions replaces the permissions of a role. The admin role is
+// refused, since SeedRoles hands it every permission again on the next start.
+func (u RoleUseCase) UpdatePermissions(ctx context.Context, id uint, permissions []string) (entity.Role, error) {
+	if err := validatePermissions(permissions); err != nil {
+		return entity.Role{}, err
+	}
+	role, err := u.ById(ctx, id)
+	if err != nil {
+		return entity.Role{}, err
+	}
+	if role.Name == entity.AdminRole {
+		return entity.Role{}, ErrBuiltInRole
+	}
+	before := auditedRole(role)
+	if err = u.Repo.ReplacePermissions(ctx, &role, permissions); err != nil {
+		return role, err
+	}
+	if err = u.invalidate(ctx, role.Name); err != nil {
+		return role, err
+	}
+	return role, audit(ctx, u.Audit, entity.UpdateRolePermissionsAction, "role", role.ID, before, auditedRole(role))
+}
+
+func (u RoleUseCase) SetRequireTwoFactor(ctx context.Context, id uint, required bool) (entity.Role, error) {
+	role, err := u.ById(ctx, id)
+	if err != nil {
+		return entity.Role{}, err
+	}
+	if !required && (role.Name == entity.AdminRole || role.Name == entity.SupportRole) {
+		return entity.Role{}, ErrTwoFactorForced
+	}
+	before := auditedRole(role)
+	if err = u.Repo.Update(ctx, &role, map[string]any{"require_two_factor": required}); err != nil {
+		return role, err
+	}
+	role.RequireTwoFactor = required
+	if err = u.invalidate(ctx, role.Name); err != nil {
+		return role, err
+	}
+	return role, audit(ctx, u.Audit, entity.RequireTwoFactorAction, "role", role.ID, before, auditedRole(role))
+}
+
+func (u RoleUseCase) DeleteById(ctx context.Context, id uint) error {
+	role, err := u.ById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if entity.IsDefaultRole(role.Name) {
+		return ErrDefaultRole
+	}
+	count, err := u.Repo.CountUsers(ctx, role.Name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRoleInUse
+	}
+	if err = u.Repo.Delete(ctx, &role); err != nil {
+		return err
+	}
+	if err = u.invalidate(ctx, role.Name); err != nil {
+		return err
+	}
+	return audit(ctx, u.Audit, entity.DeleteRoleAction, "role", role.ID, auditedRole(role), nil)
+}
+
+// auditedRole flattens role so a permission change shows up as a list of
+// names rather than join table rows.
+func auditedRole(role entity.Role) map[string]any {
+	return map[string]any{
+		"Name":             role.Name,
+		"RequireTwoFactor": role.RequireTwoFactor,
+		"Permissions":      role.PermissionNames(),
+	}
+}
+
+func validatePermissions(permissions []string) error {
+	for _, permission := range permissions {
+		if !entity.IsPermissionValid(permission) {
+			return fmt.Errorf("%w %q", ErrInvalidPermission, permission)
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
@@ -10,16 +11,58 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	ErrStateHasCities      = errors.New("the state still has cities, purge them first")
+	ErrInvalidTranslation  = errors.New("invalid translation")
+	ErrStateTitleTaken     = errors.New("a state with this title already exists")
+	ErrStateInUse          = errors.New("the state still has cities, delete or reassign them first")
+	ErrInvalidDeletionMode = errors.New("deletion mode must be reject, cascade or reassign")
+	ErrInvalidReassignment = errors.New("cities can only be reassigned to another existing state")
+	ErrInvalidDefaultMode  = errors.New("default deletion mode must be reject or cascade, reassign needs a target state")
+)
+
 type StateUseCase struct {
-	Repo repository.StateRepository
+	Repo  repository.StateRepository
+	Audit AuditHook
+	// DeletionMode is what deleting a state with cities does when the caller
+	// doesn't choose, see entity.RejectDeletion
+	DeletionMode string
 }
 
 func NewStateUseCase(repo repository.StateRepository) StateUseCase {
-	return StateUseCase{Repo: repo}
+	return StateUseCase{Repo: repo, DeletionMode: entity.RejectDeletion}
+}
+
+// WithAudit returns a copy of the use case that reports its mutations to hook.
+func (u StateUseCase) WithAudit(hook AuditHook) StateUseCase {
+	u.Audit = hook
+	return u
+}
+
+// WithDeletionMode returns a copy of the use case that deletes states with
+// cities according to mode.
+func (u StateUseCase) WithDeletionMode(mode string) StateUseCase {
+	u.DeletionMode = mode
+	return u
+}
+
+// ValidateDefaultDeletionMode reports whether mode can be used when a
+// request doesn't choose one. Reassign can't, since it needs a target state.
+func ValidateDefaultDeletionMode(mode string) error {
+	if mode != entity.RejectDeletion && mode != entity.CascadeDeletion {
+		return fmt.Errorf("%w, got %q", ErrInvalidDefaultMode, mode)
+	}
+	return nil
 }
 
 func (u StateUseCase) Create(context context.Context, state *entity.State) error {
-	return u.Repo.Save(context, state).Error
+	if err := state.Location.Validate(); err != nil {
+		return err
+	}
+	if err := titleTaken(u.Repo.Save(context, state).Error, ErrStateTitleTaken); err != nil {
+		return err
+	}
+	return audit(context, u.Audit, entity.CreateStateAction, "state", state.ID, nil, state)
 }
 
 func (u StateUseCase) GetStateList(ctx context.Context, page, pageSize int, title string) ([]entity.State, error) {
@@ -53,14 +96,130 @@ func (u StateUseCase) Update(ctx context.Context, id uint, newInfo map[string]an
 	if err != nil {
 		return err
 	}
-	err = u.Repo.Update(ctx, &state, newInfo)
-	return err
+	before := state
+	if err = titleTaken(u.Repo.Update(ctx, &state, newInfo), ErrStateTitleTaken); err != nil {
+		return err
+	}
+	after, err := u.GetStateById(ctx, id)
+	if err != nil {
+		return err
+	}
+	return audit(ctx, u.Audit, entity.UpdateStateAction, "state", id, before, after)
 }
 
+// DeleteById deletes a state according to the use case's DeletionMode.
 func (u StateUseCase) DeleteById(ctx context.Context, id uint) error {
+	return u.Delete(ctx, id, u.DeletionMode, 0)
+}
+
+// Delete deletes a state. When it still has cities, mode decides whether
+// the deletion is rejected with ErrStateInUse, the cities are deleted with
+// it or they are moved to the state with targetId. Moving fails with
+// ErrCityTitleTaken when the target already has a city of the same title.
+func (u StateUseCase) Delete(ctx context.Context, id uint, mode string, targetId uint) error {
+	if !entity.IsDeletionModeValid(mode) {
+		return ErrInvalidDeletionMode
+	}
 	state, err := u.GetStateById(ctx, id)
 	if err != nil {
 		return err
 	}
-	return u.Repo.Delete(ctx, &state).Error
+	cities, err := u.Repo.CountActiveCities(ctx, id)
+	if err != nil {
+		return err
+	}
+	switch {
+	case cities == 0:
+		err = u.Repo.Delete(ctx, &state).Error
+	case mode == entity.RejectDeletion:
+		return ErrStateInUse
+	case mode == entity.CascadeDeletion:
+		err = u.Repo.DeleteCascade(ctx, &state)
+	default:
+		if targetId == id || !u.DoesStateExist(ctx, targetId) {
+			return ErrInvalidReassignment
+		}
+		err = titleTaken(u.Repo.DeleteReassign(ctx, &state, targetId), ErrCityTitleTaken)
+	}
+	if err != nil {
+		return err
+	}
+	return audit(ctx, u.Audit, entity.DeleteStateAction, "state", id, state, nil)
+}
+
+// titleTaken replaces the error of a write that breaks the unique index on
+// folded titles with taken.
+func titleTaken(err, taken error) error {
+	if repository.IsTitleTaken(err) {
+		return taken
+	}
+	return err
+}
+
+func (u StateUseCase) Trashed(ctx context.Context, pageNumber, pageSize int) ([]entity.State, int, error) {
+	return u.Repo.Trashed(ctx, pageSize, utils.PageToOffset(pageNumber, pageSize))
+}
+
+func (u StateUseCase) Restore(ctx context.Context, id uint) (entity.State, error) {
+	state := entity.State{}
+	if err := u.Repo.TrashedById(ctx, id, &state).Error; err != nil {
+		return entity.State{}, err
+	}
+	if err := titleTaken(u.Repo.Restore(ctx, &state), ErrStateTitleTaken); err != nil {
+		return entity.State{}, err
+	}
+	restored, err := u.GetStateById(ctx, id)
+	if err != nil {
+		return entity.State{}, err
+	}
+	return restored, audit(ctx, u.Audit, entity.RestoreStateAction, "state", id, nil, restored)
+}
+
+// Purge permanently removes a state that is already in the trash. Its cities
+// have to go first, trashed ones included, since they still reference it.
+func (u StateUseCase) Purge(ctx context.Context, id uint) error {
+	state := entity.State{}
+	if err := u.Repo.TrashedById(ctx, id, &state).Error; err != nil {
+		return err
+	}
+	count, err := u.Repo.CountCities(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrStateHasCities
+	}
+	if err = u.Repo.Purge(ctx, &state); err != nil {
+		return err
+	}
+	return audit(ctx, u.Audit, entity.PurgeStateAction, "state", id, state, nil)
+}
+
+// UpdateTranslations replaces the state's translations with titles, keyed by
+// locale. The title in the default locale is the state's own title.
+func (u StateUseCase) UpdateTranslations(ctx context.Context, id uint, titles map[string]string) (entity.State, error) {
+	if err := validateTranslations(titles); err != nil {
+		return entity.State{}, err
+	}
+	state, err := u.GetStateById(ctx, id)
+	if err != nil {
+		return entity.State{}, err
+	}
+	before := state.TranslationTitles()
+	if err = u.Repo.ReplaceTranslations(ctx, &state, titles); err != nil {
+		return state, err
+	}
+	return state, audit(ctx, u.Audit, entity.TranslateStateAction, "state", id, before, state.TranslationTitles())
+}
+
+func validateTranslations(titles map[string]string) error {
+	for locale := range titles {
+		if locale == entity.DefaultLocale {
+			return fmt.Errorf("%w: %q is the default locale, change the title instead", ErrInvalidTranslation, locale)
+		}
+		if !entity.IsLocaleSupported(locale) {
+			return fmt.Errorf("%w: unsupported locale %q", ErrInvalidTranslation, locale)
+		}
+	}
+	return nil
 }
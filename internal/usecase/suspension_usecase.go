@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+)
+
+var (
+	ErrInvalidSuspensionReason = errors.New("invalid suspension reason")
+	ErrSuspensionEnd           = errors.New("suspension end time must be in the future")
+	ErrSuspendSelf             = errors.New("you can not suspend yourself")
+	ErrNotSuspended            = errors.New("user is not suspended")
+)
+
+type SuspensionUseCase struct {
+	Repo  repository.SuspensionRepository
+	Users repository.UserRepository
+}
+
+func NewSuspensionUseCase(repo repository.SuspensionRepository, userRepo repository.UserRepository) SuspensionUseCase {
+	return SuspensionUseCase{Repo: repo, Users: userRepo}
+}
+
+// Suspend locks the user out until endsAt, or for good when endsAt is nil.
+// Suspending an already suspended user replaces the running suspension.
+func (u SuspensionUseCase) Suspend(ctx context.Context, actorId, userId uint, reason, notes string, endsAt *time.Time) (entity.User, error) {
+	if !entity.IsSuspensionReasonValid(reason) {
+		return entity.User{}, ErrInvalidSuspensionReason
+	}
+	now := time.Now()
+	if endsAt != nil && !endsAt.After(now) {
+		return entity.User{}, ErrSuspensionEnd
+	}
+	if actorId == userId {
+		return entity.User{}, ErrSuspendSelf
+	}
+	user := entity.User{}
+	if err := u.Users.ById(userId, &user).Error; err != nil {
+		return entity.User{}, err
+	}
+	if err := NewUserUseCase(u.Users).EnsureAdminRemains(user); err != nil {
+		return entity.User{}, err
+	}
+	suspension := entity.NewSuspension(userId, actorId, reason, notes, endsAt)
+	suspension.CreatedAt = now
+	err := u.Repo.Suspend(ctx, &user, &suspension)
+	return user, err
+}
+
+func (u SuspensionUseCase) Unsuspend(ctx context.Context, actorId, userId uint, notes string) (entity.User, error) {
+	user := entity.User{}
+	if err := u.Users.ById(userId, &user).Error; err != nil {
+		return entity.User{}, err
+	}
+	now := time.Now()
+	if !user.IsSuspended(now) {
+		return entity.User{}, ErrNotSuspended
+	}
+	err := u.Repo.Lift(ctx, &user, actorId, notes, now)
+	return user, err
+}
+
+func (u SuspensionUseCase) History(ctx context.Context, userId uint) ([]entity.Suspension, error) {
+	return u.Repo.ByUserId(ctx, userId)
+}
@@ -0,0 +1,100 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAccountUseCase_Deletion(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	userRepo := repository.NewUserRepository(db)
+	conf := config.AccountDeletion{GracePeriod: time.Hour}
+	accountUseCase := usecase.NewAccountUseCase(repository.NewAccountRepository(db), userRepo, conf)
+	ctx := context.TODO()
+
+	admin := entity.NewUser("admin", "09120000001", entity.AdminRole)
+	assert.NoError(t, userRepo.Save(&admin))
+	_, err = accountUseCase.ScheduleDeletion(ctx, admin.ID)
+	assert.ErrorIs(t, err, usecase.ErrLastAdmin)
+
+	user := entity.NewUser("user", "09120000002", entity.UserRole)
+	assert.NoError(t, userRepo.Save(&user))
+	user, err = accountUseCase.ScheduleDeletion(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.True(t, user.IsDeletionPending())
+
+	cancelled, err := accountUseCase.CancelDeletion(ctx, &user)
+	assert.NoError(t, err)
+	assert.True(t, cancelled)
+	assert.False(t, user.IsDeletionPending())
+	cancelled, err = accountUseCase.CancelDeletion(ctx, &user)
+	assert.NoError(t, err)
+	assert.False(t, cancelled)
+
+	user, err = accountUseCase.ScheduleDeletion(ctx, user.ID)
+	assert.NoError(t, err)
+	count, err := accountUseCase.AnonymizeDue(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+	count, err = accountUseCase.AnonymizeDue(ctx, time.Now().Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	userUseCase := usecase.NewUserUseCase(userRepo)
+	assert.False(t, userUseCase.DoesUserExist(user.ID))
+	assert.False(t, userUseCase.IsMobileNumberTaken(user.MobileNumber))
+	_, err = userUseCase.Restore(ctx, user.ID)
+	assert.ErrorIs(t, err, usecase.ErrUserAnonymized)
+}
+
+func TestAccountUseCase_AnonymizeDueScrubsAuditLogs(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	userRepo := repository.NewUserRepository(db)
+	auditLogs := usecase.NewAuditLogUseCase(repository.NewAuditLogRepository(db))
+	conf := config.AccountDeletion{GracePeriod: time.Hour}
+	accountUseCase := usecase.NewAccountUseCase(repository.NewAccountRepository(db), userRepo, conf).WithAudit(auditLogs)
+	userUseCase := usecase.NewUserUseCase(userRepo).WithAudit(auditLogs)
+	ctx := context.TODO()
+
+	user := entity.NewUser("Jane Doe", "09120000002", entity.UserRole)
+	assert.NoError(t, userRepo.Save(&user))
+	assert.NoError(t, userUseCase.Update(ctx, user.ID, map[string]any{"full_name": "Jane Roe"}))
+	details := fmt.Sprintf("%v -> %v: lost sim", "09120000003", user.MobileNumber)
+	assert.NoError(t, auditLogs.Record(ctx, 1, entity.ChangeMobileNumberAction, "user", user.ID, details))
+	_, err = accountUseCase.ScheduleDeletion(ctx, user.ID)
+	assert.NoError(t, err)
+
+	count, err := accountUseCase.AnonymizeDue(ctx, time.Now().Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	entries, err := auditLogs.ByTarget(ctx, "user", user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4)
+	for _, entry := range entries {
+		for _, personal := range []string{"Jane", "0912000000"} {
+			assert.False(t, strings.Contains(entry.Changes+entry.Details, personal), "%v keeps %v", entry.Action, personal)
+		}
+	}
+	assert.Contains(t, entries[0].Changes, entity.RedactedValue)
+}
@@ -0,0 +1,94 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newAPIKeyUseCase(t *testing.T) (usecase.APIKeyUseCase, *miniredis.Miniredis) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	mr, err := miniredis.Run()
+	assert.NoError(t, err)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return usecase.NewAPIKeyUseCase(repository.NewAPIKeyRepository(db), repository.NewRateLimitRepository(rdb)), mr
+}
+
+func TestAPIKeyUseCase_Issue(t *testing.T) {
+	useCase, mr := newAPIKeyUseCase(t)
+	defer mr.Close()
+	userId := uint(1)
+	past := time.Now().Add(-time.Minute)
+
+	_, _, err := useCase.Issue(context.TODO(), "partner", nil, "", []string{entity.UsersReadPermission}, 0, nil)
+	assert.ErrorIs(t, err, usecase.ErrAPIKeyOwner)
+	_, _, err = useCase.Issue(context.TODO(), "partner", &userId, "Agency", []string{entity.UsersReadPermission}, 0, nil)
+	assert.ErrorIs(t, err, usecase.ErrAPIKeyOwner)
+	_, _, err = useCase.Issue(context.TODO(), "partner", nil, "Agency", nil, 0, nil)
+	assert.ErrorIs(t, err, usecase.ErrAPIKeyNoScopes)
+	_, _, err = useCase.Issue(context.TODO(), "partner", nil, "Agency", []string{"unknown"}, 0, nil)
+	assert.Error(t, err)
+	_, _, err = useCase.Issue(context.TODO(), "partner", nil, "Agency", []string{entity.UsersReadPermission}, 0, &past)
+	assert.ErrorIs(t, err, usecase.ErrAPIKeyExpiry)
+
+	apiKey, rawKey, err := useCase.Issue(context.TODO(), "partner", nil, "Agency", []string{entity.UsersReadPermission}, 0, nil)
+	assert.NoError(t, err)
+	assert.NotZero(t, apiKey.ID)
+	assert.NotEmpty(t, rawKey)
+}
+
+func TestAPIKeyUseCase_Authenticate(t *testing.T) {
+	useCase, mr := newAPIKeyUseCase(t)
+	defer mr.Close()
+
+	apiKey, rawKey, _ := useCase.Issue(context.TODO(), "partner", nil, "Agency", []string{entity.UsersReadPermission}, 0, nil)
+	authenticated, err := useCase.Authenticate(context.TODO(), rawKey)
+	assert.NoError(t, err)
+	assert.Equal(t, apiKey.ID, authenticated.ID)
+	assert.Equal(t, []string{entity.UsersReadPermission}, authenticated.ScopeNames())
+	stored, _ := useCase.ById(context.TODO(), apiKey.ID)
+	assert.NotNil(t, stored.LastUsedAt)
+
+	_, err = useCase.Authenticate(context.TODO(), "rrk_00000000_abc")
+	assert.ErrorIs(t, err, usecase.ErrInvalidAPIKey)
+	_, err = useCase.Authenticate(context.TODO(), rawKey[:len(rawKey)-1])
+	assert.ErrorIs(t, err, usecase.ErrInvalidAPIKey)
+
+	_, err = useCase.Revoke(context.TODO(), apiKey.ID)
+	assert.NoError(t, err)
+	_, err = useCase.Authenticate(context.TODO(), rawKey)
+	assert.ErrorIs(t, err, usecase.ErrInvalidAPIKey)
+}
+
+func TestAPIKeyUseCase_Allow(t *testing.T) {
+	useCase, mr := newAPIKeyUseCase(t)
+	defer mr.Close()
+
+	apiKey, _, _ := useCase.Issue(context.TODO(), "partner", nil, "Agency", []string{entity.UsersReadPermission}, 2, nil)
+	remaining, allowed, err := useCase.Allow(context.TODO(), apiKey)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, int64(1), remaining)
+	_, allowed, _ = useCase.Allow(context.TODO(), apiKey)
+	assert.True(t, allowed)
+	_, allowed, _ = useCase.Allow(context.TODO(), apiKey)
+	assert.False(t, allowed)
+
+	mr.FastForward(time.Minute)
+	_, allowed, _ = useCase.Allow(context.TODO(), apiKey)
+	assert.True(t, allowed)
+}
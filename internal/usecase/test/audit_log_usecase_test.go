@@ -0,0 +1,81 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAuditLogUseCase_Hook(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	auditUseCase := usecase.NewAuditLogUseCase(repository.NewAuditLogRepository(db))
+	stateUseCase := usecase.NewStateUseCase(repository.NewStateRepository(db)).WithAudit(auditUseCase)
+	ctx := context.WithValue(context.Background(), "userId", uint(7))
+	ctx = context.WithValue(ctx, "requestId", "request-1")
+
+	state := entity.NewState("Tehran")
+	assert.NoError(t, stateUseCase.Create(ctx, &state))
+	assert.NoError(t, stateUseCase.Update(ctx, state.ID, map[string]any{"title": "Alborz"}))
+	assert.NoError(t, stateUseCase.DeleteById(ctx, state.ID))
+
+	auditLogs, err := auditUseCase.ByTarget(ctx, "state", state.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(auditLogs))
+	assert.Equal(t, entity.CreateStateAction, auditLogs[0].Action)
+	assert.Equal(t, entity.DeleteStateAction, auditLogs[2].Action)
+
+	update := auditLogs[1]
+	assert.Equal(t, entity.UpdateStateAction, update.Action)
+	assert.Equal(t, uint(7), update.ActorID)
+	assert.Equal(t, "request-1", update.RequestID)
+	changes := map[string]map[string]any{}
+	assert.NoError(t, json.Unmarshal([]byte(update.Changes), &changes))
+	assert.Equal(t, map[string]map[string]any{"Title": {"before": "Tehran", "after": "Alborz"}}, changes)
+}
+
+func TestAuditLogUseCase_ImpersonatorIsActor(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	auditUseCase := usecase.NewAuditLogUseCase(repository.NewAuditLogRepository(db))
+	ctx := context.WithValue(context.Background(), "userId", uint(7))
+	ctx = context.WithValue(ctx, "actorId", uint(2))
+
+	assert.NoError(t, auditUseCase.Audit(ctx, entity.UpdateUserAction, "user", 7, nil, nil))
+	auditLogs, _ := auditUseCase.ByTarget(ctx, "user", 7)
+	assert.Equal(t, uint(2), auditLogs[0].ActorID)
+	assert.Equal(t, "{}", auditLogs[0].Changes)
+}
+
+func TestAuditLogUseCase_Purge(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	auditUseCase := usecase.NewAuditLogUseCase(repository.NewAuditLogRepository(db))
+	auditLog := entity.NewAuditLog(1, entity.UpdateUserAction, "user", 2, "")
+	auditUseCase.Repo.Save(context.TODO(), &auditLog)
+
+	purged, err := auditUseCase.Purge(context.TODO(), time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, purged)
+	purged, err = auditUseCase.Purge(context.TODO(), -time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+}
@@ -34,7 +34,7 @@ func TestCityUseCase_Create(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, citiesCount, 0)
 
-	city, err := cityUseCase.Create(ctx, "something", state)
+	city, err := cityUseCase.Create(ctx, "something", state, entity.Location{})
 	assert.NoError(t, err)
 	assert.Equal(t, city.Title, "something")
 
@@ -96,7 +96,7 @@ func TestCityUseCase_DoesCityExist(t *testing.T) {
 	result := cityUseCase.DoesCityExist(ctx, 1, 1)
 	assert.False(t, result)
 
-	otherSate := entity.NewState("something")
+	otherSate := entity.NewState("something else")
 	err = stateRepo.Save(ctx, &otherSate).Error
 	assert.NoError(t, err)
 
@@ -188,3 +188,118 @@ func TestCityUseCase_DeleteById(t *testing.T) {
 
 	assert.Equal(t, countAfterDelete, count-1)
 }
+
+func TestCityUseCase_RestoreAndPurge(t *testing.T) {
+	ctx := context.TODO()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	stateUseCase := usecase.NewStateUseCase(repository.NewStateRepository(db))
+	cityUseCase := usecase.NewCityUseCase(repository.NewCityRepository(db))
+	state := entity.NewState("Tehran")
+	stateUseCase.Create(ctx, &state)
+	city, _ := cityUseCase.Create(ctx, "Rey", state, entity.Location{})
+
+	assert.NoError(t, cityUseCase.DeleteById(ctx, city.ID))
+	assert.NoError(t, stateUseCase.DeleteById(ctx, state.ID))
+	assert.ErrorIs(t, stateUseCase.Purge(ctx, state.ID), usecase.ErrStateHasCities)
+
+	cities, count, err := cityUseCase.Trashed(ctx, state.ID, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, city.ID, cities[0].ID)
+
+	_, err = cityUseCase.Restore(ctx, city.ID)
+	assert.ErrorIs(t, err, usecase.ErrStateDeleted)
+	_, err = stateUseCase.Restore(ctx, state.ID)
+	assert.NoError(t, err)
+	restored, err := cityUseCase.Restore(ctx, city.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Tehran", restored.State.Title)
+	_, err = cityUseCase.Restore(ctx, city.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	cityUseCase.DeleteById(ctx, city.ID)
+	stateUseCase.DeleteById(ctx, state.ID)
+	assert.NoError(t, cityUseCase.Purge(ctx, city.ID))
+	assert.NoError(t, stateUseCase.Purge(ctx, state.ID))
+	_, count, _ = stateUseCase.Trashed(ctx, 1, 10)
+	assert.Equal(t, 0, count)
+}
+
+func TestCityUseCase_Nearby(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	database.Migrate(db)
+	ctx := context.TODO()
+	cities := usecase.NewCityUseCase(repository.NewCityRepository(db))
+	at := func(lat, lng float64) entity.Location {
+		return entity.Location{Latitude: &lat, Longitude: &lng}
+	}
+
+	state := entity.NewState("تهران")
+	assert.NoError(t, db.Create(&state).Error)
+	_, err = cities.Create(ctx, "Qom", state, at(34.6401, 50.8764))
+	assert.NoError(t, err)
+	_, err = cities.Create(ctx, "Rey", state, at(35.5961, 51.4340))
+	assert.NoError(t, err)
+	_, err = cities.Create(ctx, "Tabriz", state, at(38.0800, 46.2919))
+	assert.NoError(t, err)
+	_, err = cities.Create(ctx, "Unknown", state, entity.Location{})
+	assert.NoError(t, err)
+	lat := 35.0
+	_, err = cities.Create(ctx, "Broken", state, entity.Location{Latitude: &lat})
+	assert.Error(t, err)
+
+	nearby, err := cities.Nearby(ctx, 35.6892, 51.3890, 200, 10)
+	assert.NoError(t, err)
+	assert.Len(t, nearby, 2)
+	assert.Equal(t, "Rey", nearby[0].City.Title)
+	assert.Equal(t, "Qom", nearby[1].City.Title)
+	assert.InDelta(t, 125.4, nearby[1].Distance, 1)
+
+	nearby, err = cities.Nearby(ctx, 35.6892, 51.3890, 200, 1)
+	assert.NoError(t, err)
+	assert.Len(t, nearby, 1)
+
+	_, err = cities.Nearby(ctx, 95, 51, 10, 10)
+	assert.Error(t, err)
+	_, err = cities.Nearby(ctx, 35, 51, 0, 10)
+	assert.ErrorIs(t, err, usecase.ErrInvalidRadius)
+}
+
+func TestCityUseCase_DuplicateTitle(t *testing.T) {
+	ctx := context.TODO()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	assert.NoError(t, err)
+	database.Migrate(db)
+	stateRepo := repository.NewStateRepository(db)
+	useCase := usecase.NewCityUseCase(repository.NewCityRepository(db))
+
+	state := entity.NewState("Fars")
+	assert.NoError(t, stateRepo.Save(ctx, &state).Error)
+	other := entity.NewState("Isfahan")
+	assert.NoError(t, stateRepo.Save(ctx, &other).Error)
+
+	city, err := useCase.Create(ctx, "شیراز", state, entity.Location{})
+	assert.NoError(t, err)
+	_, err = useCase.Create(ctx, "شيراز ", state, entity.Location{})
+	assert.ErrorIs(t, err, usecase.ErrCityTitleTaken)
+	_, err = useCase.Create(ctx, "شیراز", other, entity.Location{})
+	assert.NoError(t, err)
+
+	second, err := useCase.Create(ctx, "Marvdasht", state, entity.Location{})
+	assert.NoError(t, err)
+	_, err = useCase.Update(ctx, second.ID, map[string]any{"title": "شیراز"})
+	assert.ErrorIs(t, err, usecase.ErrCityTitleTaken)
+	_, err = useCase.Update(ctx, city.ID, map[string]any{"title": "شیراز"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, useCase.DeleteById(ctx, second.ID))
+	_, err = useCase.Create(ctx, "MARVDASHT", state, entity.Location{})
+	assert.NoError(t, err)
+	_, err = useCase.Restore(ctx, second.ID)
+	assert.ErrorIs(t, err, usecase.ErrCityTitleTaken)
+}
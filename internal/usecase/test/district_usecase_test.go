@@ -0,0 +1,116 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestDistrictUseCase_CRUD(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	database.Migrate(db)
+	ctx := context.TODO()
+
+	state := entity.NewState("تهران")
+	assert.NoError(t, db.Create(&state).Error)
+	city := entity.NewCity("تهران", state)
+	assert.NoError(t, db.Create(&city).Error)
+
+	districts := usecase.NewDistrictUseCase(repository.NewDistrictRepository(db))
+	neighborhoods := usecase.NewNeighborhoodUseCase(repository.NewNeighborhoodRepository(db))
+
+	first, err := districts.Create(ctx, "منطقه ۱", city)
+	assert.NoError(t, err)
+	_, err = districts.Create(ctx, "منطقه ۲", city)
+	assert.NoError(t, err)
+
+	list, count, err := districts.List(ctx, city.ID, 1, 1, "منطقه")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Len(t, list, 1)
+	assert.Equal(t, first.ID, list[0].ID)
+
+	updated, err := districts.Update(ctx, first.ID, map[string]any{"title": "منطقه یک"})
+	assert.NoError(t, err)
+	assert.Equal(t, "منطقه یک", updated.Title)
+	assert.Equal(t, []entity.PathNode{
+		{Type: "state", ID: state.ID, Title: "تهران"},
+		{Type: "city", ID: city.ID, Title: "تهران"},
+	}, updated.Path())
+
+	tajrish, err := neighborhoods.Create(ctx, "تجریش", updated)
+	assert.NoError(t, err)
+	tajrish, err = neighborhoods.ById(ctx, tajrish.ID)
+	assert.NoError(t, err)
+	path := tajrish.Path()
+	assert.Len(t, path, 3)
+	assert.Equal(t, entity.PathNode{Type: "district", ID: first.ID, Title: "منطقه یک"}, path[2])
+
+	assert.NoError(t, neighborhoods.DeleteById(ctx, tajrish.ID))
+	_, err = neighborhoods.ById(ctx, tajrish.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestLocationUseCase_Tree(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	database.Migrate(db)
+	ctx := context.TODO()
+	locations := newLocationUseCase(db)
+
+	state := entity.NewState("تهران")
+	assert.NoError(t, db.Create(&state).Error)
+	tehran := entity.NewCity("تهران", state)
+	assert.NoError(t, db.Create(&tehran).Error)
+	rey := entity.NewCity("ری", state)
+	assert.NoError(t, db.Create(&rey).Error)
+	district := entity.NewDistrict("منطقه ۱", tehran)
+	assert.NoError(t, db.Create(&district).Error)
+	neighborhood := entity.NewNeighborhood("تجریش", district)
+	assert.NoError(t, db.Create(&neighborhood).Error)
+
+	tree, err := locations.Tree(ctx, state.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, state.ID, tree.State.ID)
+	assert.Len(t, tree.Cities, 2)
+	assert.Len(t, tree.Cities[0].Districts, 1)
+	assert.Empty(t, tree.Cities[1].Districts)
+	assert.Equal(t, "تجریش", tree.Cities[0].Districts[0].Neighborhoods[0].Title)
+
+	_, err = locations.Tree(ctx, 404)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestCityUseCase_PurgeWithDistricts(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	database.Migrate(db)
+	ctx := context.TODO()
+	cities := usecase.NewCityUseCase(repository.NewCityRepository(db))
+	districts := usecase.NewDistrictUseCase(repository.NewDistrictRepository(db))
+
+	state := entity.NewState("تهران")
+	assert.NoError(t, db.Create(&state).Error)
+	city, err := cities.Create(ctx, "تهران", state, entity.Location{})
+	assert.NoError(t, err)
+	district, err := districts.Create(ctx, "منطقه ۱", city)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Create(&entity.Neighborhood{Title: "تجریش", DistrictID: district.ID}).Error)
+
+	assert.NoError(t, cities.DeleteById(ctx, city.ID))
+	assert.ErrorIs(t, cities.Purge(ctx, city.ID), usecase.ErrCityHasDistricts)
+
+	assert.NoError(t, districts.DeleteById(ctx, district.ID))
+	assert.NoError(t, cities.Purge(ctx, city.ID))
+	var remaining int64
+	db.Unscoped().Model(&entity.Neighborhood{}).Count(&remaining)
+	assert.Zero(t, remaining)
+}
@@ -0,0 +1,83 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestGeoDataUseCase(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	ctx := context.TODO()
+	dataset := entity.GeoDataset{
+		Version: "test",
+		States: []entity.DatasetState{
+			{Code: "IR-23", Title: "تهران", EnglishTitle: "Tehran", Cities: []entity.DatasetCity{
+				{Code: "IR-23-01", Title: "تهران", EnglishTitle: "Tehran"},
+				{Code: "IR-23-02", Title: "ری", EnglishTitle: "Rey"},
+			}},
+			{Code: "IR-25", Title: "قم", EnglishTitle: "Qom", Cities: []entity.DatasetCity{
+				{Code: "IR-25-01", Title: "قم", EnglishTitle: "Qom"},
+			}},
+		},
+	}
+	geoData := usecase.NewGeoDataUseCase(repository.NewGeoDataRepository(db), dataset)
+
+	// typed by hand with the Arabic yeh
+	tehran := entity.NewState("تهران")
+	assert.NoError(t, db.Create(&tehran).Error)
+	rey := entity.City{Title: "ري", StateID: tehran.ID}
+	assert.NoError(t, db.Create(&rey).Error)
+	other := entity.NewState("Somewhere")
+	assert.NoError(t, db.Create(&other).Error)
+
+	diff, err := geoData.Diff(ctx)
+	assert.NoError(t, err)
+	assert.False(t, diff.InSync())
+	assert.Equal(t, 1, diff.MatchedStates)
+	assert.Len(t, diff.MissingStates, 1)
+	assert.Len(t, diff.ChangedStates, 1)
+	assert.True(t, diff.ChangedStates[0].Unlinked)
+	assert.Len(t, diff.UnknownStates, 1)
+	assert.Len(t, diff.MissingCities, 2)
+	assert.Len(t, diff.ChangedCities, 1)
+
+	result, err := geoData.Seed(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.GeoSeedResult{Version: "test", CreatedStates: 1, LinkedStates: 1, CreatedCities: 2, LinkedCities: 1}, result)
+	result, err = geoData.Seed(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.GeoSeedResult{Version: "test"}, result)
+
+	linked := entity.City{}
+	assert.NoError(t, db.First(&linked, rey.ID).Error)
+	assert.Equal(t, "IR-23-02", linked.Code)
+	assert.Equal(t, "ري", linked.Title)
+	qom := entity.State{}
+	assert.NoError(t, db.Preload("Translations").First(&qom, "code = ?", "IR-25").Error)
+	assert.Equal(t, "Qom", qom.TitleIn(entity.EnglishLocale))
+	var cityTranslations int64
+	db.Model(&entity.CityTranslation{}).Where("locale = ?", entity.EnglishLocale).Count(&cityTranslations)
+	assert.Equal(t, int64(2), cityTranslations)
+
+	diff, err = geoData.Diff(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, diff.MatchedStates)
+	assert.Equal(t, 3, diff.MatchedCities)
+	assert.Empty(t, diff.MissingStates)
+	assert.Empty(t, diff.ChangedStates)
+	assert.Empty(t, diff.ChangedCities)
+	assert.Len(t, diff.UnknownStates, 1)
+	assert.Equal(t, other.ID, diff.UnknownStates[0].ID)
+}
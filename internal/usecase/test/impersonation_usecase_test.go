@@ -0,0 +1,52 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestImpersonationUseCase(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	useCase := usecase.NewImpersonationUseCase(repository.NewImpersonationRepository(db), config.Impersonation{TTL: time.Minute}, "secretestkey")
+	ctx := context.TODO()
+	subject := entity.NewUser("", "+989120000001", entity.UserRole)
+	subject.ID = 2
+
+	_, _, err = useCase.Start(ctx, 2, subject, "ticket 42")
+	assert.ErrorIs(t, err, usecase.ErrImpersonateSelf)
+
+	impersonation, token, err := useCase.Start(ctx, 1, subject, "ticket 42")
+	assert.NoError(t, err)
+	claims, err := utils.ValidateToken("secretestkey", token)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), claims["userId"])
+	assert.Equal(t, float64(1), claims["actorId"])
+	assert.Equal(t, float64(impersonation.ID), claims["impersonationId"])
+
+	active, err := useCase.Active(ctx, impersonation.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), active.ActorID)
+
+	ended, err := useCase.End(ctx, impersonation.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, ended.EndedAt)
+	_, err = useCase.Active(ctx, impersonation.ID)
+	assert.ErrorIs(t, err, usecase.ErrImpersonationEnded)
+	_, err = useCase.End(ctx, impersonation.ID)
+	assert.ErrorIs(t, err, usecase.ErrImpersonationEnded)
+}
@@ -0,0 +1,122 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newLocationUseCase(db *gorm.DB) usecase.LocationUseCase {
+	return usecase.NewLocationUseCase(
+		repository.NewStateRepository(db),
+		repository.NewCityRepository(db),
+		repository.NewDistrictRepository(db),
+		repository.NewNeighborhoodRepository(db),
+		repository.NewGeoDataRepository(db),
+	)
+}
+
+func TestLocationUseCase_ImportByTitle(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	locations := newLocationUseCase(db)
+	ctx := context.TODO()
+	tehran := entity.NewState("تهران")
+	assert.NoError(t, db.Create(&tehran).Error)
+
+	rows := []entity.LocationRow{
+		{Location: "line 2", StateTitle: "تهران", StateCode: "IR-23", CityTitle: "ری"},
+		{Location: "line 3", StateTitle: "تهران", CityTitle: "ري"},
+		{Location: "line 4", StateTitle: "", CityTitle: "قم"},
+	}
+	result, err := locations.Import(ctx, rows, entity.ImportByTitle, false)
+	assert.ErrorIs(t, err, usecase.ErrInvalidImport)
+	assert.Equal(t, []entity.ImportError{
+		{Location: "line 3", Field: "city_title", Message: "the city is already listed at line 2"},
+		{Location: "line 4", Field: "state_title", Message: "state title is required"},
+	}, result.Errors)
+
+	rows = rows[:1]
+	result, err = locations.Import(ctx, rows, entity.ImportByTitle, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.UpdatedStates)
+	assert.Equal(t, 1, result.CreatedCities)
+	result, err = locations.Import(ctx, rows, entity.ImportByTitle, false)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ImportResult{Rows: 1}, result)
+
+	state := entity.State{}
+	assert.NoError(t, db.First(&state, tehran.ID).Error)
+	assert.Equal(t, "IR-23", state.Code)
+
+	other := entity.State{Title: "Other", Code: "IR-99"}
+	assert.NoError(t, db.Create(&other).Error)
+	_, err = locations.Import(ctx, []entity.LocationRow{{Location: "line 2", StateTitle: "تهران", StateCode: "IR-99"}}, entity.ImportByTitle, false)
+	assert.ErrorIs(t, err, usecase.ErrInvalidImport)
+}
+
+func TestLocationUseCase_ImportByCode(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	locations := newLocationUseCase(db)
+	ctx := context.TODO()
+
+	_, err = locations.Import(ctx, nil, "name", false)
+	assert.ErrorIs(t, err, usecase.ErrInvalidImportMode)
+
+	rows := []entity.LocationRow{
+		{Location: "[0].cities[0]", StateCode: "IR-23", StateTitle: "Tehran", CityCode: "IR-23-01", CityTitle: "Tehran"},
+		{Location: "[1].cities[0]", StateCode: "IR-25", StateTitle: "Qom", CityCode: "IR-25-01", CityTitle: "Qom"},
+	}
+	result, err := locations.Import(ctx, rows, entity.ImportByCode, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.CreatedStates)
+	var count int64
+	db.Model(&entity.State{}).Count(&count)
+	assert.Zero(t, count)
+	_, err = locations.Import(ctx, rows, entity.ImportByCode, false)
+	assert.NoError(t, err)
+
+	// renames Tehran and moves the Qom city under it
+	rows = []entity.LocationRow{
+		{Location: "[0].cities[0]", StateCode: "IR-23", StateTitle: "تهران", CityCode: "IR-23-01", CityTitle: "تهران"},
+		{Location: "[0].cities[1]", StateCode: "IR-23", StateTitle: "تهران", CityCode: "IR-25-01", CityTitle: "قم"},
+	}
+	result, err = locations.Import(ctx, rows, entity.ImportByCode, false)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ImportResult{Rows: 2, UpdatedStates: 1, UpdatedCities: 2}, result)
+
+	exported := map[string][]string{}
+	err = locations.Export(ctx, 1, func(state entity.State, cities []entity.City) error {
+		exported[state.Title] = []string{}
+		for _, city := range cities {
+			exported[state.Title] = append(exported[state.Title], city.Title)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"تهران": {"تهران", "قم"}, "Qom": {}}, exported)
+
+	rows = []entity.LocationRow{
+		{Location: "[0]", StateCode: "IR-99", StateTitle: "QOM"},
+		{Location: "[1].cities[0]", StateCode: "IR-23", StateTitle: "تهران", CityCode: "IR-23-99", CityTitle: "قم"},
+	}
+	result, err = locations.Import(ctx, rows, entity.ImportByCode, true)
+	assert.ErrorIs(t, err, usecase.ErrInvalidImport)
+	assert.Len(t, result.Errors, 2)
+	assert.Equal(t, "state_title", result.Errors[0].Field)
+	assert.Equal(t, "city_title", result.Errors[1].Field)
+}
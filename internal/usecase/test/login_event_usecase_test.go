@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newLoginEventUseCase() usecase.LoginEventUseCase {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	conf := config.LoginMonitor{FailureBurst: 3, FailureWindow: time.Minute}
+	return usecase.NewLoginEventUseCase(repository.NewLoginEventRepository(db), conf)
+}
+
+func TestLoginEventUseCase_SuccessAnomalies(t *testing.T) {
+	useCase := newLoginEventUseCase()
+	ctx := context.TODO()
+	login := func(ip, userAgent string) entity.LoginEvent {
+		event := entity.NewLoginEvent(1, "+989120000001", entity.LoginSucceededEvent, ip, userAgent)
+		assert.NoError(t, useCase.Record(ctx, &event, "IR"))
+		return event
+	}
+
+	first := login("2.180.0.1", "phone")
+	assert.Equal(t, "IR", first.Country)
+	assert.False(t, first.IsSuspicious())
+	assert.False(t, login("5.112.0.1", "phone").IsSuspicious())
+	assert.False(t, login("127.0.0.1", "phone").IsSuspicious())
+	assert.Equal(t, []string{entity.NewDeviceAnomaly}, login("2.180.0.1", "laptop").AnomalyList())
+	assert.Equal(t, []string{entity.UnusualCountryAnomaly}, login("86.130.0.1", "phone").AnomalyList())
+	assert.Equal(t, []string{entity.NewDeviceAnomaly, entity.UnusualCountryAnomaly}, login("8.8.8.8", "tablet").AnomalyList())
+	// the country is known from now on
+	assert.False(t, login("86.130.0.1", "laptop").IsSuspicious())
+}
+
+func TestLoginEventUseCase_FailureBurst(t *testing.T) {
+	useCase := newLoginEventUseCase()
+	ctx := context.TODO()
+	var flagged []bool
+	for i := 0; i < 4; i++ {
+		event := entity.NewLoginEvent(1, "+989120000001", entity.LoginFailedEvent, "2.180.0.1", "phone")
+		assert.NoError(t, useCase.Record(ctx, &event, "IR"))
+		flagged = append(flagged, event.IsSuspicious())
+	}
+	assert.Equal(t, []bool{false, false, true, false}, flagged)
+
+	queue, count, err := useCase.ReviewQueue(ctx, false, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	_, err = useCase.Review(ctx, queue[0].ID-1, 9, "ok")
+	assert.ErrorIs(t, err, usecase.ErrLoginNotSuspicious)
+	reviewed, err := useCase.Review(ctx, queue[0].ID, 9, "user mistyped")
+	assert.NoError(t, err)
+	assert.NotNil(t, reviewed.ReviewedAt)
+	_, err = useCase.Review(ctx, queue[0].ID, 9, "again")
+	assert.ErrorIs(t, err, usecase.ErrLoginAlreadyReviewed)
+	_, err = useCase.Review(ctx, 999, 9, "missing")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	history, count, err := useCase.History(ctx, 1, 2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+	assert.Len(t, history, 1)
+}
@@ -0,0 +1,131 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newOAuthUseCase(t *testing.T) (usecase.OAuthUseCase, repository.UserRepository, *miniredis.Miniredis) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	mr, err := miniredis.Run()
+	assert.NoError(t, err)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	userRepo := repository.NewUserRepository(db)
+	useCase := usecase.NewOAuthUseCase(
+		repository.NewOAuthClientRepository(db),
+		repository.NewOAuthTokenRepository(rdb),
+		userRepo,
+		config.OAuth{CodeTTL: 5 * time.Minute, AccessTokenTTL: time.Hour, RefreshTokenTTL: 720 * time.Hour},
+		"secretestkey",
+	)
+	return useCase, userRepo, mr
+}
+
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestOAuthUseCase_RegisterClient(t *testing.T) {
+	useCase, _, mr := newOAuthUseCase(t)
+	defer mr.Close()
+
+	_, _, err := useCase.RegisterClient(context.TODO(), "app", nil, []string{"unknown"}, true)
+	assert.ErrorIs(t, err, usecase.ErrOAuthInvalidScope)
+	_, _, err = useCase.RegisterClient(context.TODO(), "app", nil, []string{entity.ProfileScope}, false)
+	assert.Error(t, err)
+	_, _, err = useCase.RegisterClient(context.TODO(), "app", []string{"/relative"}, []string{entity.ProfileScope}, false)
+	assert.Error(t, err)
+
+	client, secret, err := useCase.RegisterClient(context.TODO(), "app", []string{"app://callback"}, []string{entity.ProfileScope}, true)
+	assert.NoError(t, err)
+	authenticated, err := useCase.AuthenticateClient(context.TODO(), client.ClientID, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, client.ID, authenticated.ID)
+	_, err = useCase.AuthenticateClient(context.TODO(), client.ClientID, "")
+	assert.ErrorIs(t, err, usecase.ErrOAuthInvalidClient)
+}
+
+func TestOAuthUseCase_AuthorizationCodeFlow(t *testing.T) {
+	useCase, userRepo, mr := newOAuthUseCase(t)
+	defer mr.Close()
+	user := entity.NewUser("something", "09120000001", entity.UserRole)
+	userRepo.Save(&user)
+	client, _, _ := useCase.RegisterClient(context.TODO(), "mobile", []string{"app://callback"}, []string{entity.ProfileScope}, false)
+	verifier := "a-long-enough-verifier-for-pkce-purposes-0123456789"
+
+	_, err := useCase.Authorize(context.TODO(), client.ClientID, user.ID, "app://other", "", codeChallenge(verifier), entity.PKCEMethodS256)
+	assert.ErrorIs(t, err, usecase.ErrOAuthRedirectURI)
+	_, err = useCase.Authorize(context.TODO(), client.ClientID, user.ID, "app://callback", "", "", "")
+	assert.ErrorIs(t, err, usecase.ErrOAuthPKCERequired)
+	_, err = useCase.Authorize(context.TODO(), client.ClientID, user.ID, "app://callback", entity.UsersReadPermission, codeChallenge(verifier), entity.PKCEMethodS256)
+	assert.ErrorIs(t, err, usecase.ErrOAuthInvalidScope)
+
+	code, err := useCase.Authorize(context.TODO(), client.ClientID, user.ID, "app://callback", "", codeChallenge(verifier), entity.PKCEMethodS256)
+	assert.NoError(t, err)
+	_, err = useCase.ExchangeCode(context.TODO(), client, code, "app://callback", "wrong-verifier")
+	assert.ErrorIs(t, err, usecase.ErrOAuthInvalidGrant)
+
+	code, _ = useCase.Authorize(context.TODO(), client.ClientID, user.ID, "app://callback", "", codeChallenge(verifier), entity.PKCEMethodS256)
+	tokens, err := useCase.ExchangeCode(context.TODO(), client, code, "app://callback", verifier)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ProfileScope, tokens.Scope)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	_, err = useCase.ExchangeCode(context.TODO(), client, code, "app://callback", verifier)
+	assert.ErrorIs(t, err, usecase.ErrOAuthInvalidGrant)
+
+	introspection, err := useCase.Introspect(context.TODO(), tokens.AccessToken)
+	assert.NoError(t, err)
+	assert.True(t, introspection.Active)
+	assert.Equal(t, user.ID, introspection.UserId)
+
+	refreshed, err := useCase.Refresh(context.TODO(), client, tokens.RefreshToken, "")
+	assert.NoError(t, err)
+	assert.NotEqual(t, tokens.RefreshToken, refreshed.RefreshToken)
+	_, err = useCase.Refresh(context.TODO(), client, tokens.RefreshToken, "")
+	assert.ErrorIs(t, err, usecase.ErrOAuthInvalidGrant)
+
+	assert.NoError(t, useCase.Revoke(context.TODO(), client, refreshed.AccessToken))
+	introspection, _ = useCase.Introspect(context.TODO(), refreshed.AccessToken)
+	assert.False(t, introspection.Active)
+	assert.NoError(t, useCase.Revoke(context.TODO(), client, refreshed.RefreshToken))
+	introspection, _ = useCase.Introspect(context.TODO(), refreshed.RefreshToken)
+	assert.False(t, introspection.Active)
+}
+
+func TestOAuthUseCase_ClientCredentials(t *testing.T) {
+	useCase, _, mr := newOAuthUseCase(t)
+	defer mr.Close()
+	confidential, _, _ := useCase.RegisterClient(context.TODO(), "agency", nil, []string{entity.UsersReadPermission, entity.ProfileScope}, true)
+	public, _, _ := useCase.RegisterClient(context.TODO(), "mobile", []string{"app://callback"}, []string{entity.UsersReadPermission}, false)
+
+	_, err := useCase.ClientCredentials(context.TODO(), public, "")
+	assert.ErrorIs(t, err, usecase.ErrOAuthUnauthorized)
+	_, err = useCase.ClientCredentials(context.TODO(), confidential, "")
+	assert.ErrorIs(t, err, usecase.ErrOAuthInvalidScope)
+
+	tokens, err := useCase.ClientCredentials(context.TODO(), confidential, entity.UsersReadPermission)
+	assert.NoError(t, err)
+	assert.Empty(t, tokens.RefreshToken)
+	introspection, _ := useCase.Introspect(context.TODO(), tokens.AccessToken)
+	assert.True(t, introspection.Active)
+	assert.Zero(t, introspection.UserId)
+}
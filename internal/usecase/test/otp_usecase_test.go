@@ -3,12 +3,14 @@ package usecase_test
 import (
 	"context"
 	"errors"
-	"fmt"
 	"testing"
 	"time"
 
+	"github.com/TheAmirhosssein/room-reservation-api/config"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/validators"
 	"github.com/alicebob/miniredis/v2"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
@@ -26,17 +28,23 @@ func TestOTPUseCase_GenerateCode(t *testing.T) {
 	})
 	mobileNumber := "09220002200"
 	otpRepo := repository.NewOTPCodeRepository(rdb)
-	otpUseCase := usecase.NewOTPCase(otpRepo)
-	code, err := otpUseCase.GenerateCode(ctx, mobileNumber)
+	otpUseCase := usecase.NewOTPCase(otpRepo, config.OTP{Length: 5, TTL: 2 * time.Minute})
+	code, err := otpUseCase.GenerateCode(ctx, mobileNumber, entity.LoginPurpose)
 	assert.NoErrorf(t, err, "An error occurred generating new otp code: %v", err)
+	assert.Equal(t, 5, len(code))
 
-	savedOTPCode, err := mr.Get(mobileNumber)
+	key := entity.OTPKey("+989220002200", entity.LoginPurpose)
+	savedOTPCode, err := mr.Get(key)
 	assert.NoErrorf(t, err, "An error occurred getting value from miniredis: %v", err)
-	assert.Equal(t, savedOTPCode, code)
+	assert.True(t, entity.MatchOTPCode(savedOTPCode, code))
+	assert.Equal(t, 2*time.Minute, mr.TTL(key))
 
 	expectedError := errors.New("please wait a minute to get new code")
-	_, err = otpUseCase.GenerateCode(ctx, mobileNumber)
+	_, err = otpUseCase.GenerateCode(ctx, mobileNumber, entity.LoginPurpose)
 	assert.EqualError(t, expectedError, err.Error(), "Expected error to match the expected error")
+
+	_, err = otpUseCase.GenerateCode(ctx, mobileNumber, entity.DeleteAccountPurpose)
+	assert.NoError(t, err, "codes for other purposes must be independent")
 }
 
 func TestOTPUseCase_IsCodeValid(t *testing.T) {
@@ -50,26 +58,34 @@ func TestOTPUseCase_IsCodeValid(t *testing.T) {
 		Addr: mr.Addr(),
 	})
 	otpRepo := repository.NewOTPCodeRepository(rdb)
-	otpUseCase := usecase.NewOTPCase(otpRepo)
+	otpUseCase := usecase.NewOTPCase(otpRepo, config.OTP{Length: 6, TTL: time.Minute})
 	mobileNumber := "09220002200"
 
-	code, err := otpUseCase.GenerateCode(ctx, mobileNumber)
+	code, err := otpUseCase.GenerateCode(ctx, mobileNumber, entity.LoginPurpose)
 	assert.NoError(t, err)
 
-	err = otpUseCase.ValidateCode(ctx, mobileNumber, code)
+	err = otpUseCase.ValidateCode(ctx, mobileNumber, entity.LoginPurpose, code)
 	assert.NoError(t, err)
 
-	savedCode, _ := mr.Get(mobileNumber)
-	fmt.Println(savedCode)
-	err = otpUseCase.ValidateCode(ctx, mobileNumber, code)
+	err = otpUseCase.ValidateCode(ctx, mobileNumber, entity.LoginPurpose, code)
 	assert.Error(t, err)
 
 	expectedError := errors.New("this code is invalid, please get new one")
-	err = otpUseCase.ValidateCode(ctx, "wrongnumber", code)
+	err = otpUseCase.ValidateCode(ctx, "09220002201", entity.LoginPurpose, code)
 	assert.EqualError(t, err, expectedError.Error(), "Expected error to match the expected error")
 
-	otpUseCase.GenerateCode(ctx, mobileNumber)
-	err = otpUseCase.ValidateCode(ctx, mobileNumber, "code")
+	err = otpUseCase.ValidateCode(ctx, "wrongnumber", entity.LoginPurpose, code)
+	assert.ErrorIs(t, err, validators.ErrInvalidMobileNumber)
+
+	otpUseCase.GenerateCode(ctx, mobileNumber, entity.LoginPurpose)
+	err = otpUseCase.ValidateCode(ctx, mobileNumber, entity.LoginPurpose, "code")
 	expectedError = errors.New("this code is incorrect")
 	assert.EqualError(t, err, expectedError.Error(), "Expected error to match the expected error")
+
+	deleteCode, err := otpUseCase.GenerateCode(ctx, mobileNumber, entity.DeleteAccountPurpose)
+	assert.NoError(t, err)
+	err = otpUseCase.ValidateCode(ctx, mobileNumber, entity.ChangeMobilePurpose, deleteCode)
+	assert.EqualError(t, err, "this code is invalid, please get new one", "codes must not be replayed for another purpose")
+	err = otpUseCase.ValidateCode(ctx, mobileNumber, entity.DeleteAccountPurpose, deleteCode)
+	assert.NoError(t, err)
 }
@@ -0,0 +1,142 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRoleUseCase_Create(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	useCase := usecase.NewRoleUseCase(repository.NewRoleRepository(db))
+
+	role, err := useCase.Create(context.TODO(), "Editor", []string{entity.SettingsStatesWritePermission})
+	assert.NoError(t, err)
+	assert.NotZero(t, role.ID)
+	assert.True(t, useCase.DoesRoleExist(context.TODO(), "Editor"))
+
+	_, err = useCase.Create(context.TODO(), "Editor", nil)
+	assert.ErrorIs(t, err, usecase.ErrRoleExists)
+
+	_, err = useCase.Create(context.TODO(), "Other", []string{"unknown.permission"})
+	assert.Error(t, err)
+}
+
+func TestRoleUseCase_HasPermissionAndCanGrant(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	useCase := usecase.NewRoleUseCase(repository.NewRoleRepository(db))
+
+	allowed, err := useCase.HasPermission(context.TODO(), entity.SupportRole, entity.UsersReadPermission)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = useCase.HasPermission(context.TODO(), entity.UserRole, entity.UsersReadPermission)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	allowed, err = useCase.HasPermission(context.TODO(), "Unknown", entity.UsersReadPermission)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	grantable, err := useCase.CanGrant(context.TODO(), entity.SupportRole, entity.AdminRole)
+	assert.NoError(t, err)
+	assert.False(t, grantable)
+	grantable, err = useCase.CanGrant(context.TODO(), entity.AdminRole, entity.SupportRole)
+	assert.NoError(t, err)
+	assert.True(t, grantable)
+}
+
+func TestRoleUseCase_DeleteById(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	useCase := usecase.NewRoleUseCase(repository.NewRoleRepository(db))
+	userRepo := repository.NewUserRepository(db)
+
+	admin, err := useCase.ByName(context.TODO(), entity.AdminRole)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, useCase.DeleteById(context.TODO(), admin.ID), usecase.ErrDefaultRole)
+
+	role, _ := useCase.Create(context.TODO(), "Editor", nil)
+	user := entity.NewUser("something", "09900302020", role.Name)
+	userRepo.Save(&user)
+	assert.ErrorIs(t, useCase.DeleteById(context.TODO(), role.ID), usecase.ErrRoleInUse)
+
+	userRepo.Update(&user, map[string]any{"role": entity.UserRole})
+	assert.NoError(t, useCase.DeleteById(context.TODO(), role.ID))
+	assert.False(t, useCase.DoesRoleExist(context.TODO(), "Editor"))
+}
+
+func TestRoleUseCase_UpdatePermissions(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	useCase := usecase.NewRoleUseCase(repository.NewRoleRepository(db))
+
+	admin, err := useCase.ByName(context.TODO(), entity.AdminRole)
+	assert.NoError(t, err)
+	_, err = useCase.UpdatePermissions(context.TODO(), admin.ID, []string{entity.UsersReadPermission})
+	assert.ErrorIs(t, err, usecase.ErrBuiltInRole)
+	admin, _ = useCase.ByName(context.TODO(), entity.AdminRole)
+	assert.Equal(t, len(entity.AllPermissions), len(admin.Permissions))
+
+	role, _ := useCase.Create(context.TODO(), "Editor", nil)
+	role, err = useCase.UpdatePermissions(context.TODO(), role.ID, []string{entity.SettingsStatesWritePermission})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{entity.SettingsStatesWritePermission}, role.PermissionNames())
+}
+
+func TestRoleUseCase_Cache(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	mr, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer mr.Close()
+	cache := repository.NewRoleCacheRepository(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	roleRepo := repository.NewRoleRepository(db)
+	useCase := usecase.NewCachedRoleUseCase(roleRepo, cache)
+	ctx := context.TODO()
+
+	support, err := useCase.ByName(ctx, entity.SupportRole)
+	assert.NoError(t, err)
+	assert.True(t, mr.Exists("role:"+entity.SupportRole))
+
+	// changes made behind the use case are only seen once the cache expires
+	roleRepo.ReplacePermissions(ctx, &support, []string{entity.UsersReadPermission})
+	cached, _ := useCase.ByName(ctx, entity.SupportRole)
+	assert.True(t, cached.HasPermission(entity.UsersDeletePermission))
+
+	_, err = useCase.UpdatePermissions(ctx, support.ID, []string{entity.RolesReadPermission})
+	assert.NoError(t, err)
+	updated, _ := useCase.ByName(ctx, entity.SupportRole)
+	assert.Equal(t, []string{entity.RolesReadPermission}, updated.PermissionNames())
+
+	_, err = useCase.ByName(ctx, "Missing")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.False(t, mr.Exists("role:Missing"))
+}
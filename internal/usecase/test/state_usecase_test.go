@@ -147,3 +147,80 @@ func TestStateUseCase_DeleteById(t *testing.T) {
 
 	assert.Equal(t, countAfterDelete, count-1)
 }
+
+func TestStateUseCase_DuplicateTitle(t *testing.T) {
+	ctx := context.TODO()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	assert.NoError(t, err)
+	database.Migrate(db)
+	useCase := usecase.NewStateUseCase(repository.NewStateRepository(db))
+
+	state := entity.NewState("Kerman")
+	assert.NoError(t, useCase.Create(ctx, &state))
+	duplicate := entity.NewState(" KERMAN ")
+	assert.ErrorIs(t, useCase.Create(ctx, &duplicate), usecase.ErrStateTitleTaken)
+
+	persian := entity.NewState("كرمانشاه")
+	assert.NoError(t, useCase.Create(ctx, &persian))
+	duplicate = entity.NewState("کرمانشاه")
+	assert.ErrorIs(t, useCase.Create(ctx, &duplicate), usecase.ErrStateTitleTaken)
+
+	assert.ErrorIs(t, useCase.Update(ctx, persian.ID, map[string]any{"title": "kerman"}), usecase.ErrStateTitleTaken)
+	assert.NoError(t, useCase.Update(ctx, state.ID, map[string]any{"title": "kerman"}))
+
+	assert.NoError(t, useCase.DeleteById(ctx, state.ID))
+	other := entity.NewState("Kerman")
+	assert.NoError(t, useCase.Create(ctx, &other))
+	_, err = useCase.Restore(ctx, state.ID)
+	assert.ErrorIs(t, err, usecase.ErrStateTitleTaken)
+}
+
+func TestStateUseCase_DeleteWithCities(t *testing.T) {
+	ctx := context.TODO()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	assert.NoError(t, err)
+	database.Migrate(db)
+	useCase := usecase.NewStateUseCase(repository.NewStateRepository(db))
+	cities := usecase.NewCityUseCase(repository.NewCityRepository(db))
+	districts := usecase.NewDistrictUseCase(repository.NewDistrictRepository(db))
+
+	state := entity.NewState("Tehran")
+	assert.NoError(t, useCase.Create(ctx, &state))
+	target := entity.NewState("Alborz")
+	assert.NoError(t, useCase.Create(ctx, &target))
+	city, err := cities.Create(ctx, "Karaj", state, entity.Location{})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, useCase.DeleteById(ctx, state.ID), usecase.ErrStateInUse)
+	assert.ErrorIs(t, useCase.Delete(ctx, state.ID, "archive", 0), usecase.ErrInvalidDeletionMode)
+	assert.ErrorIs(t, useCase.Delete(ctx, state.ID, entity.ReassignDeletion, state.ID), usecase.ErrInvalidReassignment)
+	assert.ErrorIs(t, useCase.Delete(ctx, state.ID, entity.ReassignDeletion, 404), usecase.ErrInvalidReassignment)
+
+	taken, err := cities.Create(ctx, "karaj", target, entity.Location{})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, useCase.Delete(ctx, state.ID, entity.ReassignDeletion, target.ID), usecase.ErrCityTitleTaken)
+	assert.NoError(t, cities.DeleteById(ctx, taken.ID))
+	deleted, err := cities.Create(ctx, "Shahriar", state, entity.Location{})
+	assert.NoError(t, err)
+	assert.NoError(t, cities.DeleteById(ctx, deleted.ID))
+
+	assert.NoError(t, useCase.Delete(ctx, state.ID, entity.ReassignDeletion, target.ID))
+	moved, err := cities.ById(ctx, city.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, target.ID, moved.StateID)
+	deleted, err = cities.TrashedById(ctx, deleted.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, state.ID, deleted.StateID)
+	assert.False(t, useCase.DoesStateExist(ctx, state.ID))
+
+	district, err := districts.Create(ctx, "Mehrshahr", moved)
+	assert.NoError(t, err)
+	assert.NoError(t, useCase.WithDeletionMode(entity.CascadeDeletion).DeleteById(ctx, target.ID))
+	_, err = cities.ById(ctx, city.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = districts.ById(ctx, district.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	trashed, err := cities.TrashedById(ctx, city.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Karaj", trashed.Title)
+}
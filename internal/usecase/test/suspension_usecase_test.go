@@ -0,0 +1,54 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSuspensionUseCase(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	userRepo := repository.NewUserRepository(db)
+	useCase := usecase.NewSuspensionUseCase(repository.NewSuspensionRepository(db), userRepo)
+	user := entity.NewUser("", "09120000001", entity.UserRole)
+	userRepo.Save(&user)
+	ctx := context.TODO()
+
+	_, err = useCase.Suspend(ctx, 99, user.ID, "bored", "", nil)
+	assert.ErrorIs(t, err, usecase.ErrInvalidSuspensionReason)
+	past := time.Now().Add(-time.Minute)
+	_, err = useCase.Suspend(ctx, 99, user.ID, entity.SpamSuspensionReason, "", &past)
+	assert.ErrorIs(t, err, usecase.ErrSuspensionEnd)
+	_, err = useCase.Suspend(ctx, user.ID, user.ID, entity.SpamSuspensionReason, "", nil)
+	assert.ErrorIs(t, err, usecase.ErrSuspendSelf)
+	_, err = useCase.Unsuspend(ctx, 99, user.ID, "")
+	assert.ErrorIs(t, err, usecase.ErrNotSuspended)
+	_, err = useCase.Suspend(ctx, 99, 12345, entity.SpamSuspensionReason, "", nil)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	endsAt := time.Now().Add(time.Hour)
+	suspended, err := useCase.Suspend(ctx, 99, user.ID, entity.SpamSuspensionReason, "bulk messages", &endsAt)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SuspendedStatus, suspended.Status(time.Now()))
+
+	lifted, err := useCase.Unsuspend(ctx, 99, user.ID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ActiveStatus, lifted.Status(time.Now()))
+
+	history, err := useCase.History(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Equal(t, "bulk messages", history[0].Notes)
+}
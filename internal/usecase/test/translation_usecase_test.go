@@ -0,0 +1,58 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestUpdateTranslations(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	database.Migrate(db)
+	ctx := context.TODO()
+	states := usecase.NewStateUseCase(repository.NewStateRepository(db))
+	cities := usecase.NewCityUseCase(repository.NewCityRepository(db))
+
+	state := entity.NewState("تهران")
+	assert.NoError(t, states.Create(ctx, &state))
+	city, err := cities.Create(ctx, "ری", state, entity.Location{})
+	assert.NoError(t, err)
+
+	_, err = states.UpdateTranslations(ctx, state.ID, map[string]string{"fa": "تهران"})
+	assert.ErrorIs(t, err, usecase.ErrInvalidTranslation)
+	_, err = states.UpdateTranslations(ctx, state.ID, map[string]string{"de": "Teheran"})
+	assert.ErrorIs(t, err, usecase.ErrInvalidTranslation)
+
+	updated, err := states.UpdateTranslations(ctx, state.ID, map[string]string{"en": "Tehran", "ar": "طهران"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"en": "Tehran", "ar": "طهران"}, updated.TranslationTitles())
+	updated, err = states.UpdateTranslations(ctx, state.ID, map[string]string{"en": "Tehran"})
+	assert.NoError(t, err)
+	assert.Equal(t, "تهران", updated.TitleIn(entity.ArabicLocale))
+
+	_, err = cities.UpdateTranslations(ctx, city.ID, map[string]string{"en": "Rey"})
+	assert.NoError(t, err)
+	city, err = cities.ById(ctx, city.ID)
+	assert.NoError(t, err)
+	localized := city.Localized(entity.EnglishLocale)
+	assert.Equal(t, "Rey", localized.Title)
+	assert.Equal(t, "Tehran", localized.State.Title)
+
+	found, err := states.GetStateList(ctx, 1, 10, "Teh")
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	foundCities, err := cities.CityList(ctx, 1, 10, int(state.ID), "Re")
+	assert.NoError(t, err)
+	assert.Len(t, foundCities, 1)
+	foundCities, err = cities.CityList(ctx, 1, 10, int(state.ID), "Qom")
+	assert.NoError(t, err)
+	assert.Empty(t, foundCities)
+}
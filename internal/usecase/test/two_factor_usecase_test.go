@@ -0,0 +1,52 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/usecase"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestTwoFactorUseCase_EnrollAndVerify(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	useCase := usecase.NewTwoFactorUseCase(repository.NewTwoFactorRepository(db))
+	ctx := context.TODO()
+
+	assert.ErrorIs(t, useCase.Confirm(ctx, 1, "123456"), usecase.ErrTwoFactorNotEnrolled)
+	twoFactor, recoveryCodes, err := useCase.Enroll(ctx, 1)
+	assert.NoError(t, err)
+	enabled, _ := useCase.IsEnabled(ctx, 1)
+	assert.False(t, enabled)
+	assert.ErrorIs(t, useCase.Verify(ctx, 1, "123456", ""), usecase.ErrTwoFactorNotEnrolled)
+
+	now := time.Now()
+	code, _ := totp.Code(twoFactor.Secret, totp.Step(now))
+	assert.NoError(t, useCase.Confirm(ctx, 1, code))
+	enabled, _ = useCase.IsEnabled(ctx, 1)
+	assert.True(t, enabled)
+	_, _, err = useCase.Enroll(ctx, 1)
+	assert.ErrorIs(t, err, usecase.ErrTwoFactorEnabled)
+
+	assert.ErrorIs(t, useCase.Verify(ctx, 1, "", ""), usecase.ErrTwoFactorRequired)
+	assert.ErrorIs(t, useCase.Verify(ctx, 1, code, ""), usecase.ErrTwoFactorInvalid, "the confirmation code was already used")
+	nextCode, _ := totp.Code(twoFactor.Secret, totp.Step(now)+1)
+	assert.NoError(t, useCase.Verify(ctx, 1, nextCode, ""))
+
+	assert.NoError(t, useCase.Verify(ctx, 1, "", recoveryCodes[0]))
+	assert.ErrorIs(t, useCase.Verify(ctx, 1, "", recoveryCodes[0]), usecase.ErrTwoFactorInvalid)
+
+	assert.NoError(t, useCase.Reset(ctx, 1))
+	enabled, _ = useCase.IsEnabled(ctx, 1)
+	assert.False(t, enabled)
+}
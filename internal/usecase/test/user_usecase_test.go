@@ -1,7 +1,9 @@
 package usecase_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/infrastructure/database"
@@ -21,12 +23,12 @@ func TestUserUseCase_GetUserOrCreate(t *testing.T) {
 	repo := repository.NewUserRepository(db)
 	userUseCase := usecase.NewUserUseCase(repo)
 
-	mobileNumber := "090012305412"
+	mobileNumber := "09001230541"
 	var count int64
 	db.Model(&entity.User{}).Count(&count)
 	user, err := userUseCase.GetUserOrCreate(mobileNumber)
 	assert.NoError(t, err)
-	assert.Equal(t, user.MobileNumber, mobileNumber)
+	assert.Equal(t, user.MobileNumber, "+989001230541")
 
 	var countAfter int64
 	db.Model(&entity.User{}).Count(&countAfter)
@@ -34,6 +36,9 @@ func TestUserUseCase_GetUserOrCreate(t *testing.T) {
 	assert.Equal(t, count, countAfter)
 	_, err = userUseCase.GetUserOrCreate(mobileNumber)
 	assert.NoError(t, err)
+	_, err = userUseCase.GetUserOrCreate("+989001230541")
+	assert.NoError(t, err)
+	db.Model(&entity.User{}).Count(&countAfter)
 	assert.Equal(t, count, countAfter)
 }
 
@@ -89,7 +94,7 @@ func TestUserUseCase_Update(t *testing.T) {
 	err = repo.Save(&user)
 
 	assert.NoError(t, err)
-	err = useCase.Update(user.ID, map[string]any{"FullName": "something else"})
+	err = useCase.Update(context.Background(), user.ID, map[string]any{"FullName": "something else"})
 	assert.NoError(t, err)
 	repo.ById(user.ID, &user)
 	assert.Equal(t, user.FullName, "something else")
@@ -107,7 +112,7 @@ func TestUserUseCase_DeleteById(t *testing.T) {
 	var count int64
 	db.Model(&entity.User{}).Count(&count)
 
-	err = useCase.DeleteById(user.ID)
+	err = useCase.DeleteById(context.Background(), user.ID)
 	assert.NoError(t, err)
 	var countAfterDelete int64
 	db.Model(&entity.User{}).Count(&countAfterDelete)
@@ -132,11 +137,11 @@ func TestUserUseCase_GetUsersList(t *testing.T) {
 	var count int64
 	db.Model(&entity.User{}).Count(&count)
 
-	users, err := useCase.GetUsersList(1, 1, "", "")
+	users, err := useCase.GetUsersList(1, 1, "", "", "")
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(users))
 
-	users, err = useCase.GetUsersList(1, 10, "09900302023", "something else")
+	users, err = useCase.GetUsersList(1, 10, "09900302023", "something else", "")
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(users))
 }
@@ -155,3 +160,114 @@ func TestUserUseCase_Count(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, count, 1)
 }
+
+func TestUserUseCase_ChangeMobileNumber(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	database.Migrate(db)
+	repo := repository.NewUserRepository(db)
+	useCase := usecase.NewUserUseCase(repo)
+
+	user := entity.NewUser("something", "09900302020", entity.UserRole)
+	repo.Save(&user)
+	otherUser := entity.NewUser("something else", "09900302023", entity.UserRole)
+	repo.Save(&otherUser)
+
+	_, err = useCase.ChangeMobileNumber(user.ID, "09900302023")
+	assert.ErrorIs(t, err, usecase.ErrMobileNumberTaken)
+
+	_, err = useCase.ChangeMobileNumber(user.ID, "+989900302020")
+	assert.ErrorIs(t, err, usecase.ErrMobileNumberUnchanged)
+
+	updatedUser, err := useCase.ChangeMobileNumber(user.ID, "+447911123456")
+	assert.NoError(t, err)
+	assert.Equal(t, "+447911123456", updatedUser.MobileNumber)
+	assert.Equal(t, "GB", updatedUser.Country)
+}
+
+func TestUserUseCase_AdminInvariants(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	repo := repository.NewUserRepository(db)
+	useCase := usecase.NewUserUseCase(repo)
+	admin := entity.NewUser("", "09120000001", entity.AdminRole)
+	repo.Save(&admin)
+
+	assert.ErrorIs(t, useCase.DeleteById(context.Background(), admin.ID), usecase.ErrLastAdmin)
+	assert.ErrorIs(t, useCase.CheckRoleChange(admin.ID, admin, entity.UserRole, false), usecase.ErrSelfDemotion)
+	assert.ErrorIs(t, useCase.CheckRoleChange(admin.ID, admin, entity.UserRole, true), usecase.ErrLastAdmin)
+	assert.NoError(t, useCase.CheckRoleChange(admin.ID, admin, entity.AdminRole, false))
+
+	// a suspended admin does not count as an active one
+	other := entity.NewUser("", "09120000002", entity.AdminRole)
+	suspendedAt := time.Now()
+	other.SuspendedAt = &suspendedAt
+	repo.Save(&other)
+	assert.ErrorIs(t, useCase.DeleteById(context.Background(), admin.ID), usecase.ErrLastAdmin)
+	assert.NoError(t, useCase.DeleteById(context.Background(), other.ID))
+
+	third := entity.NewUser("", "09120000003", entity.AdminRole)
+	repo.Save(&third)
+	assert.NoError(t, useCase.CheckRoleChange(admin.ID, admin, entity.UserRole, true))
+	assert.NoError(t, useCase.CheckRoleChange(third.ID, admin, entity.UserRole, false))
+	assert.NoError(t, useCase.DeleteById(context.Background(), admin.ID))
+	assert.ErrorIs(t, useCase.DeleteById(context.Background(), third.ID), usecase.ErrLastAdmin)
+}
+
+func TestUserUseCase_Restore(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	useCase := usecase.NewUserUseCase(repository.NewUserRepository(db))
+	user, _ := useCase.GetUserOrCreate("09001230541")
+	assert.NoError(t, useCase.DeleteById(context.Background(), user.ID))
+
+	registered, err := useCase.GetUserOrCreate("09001230541")
+	assert.NoError(t, err)
+	assert.NotEqual(t, user.ID, registered.ID)
+	_, err = useCase.Restore(context.Background(), user.ID)
+	assert.ErrorIs(t, err, usecase.ErrMobileNumberTaken)
+
+	assert.NoError(t, useCase.DeleteById(context.Background(), registered.ID))
+	restored, err := useCase.Restore(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, restored.ID)
+	assert.ErrorIs(t, useCase.Purge(context.Background(), user.ID), gorm.ErrRecordNotFound)
+	assert.NoError(t, useCase.Purge(context.Background(), registered.ID))
+}
+
+func TestUserUseCase_PurgeScrubsPersonalData(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	database.Migrate(db)
+	auditLogs := usecase.NewAuditLogUseCase(repository.NewAuditLogRepository(db))
+	useCase := usecase.NewUserUseCase(repository.NewUserRepository(db)).WithAudit(auditLogs)
+	ctx := context.TODO()
+
+	user := entity.NewUser("Jane Doe", "09120000002", entity.UserRole)
+	assert.NoError(t, useCase.Repo.Save(&user))
+	assert.NoError(t, useCase.Update(ctx, user.ID, map[string]any{"full_name": "Jane Roe"}))
+	loginEvent := entity.LoginEvent{UserID: user.ID, MobileNumber: user.MobileNumber, IP: "10.0.0.1", UserAgent: "curl"}
+	assert.NoError(t, db.Create(&loginEvent).Error)
+	assert.NoError(t, useCase.DeleteById(ctx, user.ID))
+	assert.NoError(t, useCase.Purge(ctx, user.ID))
+
+	assert.NoError(t, db.First(&loginEvent, loginEvent.ID).Error)
+	assert.Empty(t, loginEvent.MobileNumber+loginEvent.IP+loginEvent.UserAgent)
+	entries, err := auditLogs.ByTarget(ctx, "user", user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, entity.PurgeUserAction, entries[2].Action)
+	for _, entry := range entries {
+		for _, personal := range []string{"Jane", "0912000000"} {
+			assert.NotContains(t, entry.Changes+entry.Details, personal, entry.Action)
+		}
+	}
+}
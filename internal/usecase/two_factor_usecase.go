@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled = errors.New("two-factor authentication is not enrolled")
+	ErrTwoFactorRequired    = errors.New("two-factor code required")
+	ErrTwoFactorInvalid     = errors.New("invalid two-factor code")
+)
+
+type TwoFactorUseCase struct {
+	Repo repository.TwoFactorRepository
+}
+
+func NewTwoFactorUseCase(repo repository.TwoFactorRepository) TwoFactorUseCase {
+	return TwoFactorUseCase{Repo: repo}
+}
+
+func (u TwoFactorUseCase) ByUserId(ctx context.Context, userId uint) (entity.TwoFactor, error) {
+	twoFactor := entity.TwoFactor{}
+	err := u.Repo.ByUserId(ctx, userId, &twoFactor).Error
+	return twoFactor, err
+}
+
+// IsEnabled reports whether the user has a confirmed enrollment.
+func (u TwoFactorUseCase) IsEnabled(ctx context.Context, userId uint) (bool, error) {
+	twoFactor, err := u.ByUserId(ctx, userId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return twoFactor.IsConfirmed(), err
+}
+
+// Enroll starts a new enrollment, replacing an unconfirmed one, and returns
+// it with its plain text recovery codes.
+func (u TwoFactorUseCase) Enroll(ctx context.Context, userId uint) (entity.TwoFactor, []string, error) {
+	enabled, err := u.IsEnabled(ctx, userId)
+	if err != nil {
+		return entity.TwoFactor{}, nil, err
+	}
+	if enabled {
+		return entity.TwoFactor{}, nil, ErrTwoFactorEnabled
+	}
+	twoFactor, recoveryCodes, err := entity.NewTwoFactor(userId)
+	if err != nil {
+		return entity.TwoFactor{}, nil, err
+	}
+	err = u.Repo.Save(ctx, &twoFactor)
+	return twoFactor, recoveryCodes, err
+}
+
+// Confirm activates an enrollment with a first code from the authenticator.
+func (u TwoFactorUseCase) Confirm(ctx context.Context, userId uint, code string) error {
+	twoFactor, err := u.ByUserId(ctx, userId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTwoFactorNotEnrolled
+	}
+	if err != nil {
+		return err
+	}
+	if twoFactor.IsConfirmed() {
+		return ErrTwoFactorEnabled
+	}
+	now := time.Now()
+	step, ok := twoFactor.MatchTOTP(code, now)
+	if !ok {
+		return ErrTwoFactorInvalid
+	}
+	return u.Repo.Confirm(ctx, &twoFactor, step, now)
+}
+
+// Verify checks the second factor of a login, accepting either a TOTP code
+// or an unused recovery code. Codes are consumed on success.
+func (u TwoFactorUseCase) Verify(ctx context.Context, userId uint, totpCode, recoveryCode string) error {
+	twoFactor, err := u.ByUserId(ctx, userId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTwoFactorNotEnrolled
+	}
+	if err != nil {
+		return err
+	}
+	if !twoFactor.IsConfirmed() {
+		return ErrTwoFactorNotEnrolled
+	}
+	now := time.Now()
+	switch {
+	case totpCode != "":
+		step, ok := twoFactor.MatchTOTP(totpCode, now)
+		if !ok {
+			return ErrTwoFactorInvalid
+		}
+		return u.Repo.UpdateLastUsedStep(ctx, &twoFactor, step)
+	case recoveryCode != "":
+		code, ok := twoFactor.MatchRecoveryCode(recoveryCode)
+		if !ok {
+			return ErrTwoFactorInvalid
+		}
+		return u.Repo.UseRecoveryCode(ctx, &code, now)
+	default:
+		return ErrTwoFactorRequired
+	}
+}
+
+func (u TwoFactorUseCase) Reset(ctx context.Context, userId uint) error {
+	return u.Repo.DeleteByUserId(ctx, userId)
+}
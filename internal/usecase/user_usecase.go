@@ -1,21 +1,39 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
 	"github.com/TheAmirhosssein/room-reservation-api/internal/repository"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/validators"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrMobileNumberTaken     = errors.New("this mobile number is already in use")
+	ErrMobileNumberUnchanged = errors.New("new mobile number must be different from the current one")
+	ErrLastAdmin             = errors.New("at least one active admin must remain")
+	ErrSelfDemotion          = errors.New("removing your own admin role must be confirmed")
+	ErrUserAnonymized        = errors.New("anonymized users can not be restored")
+)
+
 type UserUseCase struct {
-	Repo repository.UserRepository
+	Repo  repository.UserRepository
+	Audit AuditHook
 }
 
 func NewUserUseCase(userRepo repository.UserRepository) UserUseCase {
 	return UserUseCase{Repo: userRepo}
 }
 
+// WithAudit returns a copy of the use case that reports its mutations to hook.
+func (u UserUseCase) WithAudit(hook AuditHook) UserUseCase {
+	u.Audit = hook
+	return u
+}
+
 func (u UserUseCase) GetUserOrCreate(mobileNumber string) (*entity.User, error) {
 	user := entity.NewUser("", mobileNumber, entity.UserRole)
 	u.Repo.ByMobileNumber(mobileNumber, &user)
@@ -28,6 +46,33 @@ func (u UserUseCase) GetUserOrCreate(mobileNumber string) (*entity.User, error)
 	return &user, nil
 }
 
+// MakeAdmin gives the user with mobileNumber the admin role, creating them
+// first when nobody owns the number. It reports whether a user was created.
+func (u UserUseCase) MakeAdmin(ctx context.Context, fullName, mobileNumber string) (entity.User, bool, error) {
+	number, err := validators.ParseMobileNumber(mobileNumber)
+	if err != nil {
+		return entity.User{}, false, err
+	}
+	user := entity.User{}
+	u.Repo.ByMobileNumber(number.E164(), &user)
+	if user.ID != 0 {
+		if user.Role == entity.AdminRole {
+			return user, false, nil
+		}
+		if err = u.Update(ctx, user.ID, map[string]any{"role": entity.AdminRole}); err != nil {
+			return entity.User{}, false, err
+		}
+		user, err = u.GetUserById(user.ID)
+		return user, false, err
+	}
+	user = entity.NewUser(fullName, number.E164(), entity.AdminRole)
+	user.Country = number.Region
+	if err = u.Repo.Save(&user); err != nil {
+		return entity.User{}, false, err
+	}
+	return user, true, audit(ctx, u.Audit, entity.CreateUserAction, "user", user.ID, nil, user)
+}
+
 func (u UserUseCase) DoesUserExist(id uint) bool {
 	user := new(entity.User)
 	err := u.Repo.ById(id, user).Error
@@ -40,25 +85,66 @@ func (u UserUseCase) GetUserById(id uint) (entity.User, error) {
 	return *user, query.Error
 }
 
-func (u UserUseCase) Update(id uint, newInfo map[string]any) error {
+func (u UserUseCase) Update(ctx context.Context, id uint, newInfo map[string]any) error {
 	user, err := u.GetUserById(id)
 	if err != nil {
 		return err
 	}
-	err = u.Repo.Update(&user, newInfo)
-	return err
+	before := user
+	if err = u.Repo.Update(&user, newInfo); err != nil {
+		return err
+	}
+	after, err := u.GetUserById(id)
+	if err != nil {
+		return err
+	}
+	return audit(ctx, u.Audit, entity.UpdateUserAction, "user", id, before, after)
 }
 
-func (u UserUseCase) DeleteById(id uint) error {
+func (u UserUseCase) DeleteById(ctx context.Context, id uint) error {
 	user, err := u.GetUserById(id)
 	if err != nil {
 		return err
 	}
-	return u.Repo.Delete(&user).Error
+	if err = u.EnsureAdminRemains(user); err != nil {
+		return err
+	}
+	if err = u.Repo.Delete(&user).Error; err != nil {
+		return err
+	}
+	return audit(ctx, u.Audit, entity.DeleteUserAction, "user", id, user, nil)
+}
+
+// EnsureAdminRemains returns ErrLastAdmin when user is the only active admin,
+// so deleting, suspending or demoting them would leave nobody in charge.
+func (u UserUseCase) EnsureAdminRemains(user entity.User) error {
+	if user.Role != entity.AdminRole || user.IsSuspended(time.Now()) {
+		return nil
+	}
+	count, err := u.Repo.CountActiveByRole(entity.AdminRole)
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+// CheckRoleChange validates moving user to role on behalf of actorId. Admins
+// taking away their own admin role have to confirm it.
+func (u UserUseCase) CheckRoleChange(actorId uint, user entity.User, role string, confirmed bool) error {
+	if user.Role != entity.AdminRole || role == entity.AdminRole {
+		return nil
+	}
+	if user.ID == actorId && !confirmed {
+		return ErrSelfDemotion
+	}
+	return u.EnsureAdminRemains(user)
 }
 
-func (u UserUseCase) GetUsersList(pageNumber, pageSize int, mobileNumber, fullName string) ([]entity.User, error) {
-	_, query := u.Repo.UserList(mobileNumber, fullName)
+func (u UserUseCase) GetUsersList(pageNumber, pageSize int, mobileNumber, fullName, status string) ([]entity.User, error) {
+	_, query := u.Repo.UserList(mobileNumber, fullName, status)
 	if err := query.Error; err != nil {
 		return nil, err
 	}
@@ -70,3 +156,76 @@ func (u UserUseCase) GetUsersList(pageNumber, pageSize int, mobileNumber, fullNa
 func (u UserUseCase) Count() (int, error) {
 	return u.Repo.Count()
 }
+
+func (u UserUseCase) IsMobileNumberTaken(mobileNumber string) bool {
+	user := new(entity.User)
+	err := u.Repo.ByMobileNumber(mobileNumber, user).Error
+	return err == nil && user.ID != 0
+}
+
+// ChangeMobileNumber moves the user to a new mobile number after making sure
+// no other account owns it.
+func (u UserUseCase) ChangeMobileNumber(id uint, mobileNumber string) (entity.User, error) {
+	user, err := u.GetUserById(id)
+	if err != nil {
+		return entity.User{}, err
+	}
+	number, err := validators.ParseMobileNumber(mobileNumber)
+	if err != nil {
+		return entity.User{}, err
+	}
+	if number.E164() == user.MobileNumber {
+		return entity.User{}, ErrMobileNumberUnchanged
+	}
+	if u.IsMobileNumberTaken(number.E164()) {
+		return entity.User{}, ErrMobileNumberTaken
+	}
+	newInfo := map[string]any{"mobile_number": number.E164(), "country": number.Region}
+	if err = u.Repo.Update(&user, newInfo); err != nil {
+		if u.IsMobileNumberTaken(number.E164()) {
+			return entity.User{}, ErrMobileNumberTaken
+		}
+		return entity.User{}, err
+	}
+	return u.GetUserById(id)
+}
+
+func (u UserUseCase) Trashed(pageNumber, pageSize int) ([]entity.User, int, error) {
+	return u.Repo.Trashed(pageSize, (pageNumber-1)*pageSize)
+}
+
+// Restore brings a deleted user back, unless someone registered their mobile
+// number in the meantime or the account was anonymized.
+func (u UserUseCase) Restore(ctx context.Context, id uint) (entity.User, error) {
+	user := entity.User{}
+	if err := u.Repo.TrashedById(id, &user).Error; err != nil {
+		return entity.User{}, err
+	}
+	if user.AnonymizedAt != nil {
+		return entity.User{}, ErrUserAnonymized
+	}
+	if u.IsMobileNumberTaken(user.MobileNumber) {
+		return entity.User{}, ErrMobileNumberTaken
+	}
+	if err := u.Repo.Restore(&user); err != nil {
+		return entity.User{}, err
+	}
+	restored, err := u.GetUserById(id)
+	if err != nil {
+		return entity.User{}, err
+	}
+	return restored, audit(ctx, u.Audit, entity.RestoreUserAction, "user", id, nil, restored)
+}
+
+// Purge permanently removes a user that is already in the trash.
+func (u UserUseCase) Purge(ctx context.Context, id uint) error {
+	user := entity.User{}
+	if err := u.Repo.TrashedById(id, &user).Error; err != nil {
+		return err
+	}
+	if err := u.Repo.Purge(ctx, &user); err != nil {
+		return err
+	}
+	// only the ID is recorded, the purged user's data must not outlive them
+	return audit(ctx, u.Audit, entity.PurgeUserAction, "user", id, nil, nil)
+}
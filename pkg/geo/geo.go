@@ -0,0 +1,134 @@
+// Package geo does the little spherical geometry the API needs: validating
+// coordinates and GeoJSON boundaries and measuring distances on the earth.
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// EarthRadius is the mean radius of the earth in kilometers.
+const EarthRadius = 6371.0
+
+var (
+	ErrInvalidPoint    = errors.New("latitude must be between -90 and 90 and longitude between -180 and 180")
+	ErrInvalidBoundary = errors.New("boundary must be a GeoJSON Polygon or MultiPolygon")
+)
+
+// ValidatePoint checks that lat and lng are coordinates on the earth.
+func ValidatePoint(lat, lng float64) error {
+	if math.IsNaN(lat) || math.IsNaN(lng) || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return ErrInvalidPoint
+	}
+	return nil
+}
+
+// Haversine returns the great-circle distance in kilometers between two
+// points given in degrees.
+func Haversine(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// Box is a range of latitudes and longitudes in degrees. A box without
+// longitude bounds spans every longitude.
+type Box struct {
+	MinLat, MaxLat float64
+	MinLng, MaxLng float64
+	AllLongitudes  bool
+}
+
+// BoundingBox returns a box containing every point within radius kilometers
+// of the given point. It only needs comparisons to query, so the database
+// can narrow the candidates down before their exact distance is computed.
+func BoundingBox(lat, lng, radius float64) Box {
+	delta := radius / EarthRadius * 180 / math.Pi
+	box := Box{MinLat: math.Max(-90, lat-delta), MaxLat: math.Min(90, lat+delta)}
+	if box.MinLat == -90 || box.MaxLat == 90 {
+		box.AllLongitudes = true
+		return box
+	}
+	lngDelta := math.Asin(math.Min(1, math.Sin(radians(delta))/math.Cos(radians(lat)))) * 180 / math.Pi
+	box.MinLng, box.MaxLng = lng-lngDelta, lng+lngDelta
+	// the box would wrap around the antimeridian
+	if box.MinLng < -180 || box.MaxLng > 180 {
+		box.AllLongitudes = true
+	}
+	return box
+}
+
+type geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// NormalizeBoundary validates a GeoJSON Polygon or MultiPolygon geometry and
+// returns it in compact form. Each ring needs at least four positions, the
+// last one repeating the first.
+func NormalizeBoundary(boundary []byte) (string, error) {
+	shape := geometry{}
+	if err := json.Unmarshal(boundary, &shape); err != nil {
+		return "", ErrInvalidBoundary
+	}
+	var polygons [][][][]float64
+	switch shape.Type {
+	case "Polygon":
+		var polygon [][][]float64
+		if err := json.Unmarshal(shape.Coordinates, &polygon); err != nil {
+			return "", ErrInvalidBoundary
+		}
+		polygons = [][][][]float64{polygon}
+	case "MultiPolygon":
+		if err := json.Unmarshal(shape.Coordinates, &polygons); err != nil {
+			return "", ErrInvalidBoundary
+		}
+	default:
+		return "", ErrInvalidBoundary
+	}
+	if len(polygons) == 0 {
+		return "", ErrInvalidBoundary
+	}
+	for _, polygon := range polygons {
+		if len(polygon) == 0 {
+			return "", ErrInvalidBoundary
+		}
+		for _, ring := range polygon {
+			if err := validateRing(ring); err != nil {
+				return "", err
+			}
+		}
+	}
+	normalized, err := json.Marshal(geometry{Type: shape.Type, Coordinates: shape.Coordinates})
+	if err != nil {
+		return "", err
+	}
+	return string(normalized), nil
+}
+
+func validateRing(ring [][]float64) error {
+	if len(ring) < 4 {
+		return fmt.Errorf("%w: a ring needs at least 4 positions", ErrInvalidBoundary)
+	}
+	for _, position := range ring {
+		if len(position) < 2 {
+			return fmt.Errorf("%w: positions are [longitude, latitude]", ErrInvalidBoundary)
+		}
+		if err := ValidatePoint(position[1], position[0]); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidBoundary, err)
+		}
+	}
+	first, last := ring[0], ring[len(ring)-1]
+	if first[0] != last[0] || first[1] != last[1] {
+		return fmt.Errorf("%w: a ring has to end where it starts", ErrInvalidBoundary)
+	}
+	return nil
+}
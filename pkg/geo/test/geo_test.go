@@ -0,0 +1,53 @@
+package geo_test
+
+import (
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHaversine(t *testing.T) {
+	// Tehran to Qom
+	assert.InDelta(t, 125.4, geo.Haversine(35.6892, 51.3890, 34.6401, 50.8764), 1)
+	assert.Zero(t, geo.Haversine(35.6892, 51.3890, 35.6892, 51.3890))
+	assert.InDelta(t, 20015, geo.Haversine(0, 0, 0, 180), 1)
+}
+
+func TestBoundingBox(t *testing.T) {
+	box := geo.BoundingBox(35.6892, 51.3890, 130)
+	assert.False(t, box.AllLongitudes)
+	assert.True(t, box.MinLat < 34.6401 && 34.6401 < box.MaxLat)
+	assert.True(t, box.MinLng < 50.8764 && 50.8764 < box.MaxLng)
+	assert.True(t, box.MaxLat < 37)
+
+	assert.True(t, geo.BoundingBox(89.5, 0, 100).AllLongitudes)
+	assert.True(t, geo.BoundingBox(0, 179.9, 100).AllLongitudes)
+}
+
+func TestValidatePoint(t *testing.T) {
+	assert.NoError(t, geo.ValidatePoint(-90, 180))
+	assert.ErrorIs(t, geo.ValidatePoint(91, 0), geo.ErrInvalidPoint)
+	assert.ErrorIs(t, geo.ValidatePoint(0, -181), geo.ErrInvalidPoint)
+}
+
+func TestNormalizeBoundary(t *testing.T) {
+	boundary, err := geo.NormalizeBoundary([]byte(`{"type": "Polygon", "coordinates": [[[51, 35], [52, 35], [52, 36], [51, 35]]]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"Polygon","coordinates":[[[51,35],[52,35],[52,36],[51,35]]]}`, boundary)
+
+	_, err = geo.NormalizeBoundary([]byte(`{"type": "MultiPolygon", "coordinates": [[[[51, 35], [52, 35], [52, 36], [51, 35]]]]}`))
+	assert.NoError(t, err)
+
+	for _, invalid := range []string{
+		`{"type": "Point", "coordinates": [51, 35]}`,
+		`{"type": "Polygon", "coordinates": [[[51, 35], [52, 35], [51, 35]]]}`,
+		`{"type": "Polygon", "coordinates": [[[51, 35], [52, 35], [52, 36], [51, 36]]]}`,
+		`{"type": "Polygon", "coordinates": [[[51, 95], [52, 35], [52, 36], [51, 95]]]}`,
+		`{"type": "Polygon", "coordinates": []}`,
+		`not json`,
+	} {
+		_, err = geo.NormalizeBoundary([]byte(invalid))
+		assert.ErrorIs(t, err, geo.ErrInvalidBoundary, invalid)
+	}
+}
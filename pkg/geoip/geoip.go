@@ -0,0 +1,52 @@
+// Package geoip maps IP addresses to countries using an embedded table of
+// address ranges. The table is coarse on purpose: it only has to tell apart
+// the countries our users usually log in from.
+package geoip
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/netip"
+)
+
+type ipRange struct {
+	CIDR    string `json:"cidr"`
+	Country string `json:"country"`
+}
+
+type prefixCountry struct {
+	prefix  netip.Prefix
+	country string
+}
+
+//go:embed ranges.json
+var rawRanges []byte
+
+var prefixes []prefixCountry
+
+func init() {
+	var ranges []ipRange
+	if err := json.Unmarshal(rawRanges, &ranges); err != nil {
+		panic(err)
+	}
+	for _, ipRange := range ranges {
+		prefixes = append(prefixes, prefixCountry{prefix: netip.MustParsePrefix(ipRange.CIDR), country: ipRange.Country})
+	}
+}
+
+// Country returns the country code of the most specific range containing
+// ip, or false when the address is invalid or not covered by the table.
+func Country(ip string) (string, bool) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return "", false
+	}
+	addr = addr.Unmap()
+	country, bits := "", -1
+	for _, candidate := range prefixes {
+		if candidate.prefix.Bits() > bits && candidate.prefix.Contains(addr) {
+			country, bits = candidate.country, candidate.prefix.Bits()
+		}
+	}
+	return country, bits >= 0
+}
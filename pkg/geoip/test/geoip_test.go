@@ -0,0 +1,31 @@
+package geoip_test
+
+import (
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/geoip"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountry(t *testing.T) {
+	tests := []struct {
+		ip      string
+		country string
+		found   bool
+	}{
+		{ip: "2.180.10.1", country: "IR", found: true},
+		{ip: "::ffff:2.180.10.1", country: "IR", found: true},
+		{ip: "86.130.0.1", country: "GB", found: true},
+		{ip: "8.8.8.8", country: "US", found: true},
+		{ip: "127.0.0.1", found: false},
+		{ip: "not an ip", found: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.ip, func(t *testing.T) {
+			country, found := geoip.Country(test.ip)
+			assert.Equal(t, test.found, found)
+			assert.Equal(t, test.country, country)
+		})
+	}
+}
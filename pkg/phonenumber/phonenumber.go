@@ -0,0 +1,191 @@
+package phonenumber
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+type NumberType string
+
+const (
+	Mobile            NumberType = "mobile"
+	FixedLine         NumberType = "fixed_line"
+	FixedLineOrMobile NumberType = "fixed_line_or_mobile"
+)
+
+var (
+	ErrInvalidNumber = errors.New("phone number format is not valid")
+	ErrUnknownRegion = errors.New("phone number region is not supported")
+)
+
+type (
+	numberRule struct {
+		Prefixes []string `json:"prefixes"`
+		Lengths  []int    `json:"lengths"`
+	}
+
+	regionMetadata struct {
+		Region            string      `json:"region"`
+		CallingCode       string      `json:"calling_code"`
+		NationalPrefix    string      `json:"national_prefix"`
+		Mobile            *numberRule `json:"mobile"`
+		FixedLine         *numberRule `json:"fixed_line"`
+		FixedLineOrMobile *numberRule `json:"fixed_line_or_mobile"`
+	}
+
+	Number struct {
+		Region         string
+		CallingCode    string
+		NationalNumber string
+		Type           NumberType
+	}
+)
+
+//go:embed metadata.json
+var rawMetadata []byte
+
+var (
+	regions       []regionMetadata
+	regionsByCode = map[string]regionMetadata{}
+)
+
+func init() {
+	if err := json.Unmarshal(rawMetadata, &regions); err != nil {
+		panic(err)
+	}
+	for _, region := range regions {
+		regionsByCode[region.Region] = region
+	}
+}
+
+// Parse reads a phone number written either in international form (+ or 00
+// followed by the calling code) or in the national form of defaultRegion,
+// and validates it against the region's numbering rules.
+func Parse(raw, defaultRegion string) (Number, error) {
+	digits, international, err := clean(raw)
+	if err != nil {
+		return Number{}, err
+	}
+	if international {
+		return parseInternational(digits)
+	}
+
+	region, ok := regionsByCode[defaultRegion]
+	if !ok {
+		return Number{}, ErrUnknownRegion
+	}
+	if region.NationalPrefix != "" && strings.HasPrefix(digits, region.NationalPrefix) {
+		if number, err := region.parseNational(strings.TrimPrefix(digits, region.NationalPrefix)); err == nil {
+			return number, nil
+		}
+	}
+	if number, err := region.parseNational(digits); err == nil {
+		return number, nil
+	}
+	// numbers such as 989123456789 carry the calling code without a leading +
+	return parseInternational(digits)
+}
+
+// Regions returns the codes of all supported regions.
+func Regions() []string {
+	codes := make([]string, 0, len(regions))
+	for _, region := range regions {
+		codes = append(codes, region.Region)
+	}
+	return codes
+}
+
+// E164 formats the number as +<calling code><national number>.
+func (number Number) E164() string {
+	return "+" + number.CallingCode + number.NationalNumber
+}
+
+// CanReceiveSMS reports whether the number could be a mobile number.
+func (number Number) CanReceiveSMS() bool {
+	return number.Type == Mobile || number.Type == FixedLineOrMobile
+}
+
+func clean(raw string) (string, bool, error) {
+	raw = strings.TrimSpace(raw)
+	international := false
+	switch {
+	case strings.HasPrefix(raw, "+"):
+		raw, international = raw[1:], true
+	case strings.HasPrefix(raw, "00"):
+		raw, international = raw[2:], true
+	}
+
+	var builder strings.Builder
+	for _, char := range raw {
+		switch {
+		case char >= '0' && char <= '9':
+			builder.WriteRune(char)
+		case char == ' ' || char == '-' || char == '(' || char == ')' || char == '.':
+			continue
+		default:
+			return "", false, ErrInvalidNumber
+		}
+	}
+	if builder.Len() == 0 {
+		return "", false, ErrInvalidNumber
+	}
+	return builder.String(), international, nil
+}
+
+func parseInternational(digits string) (Number, error) {
+	for length := 1; length <= 3 && length < len(digits); length++ {
+		callingCode := digits[:length]
+		for _, region := range regions {
+			if region.CallingCode != callingCode {
+				continue
+			}
+			if number, err := region.parseNational(digits[length:]); err == nil {
+				return number, nil
+			}
+		}
+	}
+	return Number{}, ErrInvalidNumber
+}
+
+func (region regionMetadata) parseNational(nationalNumber string) (Number, error) {
+	rules := []struct {
+		rule       *numberRule
+		numberType NumberType
+	}{
+		{region.Mobile, Mobile},
+		{region.FixedLineOrMobile, FixedLineOrMobile},
+		{region.FixedLine, FixedLine},
+	}
+	for _, candidate := range rules {
+		if candidate.rule != nil && candidate.rule.matches(nationalNumber) {
+			return Number{
+				Region:         region.Region,
+				CallingCode:    region.CallingCode,
+				NationalNumber: nationalNumber,
+				Type:           candidate.numberType,
+			}, nil
+		}
+	}
+	return Number{}, ErrInvalidNumber
+}
+
+func (rule numberRule) matches(nationalNumber string) bool {
+	validLength := false
+	for _, length := range rule.Lengths {
+		if len(nationalNumber) == length {
+			validLength = true
+			break
+		}
+	}
+	if !validLength {
+		return false
+	}
+	for _, prefix := range rule.Prefixes {
+		if strings.HasPrefix(nationalNumber, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package phonenumber_test
+
+import (
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/phonenumber"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw           string
+		defaultRegion string
+		region        string
+		e164          string
+		numberType    phonenumber.NumberType
+	}{
+		{raw: "09123456789", defaultRegion: "IR", region: "IR", e164: "+989123456789", numberType: phonenumber.Mobile},
+		{raw: "989123456789", defaultRegion: "IR", region: "IR", e164: "+989123456789", numberType: phonenumber.Mobile},
+		{raw: "02188776655", defaultRegion: "IR", region: "IR", e164: "+982188776655", numberType: phonenumber.FixedLine},
+		{raw: "07911 123456", defaultRegion: "GB", region: "GB", e164: "+447911123456", numberType: phonenumber.Mobile},
+		{raw: "+447911123456", defaultRegion: "IR", region: "GB", e164: "+447911123456", numberType: phonenumber.Mobile},
+		{raw: "+1 202 555 0123", defaultRegion: "IR", region: "US", e164: "+12025550123", numberType: phonenumber.FixedLineOrMobile},
+		{raw: "+7 912 345 67 89", defaultRegion: "IR", region: "RU", e164: "+79123456789", numberType: phonenumber.Mobile},
+		{raw: "00964 770 123 4567", defaultRegion: "IR", region: "IQ", e164: "+9647701234567", numberType: phonenumber.Mobile},
+	}
+
+	for _, test := range tests {
+		t.Run(test.raw, func(t *testing.T) {
+			number, err := phonenumber.Parse(test.raw, test.defaultRegion)
+			assert.NoError(t, err)
+			assert.Equal(t, test.region, number.Region)
+			assert.Equal(t, test.e164, number.E164())
+			assert.Equal(t, test.numberType, number.Type)
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	invalidNumbers := []string{"", "+", "abc", "0912345678", "+44791112345678", "+999123456789", "0912-345-678x"}
+	for _, raw := range invalidNumbers {
+		_, err := phonenumber.Parse(raw, "IR")
+		assert.ErrorIs(t, err, phonenumber.ErrInvalidNumber, "Expected %q to be invalid", raw)
+	}
+
+	_, err := phonenumber.Parse("09123456789", "XX")
+	assert.ErrorIs(t, err, phonenumber.ErrUnknownRegion)
+}
+
+func TestCanReceiveSMS(t *testing.T) {
+	mobile, _ := phonenumber.Parse("09123456789", "IR")
+	assert.True(t, mobile.CanReceiveSMS())
+
+	landline, _ := phonenumber.Parse("02188776655", "IR")
+	assert.False(t, landline.CanReceiveSMS())
+
+	assert.Contains(t, phonenumber.Regions(), "IR")
+}
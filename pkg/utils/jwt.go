@@ -4,47 +4,77 @@ import (
 	"errors"
 	"time"
 
-	"github.com/TheAmirhosssein/room-reservation-api/config"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func getSecretKey() (string, error) {
-	if config.InTestMode() {
-		return "secretestkey", nil
-	}
-
-	conf, err := config.NewConfig()
-	if err != nil {
-		return "", err
-	}
-
-	return conf.APP.SecretKey, nil
-}
-
-func GenerateAccessToken(userId uint, mobileNumber, role string) (string, error) {
+// GenerateAccessToken signs a user token with secretKey, the application's
+// config.APP.SecretKey, like every other token here.
+func GenerateAccessToken(secretKey string, userId uint, mobileNumber, role string) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"userId":       userId,
 		"mobileNumber": mobileNumber,
 		"role":         role,
 		"exp":          time.Now().Add(time.Hour * (24 * 365)).Unix(),
 	})
-	secretKey, err := getSecretKey()
-	if err != nil {
-		return "", err
+	return token.SignedString([]byte(secretKey))
+}
+
+// GenerateTwoFactorPendingToken signs a short-lived token for a user who
+// has to enroll in two-factor authentication before getting full access.
+func GenerateTwoFactorPendingToken(secretKey string, userId uint, mobileNumber, role string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"userId":           userId,
+		"mobileNumber":     mobileNumber,
+		"role":             role,
+		"twoFactorPending": true,
+		"exp":              time.Now().Add(time.Hour).Unix(),
+	})
+	return token.SignedString([]byte(secretKey))
+}
+
+// GenerateOAuthAccessToken signs an access token issued through the OAuth2
+// endpoints. Tokens issued on behalf of a user carry the same user claims as
+// GenerateAccessToken; client credential tokens have userId 0 and no user
+// claims.
+func GenerateOAuthAccessToken(secretKey, jti, clientId, scope string, userId uint, mobileNumber, role string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"jti":       jti,
+		"client_id": clientId,
+		"scope":     scope,
+		"iat":       now.Unix(),
+		"exp":       now.Add(ttl).Unix(),
+	}
+	if userId != 0 {
+		claims["userId"] = userId
+		claims["mobileNumber"] = mobileNumber
+		claims["role"] = role
 	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(secretKey))
 }
 
-func ValidateToken(token string) (map[string]any, error) {
+// GenerateImpersonationToken signs a token that authenticates as the subject
+// user while naming the staff member behind it. It is only valid together
+// with the impersonation session it refers to.
+func GenerateImpersonationToken(secretKey string, impersonationId, actorId, userId uint, mobileNumber, role string, expiresAt time.Time) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"userId":          userId,
+		"mobileNumber":    mobileNumber,
+		"role":            role,
+		"actorId":         actorId,
+		"impersonationId": impersonationId,
+		"exp":             expiresAt.Unix(),
+	})
+	return token.SignedString([]byte(secretKey))
+}
+
+func ValidateToken(secretKey, token string) (map[string]any, error) {
 	paredToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
 		_, ok := token.Method.(*jwt.SigningMethodHMAC)
 		if !ok {
 			return nil, errors.New("invalid type")
 		}
-		secretKey, err := getSecretKey()
-		if err != nil {
-			return nil, err
-		}
 		return []byte(secretKey), nil
 	})
 	if err != nil {
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NegotiateLocale picks the locale of a response: lang when it is supported,
+// otherwise the most preferred supported language of an Accept-Language
+// header, and fallback when neither names one. Region subtags are ignored,
+// so en-US matches en.
+func NegotiateLocale(lang, acceptLanguage string, supported []string, fallback string) string {
+	if locale, ok := matchLocale(lang, supported); ok {
+		return locale
+	}
+	type preference struct {
+		tag     string
+		quality float64
+	}
+	preferences := []preference{}
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if tag != "" && quality > 0 {
+			preferences = append(preferences, preference{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(preferences, func(i, j int) bool { return preferences[i].quality > preferences[j].quality })
+	for _, preference := range preferences {
+		if locale, ok := matchLocale(preference.tag, supported); ok {
+			return locale
+		}
+	}
+	return fallback
+}
+
+func matchLocale(tag string, supported []string) (string, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	for _, locale := range supported {
+		if locale == base {
+			return locale, true
+		}
+	}
+	return "", false
+}
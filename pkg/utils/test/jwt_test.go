@@ -10,8 +10,9 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+const secretKey = "secretestkey"
+
 func createTokenWithDifferentSigningMethod() string {
-	secretKey := "secretestkey"
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
 		"user": "testUser",
 	})
@@ -25,24 +26,43 @@ func createInvalidToken() (string, error) {
 		"mobileNumber": "mobileNumber",
 		"exp":          time.Now(),
 	})
-	secretKey := "secretestkey"
 	return token.SignedString([]byte(secretKey))
 }
 
 func TestTokenValidation(t *testing.T) {
 	tokenWithDifferentSigningMethod := createTokenWithDifferentSigningMethod()
-	_, err := utils.ValidateToken(tokenWithDifferentSigningMethod)
+	_, err := utils.ValidateToken(secretKey, tokenWithDifferentSigningMethod)
 	assert.Error(t, err)
 
 	invalidToken, err := createInvalidToken()
 	assert.NoError(t, err)
 
-	_, err = utils.ValidateToken(invalidToken)
+	_, err = utils.ValidateToken(secretKey, invalidToken)
 	assert.Error(t, err)
 
-	_, err = utils.ValidateToken("invalidToken")
+	_, err = utils.ValidateToken(secretKey, "invalidToken")
 	assert.Error(t, err)
 
-	_, err = utils.GenerateAccessToken(1, "something", entity.UserRole)
+	_, err = utils.GenerateAccessToken(secretKey, 1, "something", entity.UserRole)
+	assert.NoError(t, err)
+}
+
+func TestGenerateOAuthAccessToken(t *testing.T) {
+	token, err := utils.GenerateOAuthAccessToken(secretKey, "jti", "client", entity.ProfileScope, 1, "+989120000001", entity.UserRole, time.Hour)
+	assert.NoError(t, err)
+	claims, err := utils.ValidateToken(secretKey, token)
+	assert.NoError(t, err)
+	assert.Equal(t, "client", claims["client_id"])
+	assert.Equal(t, entity.ProfileScope, claims["scope"])
+	assert.Equal(t, float64(1), claims["userId"])
+
+	token, err = utils.GenerateOAuthAccessToken(secretKey, "jti", "client", entity.UsersReadPermission, 0, "", "", time.Hour)
+	assert.NoError(t, err)
+	claims, err = utils.ValidateToken(secretKey, token)
 	assert.NoError(t, err)
+	assert.NotContains(t, claims, "userId")
+
+	token, _ = utils.GenerateOAuthAccessToken(secretKey, "jti", "client", entity.ProfileScope, 1, "", "", -time.Minute)
+	_, err = utils.ValidateToken(secretKey, token)
+	assert.Error(t, err)
 }
@@ -0,0 +1,18 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	supported := []string{"fa", "en", "ar"}
+	assert.Equal(t, "en", utils.NegotiateLocale("en", "ar", supported, "fa"))
+	assert.Equal(t, "ar", utils.NegotiateLocale("de", "ar", supported, "fa"))
+	assert.Equal(t, "en", utils.NegotiateLocale("", "de-DE, en-US;q=0.8, ar;q=0.5", supported, "fa"))
+	assert.Equal(t, "ar", utils.NegotiateLocale("", "en;q=0.2, ar;q=0.9", supported, "fa"))
+	assert.Equal(t, "fa", utils.NegotiateLocale("", "en;q=0, de", supported, "fa"))
+	assert.Equal(t, "fa", utils.NegotiateLocale("", "", supported, "fa"))
+}
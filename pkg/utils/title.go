@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+var titleReplacer = strings.NewReplacer(
+	"ي", "ی", "ى", "ی", "ك", "ک", "ة", "ه", "ۀ", "ه", "أ", "ا", "إ", "ا", "آ", "ا",
+	"‌", " ", "‏", "", "‎", "", "ـ", "",
+)
+
+// FoldTitle reduces a title to the form used to compare it with others. Case,
+// Arabic and Persian forms of the same letter, zero-width non-joiners and
+// repeated spaces make no difference.
+func FoldTitle(title string) string {
+	folded := titleReplacer.Replace(strings.ToLower(title))
+	folded = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, folded)
+	return strings.Join(strings.Fields(folded), " ")
+}
@@ -34,24 +34,59 @@ func TestValidateMobileNumber(t *testing.T) {
 	}
 }
 
-func TestIsRoleValid(t *testing.T) {
+func TestNormalizeMobileNumber(t *testing.T) {
+	forms := []string{
+		"09123456789",
+		"9123456789",
+		"989123456789",
+		"+989123456789",
+		"00989123456789",
+		" 09123456789 ",
+	}
+
+	for _, num := range forms {
+		normalized, err := validators.NormalizeMobileNumber(num)
+		assert.NoError(t, err, "Expected %s to be normalized", num)
+		assert.Equal(t, "+989123456789", normalized)
+	}
+
+	_, err := validators.NormalizeMobileNumber("0912345678")
+	assert.ErrorIs(t, err, validators.ErrInvalidMobileNumber)
+}
+
+func TestMobileNumberSearchTerm(t *testing.T) {
+	assert.Equal(t, "+989123456789", validators.MobileNumberSearchTerm("09123456789"))
+	assert.Equal(t, "912345", validators.MobileNumberSearchTerm("0912345"))
+	assert.Equal(t, "98912", validators.MobileNumberSearchTerm("+98912"))
+	assert.Equal(t, "", validators.MobileNumberSearchTerm(""))
+}
+
+func TestParseMobileNumber(t *testing.T) {
 	tests := []struct {
-		role     string
-		expected bool
+		number   string
+		region   string
+		expected string
 	}{
-		{role: "Admin", expected: true},
-		{role: "Support", expected: true},
-		{role: "User", expected: true},
-		{role: "UnknownRole", expected: false},
-		{role: "admin", expected: false},
+		{number: "09123456789", region: "IR", expected: "+989123456789"},
+		{number: "+44 7911 123456", region: "GB", expected: "+447911123456"},
+		{number: "0049 151 23456789", region: "DE", expected: "+4915123456789"},
+		{number: "+1 (202) 555-0123", region: "US", expected: "+12025550123"},
+		{number: "+971501234567", region: "AE", expected: "+971501234567"},
+		{number: "+905321234567", region: "TR", expected: "+905321234567"},
 	}
 
 	for _, test := range tests {
-		t.Run(test.role, func(t *testing.T) {
-			result := validators.IsRoleValid(test.role)
-			if result != test.expected {
-				t.Errorf("For role %s, expected %v but got %v", test.role, test.expected, result)
-			}
+		t.Run(test.number, func(t *testing.T) {
+			number, err := validators.ParseMobileNumber(test.number)
+			assert.NoError(t, err)
+			assert.Equal(t, test.region, number.Region)
+			assert.Equal(t, test.expected, number.E164())
 		})
 	}
+
+	landlines := []string{"02112345678", "+442071234567", "+33142345678"}
+	for _, num := range landlines {
+		_, err := validators.ParseMobileNumber(num)
+		assert.ErrorIs(t, err, validators.ErrInvalidMobileNumber, "Expected landline %s to be rejected", num)
+	}
 }
@@ -1,21 +1,47 @@
 package validators
 
 import (
-	"regexp"
+	"errors"
+	"strings"
 
-	"github.com/TheAmirhosssein/room-reservation-api/internal/entity"
+	"github.com/TheAmirhosssein/room-reservation-api/pkg/phonenumber"
 )
 
+// DefaultRegion is used for numbers written without an international prefix.
+const DefaultRegion = "IR"
+
+var ErrInvalidMobileNumber = errors.New("mobile number format is not valid")
+
 func ValidateMobileNumber(mobileNumber string) bool {
-	re := regexp.MustCompile(`^(?:98|\+98|0098|0)?9[0-9]{9}$`)
-	return re.MatchString(mobileNumber)
+	_, err := ParseMobileNumber(mobileNumber)
+	return err == nil
+}
+
+// ParseMobileNumber parses a number in any supported country and makes sure
+// it can receive SMS messages.
+func ParseMobileNumber(mobileNumber string) (phonenumber.Number, error) {
+	number, err := phonenumber.Parse(mobileNumber, DefaultRegion)
+	if err != nil || !number.CanReceiveSMS() {
+		return phonenumber.Number{}, ErrInvalidMobileNumber
+	}
+	return number, nil
+}
+
+// NormalizeMobileNumber converts any accepted form of a mobile number to its
+// canonical E.164 form, e.g. 09123456789 becomes +989123456789.
+func NormalizeMobileNumber(mobileNumber string) (string, error) {
+	number, err := ParseMobileNumber(mobileNumber)
+	if err != nil {
+		return "", err
+	}
+	return number.E164(), nil
 }
 
-func IsRoleValid(role string) bool {
-	switch role {
-	case entity.AdminRole, entity.SupportRole, entity.UserRole:
-		return true
-	default:
-		return false
+// MobileNumberSearchTerm turns a possibly partial mobile number into a term
+// that can be matched against canonical numbers stored in the database.
+func MobileNumberSearchTerm(mobileNumber string) string {
+	if normalized, err := NormalizeMobileNumber(mobileNumber); err == nil {
+		return normalized
 	}
+	return strings.TrimLeft(strings.TrimSpace(mobileNumber), "+0")
 }